@@ -1,356 +1,793 @@
-package cache
-
-import (
-	"log"
-	"sync"
-	"time"
-
-	"discord-user-api/models"
-	"discord-user-api/websocket"
-)
-
-type CacheEntry struct {
-	Data      interface{}
-	Timestamp time.Time
-	TTL       time.Duration
-	Hits      int
-	LastRefresh time.Time
-	AutoRefresh bool
-	RefreshInterval time.Duration
-}
-
-type Cache struct {
-	data            map[string]*CacheEntry
-	mutex           sync.RWMutex
-	maxSize         int
-	defaultTTL      time.Duration
-	cleanupInterval time.Duration
-	stats           *CacheStats
-	stopCleanup     chan bool
-	wsManager       *websocket.WebSocketManager
-	refreshTicker   *time.Ticker
-	stopRefresh     chan bool
-}
-
-type CacheStats struct {
-	Hits        int64
-	Misses      int64
-	Evictions   int64
-	Size        int
-	LastCleanup time.Time
-	Refreshes   int64
-}
-
-func NewCache(maxSize int, defaultTTL, cleanupInterval time.Duration) *Cache {
-	cache := &Cache{
-		data:            make(map[string]*CacheEntry),
-		maxSize:         maxSize,
-		defaultTTL:      defaultTTL,
-		cleanupInterval: cleanupInterval,
-		stats:           &CacheStats{},
-		stopCleanup:     make(chan bool),
-		stopRefresh:     make(chan bool),
-	}
-
-	go cache.cleanupRoutine()
-
-	log.Printf("💾 Cache başlatıldı (Max: %d, TTL: %v, Cleanup: %v)", maxSize, defaultTTL, cleanupInterval)
-	return cache
-}
-
-func (c *Cache) SetWebSocketManager(wsManager *websocket.WebSocketManager) {
-	c.wsManager = wsManager
-	log.Printf("🔌 WebSocket Manager cache'e bağlandı")
-}
-
-func (c *Cache) Set(key string, value interface{}) {
-	c.SetWithTTL(key, value, c.defaultTTL)
-}
-
-func (c *Cache) SetWithTTL(key string, value interface{}, ttl time.Duration) {
-	c.SetWithAutoRefresh(key, value, ttl, false, 0)
-}
-
-func (c *Cache) SetWithAutoRefresh(key string, value interface{}, ttl time.Duration, autoRefresh bool, refreshInterval time.Duration) {
-	c.mutex.Lock()
-	defer c.mutex.Unlock()
-
-	if len(c.data) >= c.maxSize && c.data[key] == nil {
-		c.evictOldest()
-	}
-
-	now := time.Now()
-	c.data[key] = &CacheEntry{
-		Data:           value,
-		Timestamp:      now,
-		TTL:            ttl,
-		Hits:           0,
-		LastRefresh:    now,
-		AutoRefresh:    autoRefresh,
-		RefreshInterval: refreshInterval,
-	}
-
-	c.stats.Size = len(c.data)
-	
-	if c.wsManager != nil {
-		c.wsManager.Broadcast(models.WebSocketEvent{
-			Type:      "cache_update",
-			Data:      models.CacheUpdateEvent{
-				Type:      "set",
-				Key:       key,
-				Timestamp: now.Format(time.RFC3339),
-				Data:      value,
-			},
-			Timestamp: now.Format(time.RFC3339),
-		})
-	}
-
-	log.Printf("📥 Cache'e eklendi: %s (TTL: %v, AutoRefresh: %t)", key, ttl, autoRefresh)
-}
-
-func (c *Cache) Get(key string) (interface{}, bool) {
-	c.mutex.RLock()
-	entry, exists := c.data[key]
-	c.mutex.RUnlock()
-
-	if !exists {
-		c.stats.Misses++
-		log.Printf("❌ Cache miss: %s", key)
-		return nil, false
-	}
-
-	if time.Since(entry.Timestamp) > entry.TTL {
-		c.mutex.Lock()
-		delete(c.data, key)
-		c.stats.Size = len(c.data)
-		c.mutex.Unlock()
-		c.stats.Misses++
-		log.Printf("⏰ Cache expired: %s", key)
-		return nil, false
-	}
-
-	c.mutex.Lock()
-	entry.Hits++
-	c.mutex.Unlock()
-	c.stats.Hits++
-
-	log.Printf("📤 Cache hit: %s (Hits: %d)", key, entry.Hits)
-	return entry.Data, true
-}
-
-func (c *Cache) Refresh(key string) {
-	c.mutex.RLock()
-	entry, exists := c.data[key]
-	c.mutex.RUnlock()
-
-	if !exists {
-		log.Printf("⚠️ Refresh için öğe bulunamadı: %s", key)
-		return
-	}
-
-	if !entry.AutoRefresh {
-		log.Printf("⚠️ Öğe auto-refresh için yapılandırılmamış: %s", key)
-		return
-	}
-
-	entry.LastRefresh = time.Now()
-	c.stats.Refreshes++
-
-	if c.wsManager != nil {
-		c.wsManager.Broadcast(models.WebSocketEvent{
-			Type:      "cache_refresh",
-			Data:      models.CacheUpdateEvent{
-				Type:      "refresh",
-				Key:       key,
-				Timestamp: time.Now().Format(time.RFC3339),
-				Data:      entry.Data,
-			},
-			Timestamp: time.Now().Format(time.RFC3339),
-		})
-	}
-
-	log.Printf("🔄 Cache öğesi yenilendi: %s", key)
-}
-
-func (c *Cache) StartAutoRefresh() {
-	if c.refreshTicker != nil {
-		return
-	}
-
-	c.refreshTicker = time.NewTicker(30 * time.Second)
-	go func() {
-		for {
-			select {
-			case <-c.refreshTicker.C:
-				c.checkAutoRefresh()
-			case <-c.stopRefresh:
-				return
-			}
-		}
-	}()
-
-	log.Printf("🔄 Auto-refresh sistemi başlatıldı")
-}
-
-func (c *Cache) StopAutoRefresh() {
-	if c.refreshTicker != nil {
-		c.refreshTicker.Stop()
-		c.stopRefresh <- true
-		c.refreshTicker = nil
-		log.Printf("🛑 Auto-refresh sistemi durduruldu")
-	}
-}
-
-func (c *Cache) checkAutoRefresh() {
-	c.mutex.RLock()
-	var toRefresh []string
-	now := time.Now()
-
-	for key, entry := range c.data {
-		if entry.AutoRefresh && entry.RefreshInterval > 0 {
-			if now.Sub(entry.LastRefresh) >= entry.RefreshInterval {
-				toRefresh = append(toRefresh, key)
-			}
-		}
-	}
-	c.mutex.RUnlock()
-
-	for _, key := range toRefresh {
-		c.Refresh(key)
-	}
-
-	if len(toRefresh) > 0 {
-		log.Printf("🔄 %d öğe otomatik yenilendi", len(toRefresh))
-	}
-}
-
-func (c *Cache) Delete(key string) bool {
-	c.mutex.Lock()
-	defer c.mutex.Unlock()
-
-	if _, exists := c.data[key]; exists {
-		delete(c.data, key)
-		c.stats.Size = len(c.data)
-		
-		if c.wsManager != nil {
-			c.wsManager.Broadcast(models.WebSocketEvent{
-				Type:      "cache_delete",
-				Data:      models.CacheUpdateEvent{
-					Type:      "delete",
-					Key:       key,
-					Timestamp: time.Now().Format(time.RFC3339),
-				},
-				Timestamp: time.Now().Format(time.RFC3339),
-			})
-		}
-
-		log.Printf("🗑️  Cache'den silindi: %s", key)
-		return true
-	}
-	return false
-}
-
-func (c *Cache) Clear() {
-	c.mutex.Lock()
-	defer c.mutex.Unlock()
-
-	count := len(c.data)
-	c.data = make(map[string]*CacheEntry)
-	c.stats.Size = 0
-	
-	if c.wsManager != nil {
-		c.wsManager.Broadcast(models.WebSocketEvent{
-			Type:      "cache_clear",
-			Data:      models.CacheUpdateEvent{
-				Type:      "clear",
-				Timestamp: time.Now().Format(time.RFC3339),
-			},
-			Timestamp: time.Now().Format(time.RFC3339),
-		})
-	}
-
-	log.Printf("🧹 Cache temizlendi: %d öğe silindi", count)
-}
-
-func (c *Cache) GetStats() *CacheStats {
-	c.mutex.RLock()
-	defer c.mutex.RUnlock()
-
-	stats := *c.stats
-	stats.Size = len(c.data)
-	return &stats
-}
-
-func (c *Cache) evictOldest() {
-	var oldestKey string
-	var oldestTime time.Time
-
-	for key, entry := range c.data {
-		if oldestKey == "" || entry.Timestamp.Before(oldestTime) {
-			oldestKey = key
-			oldestTime = entry.Timestamp
-		}
-	}
-
-	if oldestKey != "" {
-		delete(c.data, oldestKey)
-		c.stats.Evictions++
-		log.Printf("🚫 En eski öğe cache'den çıkarıldı: %s", oldestKey)
-	}
-}
-
-func (c *Cache) cleanupRoutine() {
-	ticker := time.NewTicker(c.cleanupInterval)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-ticker.C:
-			c.cleanup()
-		case <-c.stopCleanup:
-			log.Printf("🛑 Cache cleanup durduruldu")
-			return
-		}
-	}
-}
-
-func (c *Cache) cleanup() {
-	c.mutex.Lock()
-	defer c.mutex.Unlock()
-
-	now := time.Now()
-	removed := 0
-
-	for key, entry := range c.data {
-		if now.Sub(entry.Timestamp) > entry.TTL {
-			delete(c.data, key)
-			removed++
-		}
-	}
-
-	if removed > 0 {
-		c.stats.Size = len(c.data)
-		c.stats.LastCleanup = now
-		log.Printf("🧹 Cache cleanup: %d süresi dolmuş öğe silindi", removed)
-	}
-}
-
-func (c *Cache) Stop() {
-	close(c.stopCleanup)
-	c.StopAutoRefresh()
-}
-
-func (c *Cache) PrintStats() {
-	stats := c.GetStats()
-	hitRate := float64(0)
-	if stats.Hits+stats.Misses > 0 {
-		hitRate = float64(stats.Hits) / float64(stats.Hits+stats.Misses) * 100
-	}
-
-	log.Printf("📊 Cache İstatistikleri:")
-	log.Printf("   📈 Hit Rate: %.2f%% (%d/%d)", hitRate, stats.Hits, stats.Hits+stats.Misses)
-	log.Printf("   📦 Size: %d/%d", stats.Size, c.maxSize)
-	log.Printf("   🚫 Evictions: %d", stats.Evictions)
-	log.Printf("   🔄 Refreshes: %d", stats.Refreshes)
-	log.Printf("   🧹 Last Cleanup: %v", stats.LastCleanup.Format("15:04:05"))
-} 
\ No newline at end of file
+package cache
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"discord-user-api/models"
+	"discord-user-api/websocket"
+)
+
+var (
+	ErrNotFound          = errors.New("cache öğesi bulunamadı")
+	ErrNoLoader          = errors.New("cache öğesi için loader kayıtlı değil")
+	ErrRefreshInProgress = errors.New("cache öğesi zaten yenileniyor")
+)
+
+type Loader func(ctx context.Context) (interface{}, error)
+
+// CacheEntry fields are only changed while holding the lock of the shard
+// that owns the key.
+type CacheEntry struct {
+	Data            interface{}
+	Timestamp       time.Time
+	TTL             time.Duration
+	Hits            int
+	LastRefresh     time.Time
+	AutoRefresh     bool
+	RefreshInterval time.Duration
+	LastError       string
+	LastErrorAt     time.Time
+	RefreshFailures int
+	StaleTTL        time.Duration
+}
+
+type Lookup struct {
+	Value        interface{}
+	Age          time.Duration
+	Stale        bool
+	Revalidating bool
+}
+
+// Cache spreads keys over a power-of-two number of shards. Per-key work only
+// takes the owning shard's lock, and statistics and settings are atomics, so
+// requests for keys in different shards never wait on each other.
+type Cache struct {
+	store           Store
+	shards          atomic.Pointer[shardSet]
+	maxSize         int
+	defaultTTL      time.Duration
+	cleanupInterval time.Duration
+	stats           cacheCounters
+	wsManager       atomic.Pointer[websocket.WebSocketManager]
+
+	refreshConcurrency atomic.Int64
+	refreshTimeout     atomic.Int64
+	defaultStaleTTL    atomic.Int64
+
+	lifecycle     sync.Mutex
+	policyName    string
+	stopCleanup   chan bool
+	refreshTicker *time.Ticker
+	stopRefresh   chan bool
+}
+
+type CacheStats struct {
+	Hits          int64
+	Misses        int64
+	Evictions     int64
+	Size          int
+	LastCleanup   time.Time
+	Refreshes     int64
+	RefreshErrors int64
+	Loaders       int
+	StaleHits     int64
+	Revalidations int64
+	Store         string
+	StoreErrors   int64
+	Shards        int
+	Eviction      EvictionStats
+}
+
+func NewCache(maxSize int, defaultTTL, cleanupInterval time.Duration) *Cache {
+	return NewCacheWithStore(NewMemoryStore(), maxSize, defaultTTL, cleanupInterval)
+}
+
+func NewCacheWithStore(store Store, maxSize int, defaultTTL, cleanupInterval time.Duration) *Cache {
+	cache := &Cache{
+		store:           store,
+		maxSize:         maxSize,
+		defaultTTL:      defaultTTL,
+		cleanupInterval: cleanupInterval,
+		stopCleanup:     make(chan bool),
+		stopRefresh:     make(chan bool),
+	}
+	cache.refreshConcurrency.Store(4)
+	cache.refreshTimeout.Store(int64(30 * time.Second))
+
+	cache.buildShards(defaultShardCount(maxSize), EvictionLRU)
+	go cache.cleanupRoutine()
+
+	log.Printf("💾 Cache başlatıldı (Store: %s, Max: %d, Shard: %d, TTL: %v, Cleanup: %v)", store.Name(), maxSize, len(cache.shards.Load().shards), defaultTTL, cleanupInterval)
+	return cache
+}
+
+func (c *Cache) SetWebSocketManager(wsManager *websocket.WebSocketManager) {
+	c.wsManager.Store(wsManager)
+	log.Printf("🔌 WebSocket Manager cache'e bağlandı")
+}
+
+func (c *Cache) SetRefreshOptions(concurrency int, timeout time.Duration) {
+	if concurrency > 0 {
+		c.refreshConcurrency.Store(int64(concurrency))
+	}
+	if timeout > 0 {
+		c.refreshTimeout.Store(int64(timeout))
+	}
+}
+
+func (c *Cache) SetStaleTTL(staleTTL time.Duration) {
+	if staleTTL >= 0 {
+		c.defaultStaleTTL.Store(int64(staleTTL))
+	}
+}
+
+// SetEvictionPolicy switches to one of EvictionLRU, EvictionLFU,
+// EvictionFIFO or EvictionTinyLFU. Keys already in the store are tracked
+// again from scratch, so access history is lost.
+func (c *Cache) SetEvictionPolicy(name string) error {
+	policy, err := NewEvictionPolicy(name, c.maxSize)
+	if err != nil {
+		return err
+	}
+
+	c.lifecycle.Lock()
+	defer c.lifecycle.Unlock()
+
+	c.buildShards(len(c.shards.Load().shards), name)
+	log.Printf("🚫 Cache eviction politikası: %s", policy.Name())
+	return nil
+}
+
+// SetShards changes the number of shards; zero picks one from GOMAXPROCS.
+// Like SetEvictionPolicy it starts every shard from scratch, loaders
+// included, so call it during startup.
+func (c *Cache) SetShards(count int) {
+	if count <= 0 {
+		count = defaultShardCount(c.maxSize)
+	} else {
+		count = clampShardCount(count, c.maxSize)
+	}
+
+	c.lifecycle.Lock()
+	defer c.lifecycle.Unlock()
+
+	if count == len(c.shards.Load().shards) {
+		return
+	}
+	c.buildShards(count, c.policyName)
+	log.Printf("🧩 Cache shard sayısı: %d", count)
+}
+
+func (c *Cache) RegisterLoader(key string, loader Loader) {
+	shard := c.shard(key)
+	shard.mutex.Lock()
+	defer shard.mutex.Unlock()
+
+	if _, exists := c.load(key); !exists {
+		return
+	}
+	shard.loaders[key] = loader
+}
+
+func (c *Cache) Set(key string, value interface{}) {
+	c.SetWithTTL(key, value, c.defaultTTL)
+}
+
+func (c *Cache) SetWithTTL(key string, value interface{}, ttl time.Duration) {
+	c.SetWithAutoRefresh(key, value, ttl, false, 0)
+}
+
+func (c *Cache) SetWithAutoRefresh(key string, value interface{}, ttl time.Duration, autoRefresh bool, refreshInterval time.Duration) {
+	staleTTL := time.Duration(c.defaultStaleTTL.Load())
+	c.SetWithStaleTTL(key, value, ttl, staleTTL, autoRefresh, refreshInterval)
+}
+
+func (c *Cache) SetWithStaleTTL(key string, value interface{}, ttl, staleTTL time.Duration, autoRefresh bool, refreshInterval time.Duration) {
+	shard := c.shard(key)
+	shard.mutex.Lock()
+	defer shard.mutex.Unlock()
+
+	_, exists := c.load(key)
+	if !exists && !c.admit(shard, key) {
+		return
+	}
+
+	now := time.Now()
+	entry := &CacheEntry{
+		Data:            value,
+		Timestamp:       now,
+		TTL:             ttl,
+		Hits:            0,
+		LastRefresh:     now,
+		AutoRefresh:     autoRefresh,
+		RefreshInterval: refreshInterval,
+		StaleTTL:        staleTTL,
+	}
+	if !c.save(key, entry) {
+		shard.policy.Remove(key)
+		return
+	}
+	delete(shard.hits, key)
+
+	c.broadcast("cache_update", "set", key, value, now)
+	log.Printf("📥 Cache'e eklendi: %s (TTL: %v, AutoRefresh: %t)", key, ttl, autoRefresh)
+}
+
+func (c *Cache) Get(key string) (interface{}, bool) {
+	shard := c.shard(key)
+	shard.mutex.Lock()
+	defer shard.mutex.Unlock()
+
+	entry, exists := c.load(key)
+	if !exists {
+		delete(shard.hits, key)
+		shard.policy.Remove(key)
+		shard.policy.Miss(key)
+		c.stats.misses.Add(1)
+		log.Printf("❌ Cache miss: %s", key)
+		return nil, false
+	}
+
+	if age := time.Since(entry.Timestamp); age > entry.TTL {
+		if age > entry.TTL+entry.StaleTTL {
+			c.remove(shard, key)
+		}
+		shard.policy.Miss(key)
+		c.stats.misses.Add(1)
+		log.Printf("⏰ Cache expired: %s", key)
+		return nil, false
+	}
+
+	hits := shard.recordHit(key, entry)
+	shard.policy.Access(key)
+	c.stats.hits.Add(1)
+
+	log.Printf("📤 Cache hit: %s (Hits: %d)", key, hits)
+	return entry.Data, true
+}
+
+func (c *Cache) Lookup(key string) (Lookup, bool) {
+	shard := c.shard(key)
+	shard.mutex.Lock()
+	defer shard.mutex.Unlock()
+
+	entry, exists := c.load(key)
+	if !exists {
+		delete(shard.hits, key)
+		shard.policy.Remove(key)
+		shard.policy.Miss(key)
+		c.stats.misses.Add(1)
+		log.Printf("❌ Cache miss: %s", key)
+		return Lookup{}, false
+	}
+
+	age := time.Since(entry.Timestamp)
+	if age > entry.TTL+entry.StaleTTL {
+		c.remove(shard, key)
+		shard.policy.Miss(key)
+		c.stats.misses.Add(1)
+		log.Printf("⏰ Cache expired: %s", key)
+		return Lookup{}, false
+	}
+
+	hits := shard.recordHit(key, entry)
+	shard.policy.Access(key)
+	lookup := Lookup{Value: entry.Data, Age: age, Stale: age > entry.TTL}
+
+	if !lookup.Stale {
+		c.stats.hits.Add(1)
+		log.Printf("📤 Cache hit: %s (Hits: %d)", key, hits)
+		return lookup, true
+	}
+
+	c.stats.staleHits.Add(1)
+	if loader := shard.loaders[key]; loader != nil {
+		lookup.Revalidating = true
+		if !shard.refreshing[key] {
+			// Claimed before the goroutine starts so concurrent stale hits
+			// cannot start a second revalidation.
+			shard.refreshing[key] = true
+			c.stats.revalidations.Add(1)
+			go c.runRefresh(shard, key, entry, loader)
+		}
+	}
+
+	log.Printf("🕰️  Stale cache hit: %s (Yaş: %v, Yenileniyor: %t)", key, age.Round(time.Second), lookup.Revalidating)
+	return lookup, true
+}
+
+func (c *Cache) Update(key string, fn func(value interface{}) (interface{}, bool)) bool {
+	shard := c.shard(key)
+	shard.mutex.Lock()
+	defer shard.mutex.Unlock()
+
+	entry, exists := c.load(key)
+	if !exists || time.Since(entry.Timestamp) > entry.TTL+entry.StaleTTL {
+		return false
+	}
+
+	value, ok := fn(entry.Data)
+	if !ok {
+		return false
+	}
+
+	now := time.Now()
+	updated := &CacheEntry{
+		Data:            value,
+		Timestamp:       entry.Timestamp,
+		TTL:             entry.TTL,
+		Hits:            shard.hitCount(key, entry),
+		LastRefresh:     now,
+		AutoRefresh:     entry.AutoRefresh,
+		RefreshInterval: entry.RefreshInterval,
+		StaleTTL:        entry.StaleTTL,
+	}
+	if !c.save(key, updated) {
+		return false
+	}
+
+	c.broadcast("cache_update", "update", key, value, now)
+	log.Printf("✏️  Cache öğesi güncellendi: %s", key)
+	return true
+}
+
+func (c *Cache) Keys(prefix string) []string {
+	keys, err := c.store.Keys(prefix)
+	if err != nil {
+		c.stats.storeErrors.Add(1)
+		logStoreError(c.store, "keys", prefix, err)
+	}
+	return keys
+}
+
+func (c *Cache) Refresh(key string) error {
+	return c.refresh(key, true)
+}
+
+func (c *Cache) refresh(key string, requireAutoRefresh bool) error {
+	shard := c.shard(key)
+	shard.mutex.Lock()
+	entry, exists := c.load(key)
+	loader := shard.loaders[key]
+
+	if !exists {
+		shard.mutex.Unlock()
+		log.Printf("⚠️ Refresh için öğe bulunamadı: %s", key)
+		return ErrNotFound
+	}
+
+	if (requireAutoRefresh && !entry.AutoRefresh) || loader == nil {
+		shard.mutex.Unlock()
+		log.Printf("⚠️ Öğe auto-refresh için yapılandırılmamış: %s", key)
+		return ErrNoLoader
+	}
+
+	if shard.refreshing[key] {
+		shard.mutex.Unlock()
+		return ErrRefreshInProgress
+	}
+
+	shard.refreshing[key] = true
+	shard.mutex.Unlock()
+
+	return c.runRefresh(shard, key, entry, loader)
+}
+
+// runRefresh loads a new value for entry. The caller must have set
+// shard.refreshing[key]; runRefresh clears it when done.
+func (c *Cache) runRefresh(shard *cacheShard, key string, entry *CacheEntry, loader Loader) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(c.refreshTimeout.Load()))
+	value, err := loader(ctx)
+	cancel()
+
+	shard.mutex.Lock()
+	defer shard.mutex.Unlock()
+	delete(shard.refreshing, key)
+
+	current, exists := c.load(key)
+	if !exists {
+		return ErrNotFound
+	}
+
+	now := time.Now()
+
+	if err != nil {
+		current.LastError = err.Error()
+		current.LastErrorAt = now
+		current.RefreshFailures++
+		c.save(key, current)
+		c.stats.refreshErrors.Add(1)
+		log.Printf("❌ Cache öğesi yenilenemedi, eski veri korunuyor: %s (%v)", key, err)
+		return err
+	}
+
+	if !sameEntry(current, entry) {
+		log.Printf("⚠️ Cache öğesi yenileme sırasında değişti, sonuç atlandı: %s", key)
+		return nil
+	}
+
+	refreshed := &CacheEntry{
+		Data:            value,
+		Timestamp:       now,
+		TTL:             entry.TTL,
+		Hits:            shard.hitCount(key, current),
+		LastRefresh:     now,
+		AutoRefresh:     entry.AutoRefresh,
+		RefreshInterval: entry.RefreshInterval,
+		StaleTTL:        entry.StaleTTL,
+	}
+	if !c.save(key, refreshed) {
+		return ErrNotFound
+	}
+	c.stats.refreshes.Add(1)
+
+	c.broadcast("cache_refresh", "refresh", key, value, now)
+	log.Printf("🔄 Cache öğesi yenilendi: %s", key)
+	return nil
+}
+
+func (c *Cache) StartAutoRefresh() {
+	c.lifecycle.Lock()
+	defer c.lifecycle.Unlock()
+
+	if c.refreshTicker != nil {
+		return
+	}
+
+	ticker := time.NewTicker(30 * time.Second)
+	c.refreshTicker = ticker
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				c.checkAutoRefresh()
+			case <-c.stopRefresh:
+				return
+			}
+		}
+	}()
+
+	log.Printf("🔄 Auto-refresh sistemi başlatıldı")
+}
+
+func (c *Cache) StopAutoRefresh() {
+	c.lifecycle.Lock()
+	defer c.lifecycle.Unlock()
+
+	if c.refreshTicker != nil {
+		c.refreshTicker.Stop()
+		c.stopRefresh <- true
+		c.refreshTicker = nil
+		log.Printf("🛑 Auto-refresh sistemi durduruldu")
+	}
+}
+
+func (c *Cache) checkAutoRefresh() {
+	var toRefresh []string
+	now := time.Now()
+
+	// Only keys with a loader in this process can be refreshed, so there is
+	// no need to walk a shared store.
+	for _, shard := range c.shards.Load().shards {
+		shard.mutex.Lock()
+		for key := range shard.loaders {
+			if shard.refreshing[key] {
+				continue
+			}
+			entry, exists := c.load(key)
+			if exists && entry.AutoRefresh && entry.RefreshInterval > 0 {
+				if now.Sub(entry.LastRefresh) >= entry.RefreshInterval {
+					toRefresh = append(toRefresh, key)
+				}
+			}
+		}
+		shard.mutex.Unlock()
+	}
+
+	if len(toRefresh) == 0 {
+		return
+	}
+
+	var refreshed, failed atomic.Int64
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, c.refreshConcurrency.Load())
+
+	for _, key := range toRefresh {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(key string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if err := c.Refresh(key); err != nil {
+				failed.Add(1)
+				return
+			}
+			refreshed.Add(1)
+		}(key)
+	}
+	wg.Wait()
+
+	log.Printf("🔄 %d öğe otomatik yenilendi, %d başarısız", refreshed.Load(), failed.Load())
+}
+
+func (c *Cache) Delete(key string) bool {
+	shard := c.shard(key)
+	shard.mutex.Lock()
+	defer shard.mutex.Unlock()
+
+	if c.remove(shard, key) {
+		c.broadcast("cache_delete", "delete", key, nil, time.Now())
+		log.Printf("🗑️  Cache'den silindi: %s", key)
+		return true
+	}
+	return false
+}
+
+// Clear locks every shard, always in index order, so no Set lands between
+// wiping the store and resetting the policies.
+func (c *Cache) Clear() {
+	shards := c.shards.Load().shards
+	for _, shard := range shards {
+		shard.mutex.Lock()
+	}
+
+	count, err := c.store.Clear()
+	if err != nil {
+		c.stats.storeErrors.Add(1)
+		logStoreError(c.store, "clear", "*", err)
+	}
+	for _, shard := range shards {
+		shard.loaders = make(map[string]Loader)
+		shard.hits = make(map[string]int)
+		shard.policy.Reset()
+		shard.mutex.Unlock()
+	}
+
+	c.broadcast("cache_clear", "clear", "", nil, time.Now())
+	log.Printf("🧹 Cache temizlendi: %d öğe silindi", count)
+}
+
+func (c *Cache) GetStats() *CacheStats {
+	stats := &CacheStats{
+		Hits:          c.stats.hits.Load(),
+		Misses:        c.stats.misses.Load(),
+		Evictions:     c.stats.evictions.Load(),
+		LastCleanup:   c.stats.lastCleanupTime(),
+		Refreshes:     c.stats.refreshes.Load(),
+		RefreshErrors: c.stats.refreshErrors.Load(),
+		StaleHits:     c.stats.staleHits.Load(),
+		Revalidations: c.stats.revalidations.Load(),
+		Store:         c.store.Name(),
+	}
+
+	shards := c.shards.Load().shards
+	stats.Shards = len(shards)
+	for i, shard := range shards {
+		shard.mutex.Lock()
+		stats.Loaders += len(shard.loaders)
+		if i == 0 {
+			stats.Eviction = shard.policy.Stats()
+		} else {
+			stats.Eviction = mergeEvictionStats(stats.Eviction, shard.policy.Stats())
+		}
+		shard.mutex.Unlock()
+	}
+
+	stats.Size = c.size()
+	stats.StoreErrors = c.stats.storeErrors.Load()
+	return stats
+}
+
+// admit registers a new key with its shard's eviction policy and drops
+// whatever the policy evicts to make room. It returns false if the policy
+// rejected the key itself.
+func (c *Cache) admit(shard *cacheShard, key string) bool {
+	admitted := true
+	for _, victim := range shard.policy.Add(key) {
+		c.stats.evictions.Add(1)
+		if victim == key {
+			admitted = false
+			log.Printf("🚪 Öğe cache'e kabul edilmedi (%s): %s", shard.policy.Name(), key)
+			continue
+		}
+		c.remove(shard, victim)
+		log.Printf("🚫 Öğe cache'den çıkarıldı (%s): %s", shard.policy.Name(), victim)
+	}
+	return admitted
+}
+
+// buildShards replaces every shard, each with its slice of maxSize, and
+// hands keys that are already in the store (a disk cache from the previous
+// run, or a shared Redis) to the new policies.
+func (c *Cache) buildShards(count int, policyName string) {
+	set := &shardSet{
+		shards: make([]*cacheShard, count),
+		mask:   uint64(count - 1),
+	}
+	capacity := shardCapacity(c.maxSize, count)
+	for i := range set.shards {
+		policy, _ := NewEvictionPolicy(policyName, capacity)
+		set.shards[i] = newCacheShard(policy)
+	}
+
+	keys, err := c.store.Keys("")
+	if err != nil {
+		c.stats.storeErrors.Add(1)
+		logStoreError(c.store, "keys", "*", err)
+	}
+	for _, key := range keys {
+		shard := set.get(key)
+		shard.mutex.Lock()
+		c.admit(shard, key)
+		shard.mutex.Unlock()
+	}
+
+	c.policyName = policyName
+	c.shards.Store(set)
+}
+
+func (c *Cache) shard(key string) *cacheShard {
+	return c.shards.Load().get(key)
+}
+
+func (c *Cache) cleanupRoutine() {
+	ticker := time.NewTicker(c.cleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			c.cleanup()
+		case <-c.stopCleanup:
+			log.Printf("🛑 Cache cleanup durduruldu")
+			return
+		}
+	}
+}
+
+// cleanup scans the store without holding any shard lock, then checks each
+// expired key again under its shard's lock in case it was set meanwhile.
+func (c *Cache) cleanup() {
+	now := time.Now()
+	removed := 0
+
+	var expired []string
+	err := c.store.Range(func(key string, entry *CacheEntry) bool {
+		if now.Sub(entry.Timestamp) > entry.TTL+entry.StaleTTL {
+			expired = append(expired, key)
+		}
+		return true
+	})
+	if err != nil {
+		c.stats.storeErrors.Add(1)
+		logStoreError(c.store, "range", "*", err)
+	}
+
+	for _, key := range expired {
+		shard := c.shard(key)
+		shard.mutex.Lock()
+		if entry, exists := c.load(key); exists && now.Sub(entry.Timestamp) > entry.TTL+entry.StaleTTL {
+			if c.remove(shard, key) {
+				removed++
+			}
+		}
+		shard.mutex.Unlock()
+	}
+
+	if removed > 0 {
+		c.stats.lastCleanup.Store(now.UnixNano())
+		log.Printf("🧹 Cache cleanup: %d süresi dolmuş öğe silindi", removed)
+	}
+}
+
+func (c *Cache) Stop() {
+	close(c.stopCleanup)
+	c.StopAutoRefresh()
+	if err := c.store.Close(); err != nil {
+		logStoreError(c.store, "close", "*", err)
+	}
+}
+
+func (c *Cache) broadcast(eventType, updateType, key string, value interface{}, now time.Time) {
+	wsManager := c.wsManager.Load()
+	if wsManager == nil {
+		return
+	}
+
+	wsManager.Broadcast(models.WebSocketEvent{
+		Type: eventType,
+		Data: models.CacheUpdateEvent{
+			Type:      updateType,
+			Key:       key,
+			Timestamp: now.Format(time.RFC3339),
+			Data:      value,
+		},
+		Timestamp: now.Format(time.RFC3339),
+	})
+}
+
+// load, save and remove wrap the store for callers holding the key's shard
+// lock. Store failures are logged and counted, and reads degrade to a miss.
+func (c *Cache) load(key string) (*CacheEntry, bool) {
+	entry, exists, err := c.store.Load(key)
+	if err != nil {
+		c.stats.storeErrors.Add(1)
+		logStoreError(c.store, "load", key, err)
+		return nil, false
+	}
+	return entry, exists
+}
+
+func (c *Cache) save(key string, entry *CacheEntry) bool {
+	if err := c.store.Save(key, entry); err != nil {
+		c.stats.storeErrors.Add(1)
+		logStoreError(c.store, "save", key, err)
+		return false
+	}
+	return true
+}
+
+func (c *Cache) remove(shard *cacheShard, key string) bool {
+	delete(shard.loaders, key)
+	delete(shard.hits, key)
+	shard.policy.Remove(key)
+	removed, err := c.store.Delete(key)
+	if err != nil {
+		c.stats.storeErrors.Add(1)
+		logStoreError(c.store, "delete", key, err)
+	}
+	return removed
+}
+
+func (c *Cache) size() int {
+	size, err := c.store.Len()
+	if err != nil {
+		c.stats.storeErrors.Add(1)
+		logStoreError(c.store, "len", "*", err)
+	}
+	return size
+}
+
+// sameEntry reports whether current is still the entry a refresh started
+// from. Stores other than MemoryStore return a new copy on every load, so
+// pointer identity is not enough.
+func sameEntry(current, original *CacheEntry) bool {
+	return current == original || (current.Timestamp.Equal(original.Timestamp) && current.LastRefresh.Equal(original.LastRefresh))
+}
+
+// mergeEvictionStats adds one shard's policy statistics to the total;
+// MinFrequency is the lowest non-zero value across shards.
+func mergeEvictionStats(total, shard EvictionStats) EvictionStats {
+	total.Capacity += shard.Capacity
+	total.Tracked += shard.Tracked
+	total.Evictions += shard.Evictions
+	total.Admitted += shard.Admitted
+	total.Rejected += shard.Rejected
+	total.SketchResets += shard.SketchResets
+	if shard.MinFrequency > 0 && (total.MinFrequency == 0 || shard.MinFrequency < total.MinFrequency) {
+		total.MinFrequency = shard.MinFrequency
+	}
+	return total
+}
+
+func (c *Cache) PrintStats() {
+	stats := c.GetStats()
+	hitRate := float64(0)
+	if stats.Hits+stats.Misses > 0 {
+		hitRate = float64(stats.Hits) / float64(stats.Hits+stats.Misses) * 100
+	}
+
+	log.Printf("📊 Cache İstatistikleri:")
+	log.Printf("   📈 Hit Rate: %.2f%% (%d/%d)", hitRate, stats.Hits, stats.Hits+stats.Misses)
+	log.Printf("   📦 Size: %d/%d (%d shard)", stats.Size, c.maxSize, stats.Shards)
+	log.Printf("   🚫 Evictions: %d (%s)", stats.Evictions, stats.Eviction.Policy)
+	log.Printf("   🔄 Refreshes: %d (Hata: %d, Loader: %d)", stats.Refreshes, stats.RefreshErrors, stats.Loaders)
+	log.Printf("   🕰️  Stale Hits: %d (Revalidations: %d)", stats.StaleHits, stats.Revalidations)
+	log.Printf("   🧹 Last Cleanup: %v", stats.LastCleanup.Format("15:04:05"))
+}
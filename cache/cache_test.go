@@ -0,0 +1,225 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMain(m *testing.M) {
+	log.SetOutput(io.Discard)
+	os.Exit(m.Run())
+}
+
+// TestCacheConcurrentAccess is meant for -race: every public operation runs
+// against a small, overlapping key set with short TTLs, so evictions, expiry,
+// stale revalidation and cleanup all interleave.
+func TestCacheConcurrentAccess(t *testing.T) {
+	const (
+		maxSize    = 64
+		keyCount   = 160
+		workers    = 8
+		iterations = 3000
+	)
+
+	c := NewCache(maxSize, time.Minute, time.Hour)
+	defer c.Stop()
+	c.SetShards(4)
+	if err := c.SetEvictionPolicy(EvictionTinyLFU); err != nil {
+		t.Fatal(err)
+	}
+
+	loader := func(ctx context.Context) (interface{}, error) {
+		return 0, nil
+	}
+
+	var lookups atomic.Int64
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(seed int64) {
+			defer wg.Done()
+			random := rand.New(rand.NewSource(seed))
+
+			for i := 0; i < iterations; i++ {
+				key := fmt.Sprintf("key_%d", random.Intn(keyCount))
+				switch random.Intn(11) {
+				case 0, 1:
+					ttl := time.Duration(random.Intn(3)) * time.Millisecond
+					c.SetWithStaleTTL(key, i, ttl, time.Millisecond, random.Intn(2) == 0, time.Millisecond)
+				case 2:
+					c.Get(key)
+					lookups.Add(1)
+				case 3:
+					c.Lookup(key)
+					lookups.Add(1)
+				case 4:
+					c.Update(key, func(value interface{}) (interface{}, bool) {
+						n, ok := value.(int)
+						return n + 1, ok
+					})
+				case 5:
+					c.Delete(key)
+				case 6:
+					c.Refresh(key)
+				case 7:
+					c.RegisterLoader(key, loader)
+				case 8:
+					c.cleanup()
+				case 9:
+					c.GetStats()
+				case 10:
+					c.Keys("key_1")
+				}
+			}
+		}(int64(w))
+	}
+	wg.Wait()
+
+	// Stale lookups revalidate in the background; let them finish before
+	// checking the books.
+	deadline := time.Now().Add(2 * time.Second)
+	for refreshesInFlight(c) > 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("arka plan yenilemeleri bitmedi")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	stats := c.GetStats()
+	if stats.Size > maxSize {
+		t.Errorf("size = %d, maxSize %d aşıldı", stats.Size, maxSize)
+	}
+	if stats.Eviction.Tracked != stats.Size {
+		t.Errorf("policy %d anahtar izliyor, store'da %d var", stats.Eviction.Tracked, stats.Size)
+	}
+	if got := stats.Hits + stats.Misses + stats.StaleHits; got != lookups.Load() {
+		t.Errorf("hit+miss+stale = %d, want %d okuma", got, lookups.Load())
+	}
+	if dropped := stats.Eviction.Evictions + stats.Eviction.Rejected; dropped != stats.Evictions {
+		t.Errorf("evictions = %d, policy toplamı %d", stats.Evictions, dropped)
+	}
+}
+
+func refreshesInFlight(c *Cache) int {
+	count := 0
+	for _, shard := range c.shards.Load().shards {
+		shard.mutex.Lock()
+		count += len(shard.refreshing)
+		shard.mutex.Unlock()
+	}
+	return count
+}
+
+func TestStaleLookupClaimsRefresh(t *testing.T) {
+	c := NewCache(16, time.Minute, time.Hour)
+	defer c.Stop()
+
+	var loads atomic.Int64
+	release := make(chan struct{})
+	c.SetWithStaleTTL("key", 1, time.Millisecond, time.Hour, false, 0)
+	c.RegisterLoader("key", func(ctx context.Context) (interface{}, error) {
+		loads.Add(1)
+		<-release
+		return 2, nil
+	})
+	time.Sleep(5 * time.Millisecond)
+
+	for i := 0; i < 3; i++ {
+		lookup, ok := c.Lookup("key")
+		if !ok || !lookup.Stale || !lookup.Revalidating || lookup.Value != 1 {
+			t.Fatalf("lookup = %+v, %v", lookup, ok)
+		}
+	}
+	// The flag is set before Lookup returns, not when the goroutine runs.
+	if err := c.refresh("key", false); err != ErrRefreshInProgress {
+		t.Errorf("refresh = %v, want ErrRefreshInProgress", err)
+	}
+
+	close(release)
+	deadline := time.Now().Add(2 * time.Second)
+	for refreshesInFlight(c) > 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("yenileme bitmedi")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	if got := loads.Load(); got != 1 {
+		t.Errorf("loads = %d, want 1", got)
+	}
+	if stats := c.GetStats(); stats.Revalidations != 1 {
+		t.Errorf("revalidations = %d, want 1", stats.Revalidations)
+	}
+	if lookup, ok := c.Lookup("key"); !ok || lookup.Value != 2 {
+		t.Errorf("lookup = %+v, %v, want 2", lookup, ok)
+	}
+}
+
+const benchmarkKeys = 1024
+
+func benchmarkShardCounts() []int {
+	counts := []int{1}
+	if n := defaultShardCount(4 * benchmarkKeys); n > 1 {
+		counts = append(counts, n)
+	}
+	return counts
+}
+
+func newBenchmarkCache(b *testing.B, shards int) (*Cache, []string) {
+	b.Helper()
+
+	c := NewCache(4*benchmarkKeys, time.Hour, time.Hour)
+	b.Cleanup(c.Stop)
+	c.SetShards(shards)
+
+	keys := make([]string, benchmarkKeys)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("guild_%d", i)
+		c.Set(keys[i], i)
+	}
+	return c, keys
+}
+
+func BenchmarkCacheGetParallel(b *testing.B) {
+	for _, shards := range benchmarkShardCounts() {
+		b.Run(fmt.Sprintf("shards=%d", shards), func(b *testing.B) {
+			c, keys := newBenchmarkCache(b, shards)
+			var worker atomic.Int64
+
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				i := int(worker.Add(1)) * 7919
+				for pb.Next() {
+					c.Get(keys[i%benchmarkKeys])
+					i++
+				}
+			})
+		})
+	}
+}
+
+func BenchmarkCacheSetParallel(b *testing.B) {
+	for _, shards := range benchmarkShardCounts() {
+		b.Run(fmt.Sprintf("shards=%d", shards), func(b *testing.B) {
+			c, keys := newBenchmarkCache(b, shards)
+			var worker atomic.Int64
+
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				i := int(worker.Add(1)) * 7919
+				for pb.Next() {
+					c.Set(keys[i%benchmarkKeys], i)
+					i++
+				}
+			})
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// Codec turns cache entries into bytes for stores outside the process.
+type Codec interface {
+	Marshal(entry *CacheEntry) ([]byte, error)
+	Unmarshal(data []byte) (*CacheEntry, error)
+}
+
+var registeredTypes = struct {
+	sync.RWMutex
+	byName map[string]reflect.Type
+}{byName: make(map[string]reflect.Type)}
+
+// RegisterType makes the dynamic type of each value decodable by JSONCodec.
+// Values stored under an unregistered type cannot be written to disk or
+// Redis, because JSON alone would decode them as maps.
+func RegisterType(values ...interface{}) {
+	registeredTypes.Lock()
+	defer registeredTypes.Unlock()
+
+	for _, value := range values {
+		t := reflect.TypeOf(value)
+		registeredTypes.byName[t.String()] = t
+	}
+}
+
+func lookupType(name string) (reflect.Type, bool) {
+	registeredTypes.RLock()
+	defer registeredTypes.RUnlock()
+	t, ok := registeredTypes.byName[name]
+	return t, ok
+}
+
+// JSONCodec stores the entry metadata next to the value and tags the value
+// with its Go type name, e.g. "[]models.DiscordGuild" or "*models.AuditLog".
+type JSONCodec struct{}
+
+type encodedEntry struct {
+	Type            string          `json:"type"`
+	Data            json.RawMessage `json:"data"`
+	Timestamp       time.Time       `json:"timestamp"`
+	TTL             time.Duration   `json:"ttl"`
+	StaleTTL        time.Duration   `json:"stale_ttl"`
+	Hits            int             `json:"hits"`
+	LastRefresh     time.Time       `json:"last_refresh"`
+	AutoRefresh     bool            `json:"auto_refresh"`
+	RefreshInterval time.Duration   `json:"refresh_interval"`
+	LastError       string          `json:"last_error,omitempty"`
+	LastErrorAt     time.Time       `json:"last_error_at"`
+	RefreshFailures int             `json:"refresh_failures,omitempty"`
+}
+
+func NewJSONCodec() *JSONCodec {
+	return &JSONCodec{}
+}
+
+func (JSONCodec) Marshal(entry *CacheEntry) ([]byte, error) {
+	encoded := encodedEntry{
+		Timestamp:       entry.Timestamp,
+		TTL:             entry.TTL,
+		StaleTTL:        entry.StaleTTL,
+		Hits:            entry.Hits,
+		LastRefresh:     entry.LastRefresh,
+		AutoRefresh:     entry.AutoRefresh,
+		RefreshInterval: entry.RefreshInterval,
+		LastError:       entry.LastError,
+		LastErrorAt:     entry.LastErrorAt,
+		RefreshFailures: entry.RefreshFailures,
+	}
+
+	if entry.Data != nil {
+		encoded.Type = reflect.TypeOf(entry.Data).String()
+		if _, ok := lookupType(encoded.Type); !ok {
+			return nil, fmt.Errorf("cache tipi kayıtlı değil: %s", encoded.Type)
+		}
+
+		data, err := json.Marshal(entry.Data)
+		if err != nil {
+			return nil, fmt.Errorf("cache değeri encode edilemedi (%s): %w", encoded.Type, err)
+		}
+		encoded.Data = data
+	}
+
+	return json.Marshal(encoded)
+}
+
+func (JSONCodec) Unmarshal(data []byte) (*CacheEntry, error) {
+	var encoded encodedEntry
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return nil, fmt.Errorf("cache kaydı decode edilemedi: %w", err)
+	}
+
+	entry := &CacheEntry{
+		Timestamp:       encoded.Timestamp,
+		TTL:             encoded.TTL,
+		StaleTTL:        encoded.StaleTTL,
+		Hits:            encoded.Hits,
+		LastRefresh:     encoded.LastRefresh,
+		AutoRefresh:     encoded.AutoRefresh,
+		RefreshInterval: encoded.RefreshInterval,
+		LastError:       encoded.LastError,
+		LastErrorAt:     encoded.LastErrorAt,
+		RefreshFailures: encoded.RefreshFailures,
+	}
+
+	if encoded.Type == "" {
+		return entry, nil
+	}
+
+	t, ok := lookupType(encoded.Type)
+	if !ok {
+		return nil, fmt.Errorf("cache tipi kayıtlı değil: %s", encoded.Type)
+	}
+
+	value := reflect.New(t)
+	if err := json.Unmarshal(encoded.Data, value.Interface()); err != nil {
+		return nil, fmt.Errorf("cache değeri decode edilemedi (%s): %w", encoded.Type, err)
+	}
+	entry.Data = value.Elem().Interface()
+	return entry, nil
+}
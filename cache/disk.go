@@ -0,0 +1,292 @@
+package cache
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const diskEntryExt = ".entry"
+
+// DiskStore keeps one file per key so entries survive restarts. Each file
+// starts with the quoted key on its own line followed by the codec payload;
+// the key index is rebuilt from those headers on open. Writes go to a synced
+// temporary file that is renamed over the entry and followed by a directory
+// sync, so a crash leaves either the old or the new entry, never half of one.
+//
+// There is no in-memory copy of the values: every Load reads and decodes the
+// file, which costs a syscall and a codec round trip per cache hit. The mutex
+// only guards the index, so that I/O runs without holding it. Use the memory
+// or redis store for hot keys.
+type DiskStore struct {
+	mutex sync.RWMutex
+	dir   string
+	codec Codec
+	index map[string]string
+}
+
+func NewDiskStore(dir string, codec Codec) (*DiskStore, error) {
+	if dir == "" {
+		return nil, errors.New("disk cache için dizin belirtilmedi")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("disk cache dizini oluşturulamadı: %w", err)
+	}
+
+	store := &DiskStore{
+		dir:   dir,
+		codec: codec,
+		index: make(map[string]string),
+	}
+	if err := store.loadIndex(); err != nil {
+		return nil, err
+	}
+
+	log.Printf("💽 Disk cache açıldı: %s (%d öğe)", dir, len(store.index))
+	return store, nil
+}
+
+func (s *DiskStore) Name() string {
+	return StoreDisk
+}
+
+func (s *DiskStore) Load(key string) (*CacheEntry, bool, error) {
+	s.mutex.RLock()
+	name, exists := s.index[key]
+	s.mutex.RUnlock()
+	if !exists {
+		return nil, false, nil
+	}
+
+	_, payload, err := readDiskEntry(filepath.Join(s.dir, name))
+	if errors.Is(err, os.ErrNotExist) {
+		// Deleted after the index lookup.
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	entry, err := s.codec.Unmarshal(payload)
+	if err != nil {
+		return nil, false, err
+	}
+	return entry, true, nil
+}
+
+func (s *DiskStore) Save(key string, entry *CacheEntry) error {
+	payload, err := s.codec.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	var buffer bytes.Buffer
+	buffer.WriteString(strconv.Quote(key))
+	buffer.WriteByte('\n')
+	buffer.Write(payload)
+
+	name := diskEntryName(key)
+	tmp, err := writeSyncedTemp(s.dir, name, buffer.Bytes())
+	if err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	if err := os.Rename(tmp, filepath.Join(s.dir, name)); err != nil {
+		s.mutex.Unlock()
+		os.Remove(tmp)
+		return err
+	}
+	s.index[key] = name
+	s.mutex.Unlock()
+
+	return syncDir(s.dir)
+}
+
+func (s *DiskStore) Delete(key string) (bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	name, exists := s.index[key]
+	if !exists {
+		return false, nil
+	}
+
+	delete(s.index, key)
+	if err := os.Remove(filepath.Join(s.dir, name)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return true, err
+	}
+	return true, nil
+}
+
+func (s *DiskStore) Keys(prefix string) ([]string, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	var keys []string
+	for key := range s.index {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+// Range skips entries that cannot be read or decoded (for example values
+// of a type that is no longer registered) instead of aborting the walk.
+func (s *DiskStore) Range(fn func(key string, entry *CacheEntry) bool) error {
+	keys, _ := s.Keys("")
+
+	var firstErr error
+	for _, key := range keys {
+		entry, exists, err := s.Load(key)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		if exists && !fn(key, entry) {
+			break
+		}
+	}
+	return firstErr
+}
+
+func (s *DiskStore) Len() (int, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return len(s.index), nil
+}
+
+func (s *DiskStore) Clear() (int, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	count := len(s.index)
+	var firstErr error
+	for key, name := range s.index {
+		if err := os.Remove(filepath.Join(s.dir, name)); err != nil && !errors.Is(err, os.ErrNotExist) && firstErr == nil {
+			firstErr = err
+		}
+		delete(s.index, key)
+	}
+	return count, firstErr
+}
+
+func (s *DiskStore) Close() error {
+	return nil
+}
+
+func (s *DiskStore) loadIndex() error {
+	names, err := os.ReadDir(s.dir)
+	if err != nil {
+		return fmt.Errorf("disk cache dizini okunamadı: %w", err)
+	}
+
+	for _, file := range names {
+		name := file.Name()
+		if strings.HasSuffix(name, ".tmp") {
+			os.Remove(filepath.Join(s.dir, name))
+			continue
+		}
+		if file.IsDir() || !strings.HasSuffix(name, diskEntryExt) {
+			continue
+		}
+
+		key, err := readDiskKey(filepath.Join(s.dir, name))
+		if err != nil {
+			log.Printf("⚠️ Bozuk disk cache dosyası atlandı: %s (%v)", name, err)
+			continue
+		}
+		s.index[key] = name
+	}
+	return nil
+}
+
+func diskEntryName(key string) string {
+	sum := sha1.Sum([]byte(key))
+	return hex.EncodeToString(sum[:]) + diskEntryExt
+}
+
+// writeSyncedTemp writes data to a new temporary file next to name and
+// fsyncs it, so the rename that follows never exposes unwritten blocks.
+func writeSyncedTemp(dir, name string, data []byte) (string, error) {
+	file, err := os.CreateTemp(dir, name+".*.tmp")
+	if err != nil {
+		return "", err
+	}
+	tmp := file.Name()
+
+	if err := file.Chmod(0o644); err != nil {
+		file.Close()
+		os.Remove(tmp)
+		return "", err
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		os.Remove(tmp)
+		return "", err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		os.Remove(tmp)
+		return "", err
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(tmp)
+		return "", err
+	}
+	return tmp, nil
+}
+
+// syncDir makes a rename inside dir durable.
+func syncDir(dir string) error {
+	file, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return file.Sync()
+}
+
+func readDiskKey(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	header, err := bufio.NewReader(file).ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strconv.Unquote(strings.TrimSuffix(header, "\n"))
+}
+
+func readDiskEntry(path string) (string, []byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", nil, err
+	}
+
+	header, payload, found := bytes.Cut(data, []byte{'\n'})
+	if !found {
+		return "", nil, io.ErrUnexpectedEOF
+	}
+	key, err := strconv.Unquote(string(header))
+	if err != nil {
+		return "", nil, err
+	}
+	return key, payload, nil
+}
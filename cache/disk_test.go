@@ -0,0 +1,149 @@
+package cache
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+type diskTestValue struct {
+	Name  string
+	Count int
+}
+
+func init() {
+	RegisterType(diskTestValue{})
+}
+
+func TestDiskStoreSurvivesReopen(t *testing.T) {
+	dir := t.TempDir()
+
+	store, err := NewDiskStore(dir, NewJSONCodec())
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry := &CacheEntry{Data: diskTestValue{Name: "guild", Count: 3}, Timestamp: time.Now().Truncate(time.Second), TTL: time.Minute}
+	for _, key := range []string{"guild_1", "guild_2"} {
+		if err := store.Save(key, entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := store.Delete("guild_2"); err != nil {
+		t.Fatal(err)
+	}
+
+	// A temp file left behind by a crash mid-write must not come back.
+	if err := os.WriteFile(filepath.Join(dir, diskEntryName("guild_3")+".123.tmp"), []byte("\"guild_3\"\n{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened, err := NewDiskStore(dir, NewJSONCodec())
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys, _ := reopened.Keys("")
+	if len(keys) != 1 || keys[0] != "guild_1" {
+		t.Fatalf("keys = %v", keys)
+	}
+
+	loaded, exists, err := reopened.Load("guild_1")
+	if err != nil || !exists {
+		t.Fatalf("Load = %v, %v", exists, err)
+	}
+	if value, ok := loaded.Data.(diskTestValue); !ok || value != entry.Data {
+		t.Errorf("data = %#v", loaded.Data)
+	}
+	if !loaded.Timestamp.Equal(entry.Timestamp) || loaded.TTL != entry.TTL {
+		t.Errorf("entry = %+v", loaded)
+	}
+
+	files, _ := os.ReadDir(dir)
+	for _, file := range files {
+		if strings.HasSuffix(file.Name(), ".tmp") {
+			t.Errorf("geçici dosya kaldı: %s", file.Name())
+		}
+	}
+}
+
+func TestDiskStoreLoadAfterFileRemoved(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewDiskStore(dir, NewJSONCodec())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Save("guild_1", &CacheEntry{Data: diskTestValue{Name: "guild"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Simulates a Delete landing between the index lookup and the read.
+	if err := os.Remove(filepath.Join(dir, diskEntryName("guild_1"))); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, exists, err := store.Load("guild_1"); exists || err != nil {
+		t.Errorf("Load = %v, %v, want a miss", exists, err)
+	}
+}
+
+func TestDiskCacheKeepsHitCounts(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewDiskStore(dir, NewJSONCodec())
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := NewCacheWithStore(store, 16, time.Minute, time.Hour)
+	defer c.Stop()
+
+	c.SetWithAutoRefresh("guild_1", diskTestValue{Name: "guild"}, time.Minute, true, time.Hour)
+	c.RegisterLoader("guild_1", func(ctx context.Context) (interface{}, error) {
+		return diskTestValue{Name: "refreshed"}, nil
+	})
+	for i := 0; i < 3; i++ {
+		if _, ok := c.Get("guild_1"); !ok {
+			t.Fatal("cache miss")
+		}
+	}
+	if _, ok := c.Lookup("guild_1"); !ok {
+		t.Fatal("cache miss")
+	}
+
+	// Hits reach the store with the next save, and a refresh keeps them.
+	if err := c.Refresh("guild_1"); err != nil {
+		t.Fatal(err)
+	}
+	if loaded, _, _ := store.Load("guild_1"); loaded.Hits != 4 {
+		t.Fatalf("saved hits = %d, want 4", loaded.Hits)
+	}
+	c.Get("guild_1")
+	c.Update("guild_1", func(value interface{}) (interface{}, bool) {
+		return value, true
+	})
+	if loaded, _, _ := store.Load("guild_1"); loaded.Hits != 5 {
+		t.Fatalf("saved hits = %d, want 5", loaded.Hits)
+	}
+
+	// A new process continues from the saved count.
+	reopened, err := NewDiskStore(dir, NewJSONCodec())
+	if err != nil {
+		t.Fatal(err)
+	}
+	next := NewCacheWithStore(reopened, 16, time.Minute, time.Hour)
+	defer next.Stop()
+	next.Get("guild_1")
+	shard := next.shard("guild_1")
+	if got := shard.hits["guild_1"]; got != 6 {
+		t.Errorf("hits = %d, want 6", got)
+	}
+
+	next.Set("guild_1", diskTestValue{Name: "replaced"})
+	if _, exists := shard.hits["guild_1"]; exists {
+		t.Error("Set hit sayısını sıfırlamadı")
+	}
+	next.Delete("guild_1")
+	if len(shard.hits) != 0 {
+		t.Errorf("hits = %v", shard.hits)
+	}
+}
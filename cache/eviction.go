@@ -0,0 +1,465 @@
+package cache
+
+import (
+	"container/list"
+	"fmt"
+	"strings"
+)
+
+const (
+	EvictionLRU     = "lru"
+	EvictionLFU     = "lfu"
+	EvictionFIFO    = "fifo"
+	EvictionTinyLFU = "tinylfu"
+)
+
+// EvictionPolicy decides which keys leave a full cache. Every operation is
+// O(1); the cache mutex serializes calls, so implementations are not safe
+// for concurrent use on their own.
+type EvictionPolicy interface {
+	Name() string
+	// Add tracks a new key and returns the keys to evict to stay within
+	// capacity. An admission policy may return key itself to reject it.
+	Add(key string) []string
+	Access(key string)
+	Miss(key string)
+	Remove(key string)
+	Len() int
+	Reset()
+	Stats() EvictionStats
+}
+
+type EvictionStats struct {
+	Policy       string `json:"policy"`
+	Capacity     int    `json:"capacity"`
+	Tracked      int    `json:"tracked"`
+	Evictions    int64  `json:"evictions"`
+	Admitted     int64  `json:"admitted,omitempty"`
+	Rejected     int64  `json:"rejected,omitempty"`
+	SketchResets int64  `json:"sketch_resets,omitempty"`
+	MinFrequency int    `json:"min_frequency,omitempty"`
+}
+
+func NewEvictionPolicy(name string, capacity int) (EvictionPolicy, error) {
+	if capacity < 1 {
+		capacity = 1
+	}
+
+	switch strings.ToLower(name) {
+	case "", EvictionLRU:
+		return newRecencyPolicy(EvictionLRU, capacity, true), nil
+	case EvictionFIFO:
+		return newRecencyPolicy(EvictionFIFO, capacity, false), nil
+	case EvictionLFU:
+		return newLFUPolicy(capacity), nil
+	case EvictionTinyLFU:
+		return newTinyLFUPolicy(capacity), nil
+	}
+	return nil, fmt.Errorf("bilinmeyen eviction politikası: %q (lru, lfu, fifo veya tinylfu olmalı)", name)
+}
+
+// recencyPolicy is LRU when accesses move keys to the front and FIFO when
+// they do not; either way the back of the list is evicted first.
+type recencyPolicy struct {
+	name      string
+	capacity  int
+	promote   bool
+	order     *list.List
+	items     map[string]*list.Element
+	evictions int64
+}
+
+func newRecencyPolicy(name string, capacity int, promote bool) *recencyPolicy {
+	return &recencyPolicy{
+		name:     name,
+		capacity: capacity,
+		promote:  promote,
+		order:    list.New(),
+		items:    make(map[string]*list.Element),
+	}
+}
+
+func (p *recencyPolicy) Name() string {
+	return p.name
+}
+
+func (p *recencyPolicy) Add(key string) []string {
+	if element, exists := p.items[key]; exists {
+		if p.promote {
+			p.order.MoveToFront(element)
+		}
+		return nil
+	}
+
+	p.items[key] = p.order.PushFront(key)
+
+	var evicted []string
+	for p.order.Len() > p.capacity {
+		oldest := p.order.Back()
+		victim := p.order.Remove(oldest).(string)
+		delete(p.items, victim)
+		evicted = append(evicted, victim)
+		p.evictions++
+	}
+	return evicted
+}
+
+func (p *recencyPolicy) Access(key string) {
+	if element, exists := p.items[key]; exists && p.promote {
+		p.order.MoveToFront(element)
+	}
+}
+
+func (p *recencyPolicy) Miss(key string) {}
+
+func (p *recencyPolicy) Remove(key string) {
+	if element, exists := p.items[key]; exists {
+		p.order.Remove(element)
+		delete(p.items, key)
+	}
+}
+
+func (p *recencyPolicy) Len() int {
+	return p.order.Len()
+}
+
+func (p *recencyPolicy) Reset() {
+	p.order.Init()
+	p.items = make(map[string]*list.Element)
+}
+
+func (p *recencyPolicy) Stats() EvictionStats {
+	return EvictionStats{
+		Policy:    p.name,
+		Capacity:  p.capacity,
+		Tracked:   p.order.Len(),
+		Evictions: p.evictions,
+	}
+}
+
+// lfuPolicy is the constant-time LFU from Shah, Mitra and Matani: buckets
+// of equal frequency in ascending order, each holding its keys from most
+// to least recently used, so ties are broken by recency.
+type lfuPolicy struct {
+	capacity  int
+	buckets   *list.List
+	items     map[string]*list.Element
+	evictions int64
+}
+
+type lfuBucket struct {
+	frequency int
+	keys      *list.List
+}
+
+type lfuItem struct {
+	key    string
+	bucket *list.Element
+}
+
+func newLFUPolicy(capacity int) *lfuPolicy {
+	return &lfuPolicy{
+		capacity: capacity,
+		buckets:  list.New(),
+		items:    make(map[string]*list.Element),
+	}
+}
+
+func (p *lfuPolicy) Name() string {
+	return EvictionLFU
+}
+
+func (p *lfuPolicy) Add(key string) []string {
+	if _, exists := p.items[key]; exists {
+		p.Access(key)
+		return nil
+	}
+
+	// Evict before inserting, otherwise the new key would be the least
+	// frequently used one and leave straight away.
+	var evicted []string
+	for len(p.items) >= p.capacity {
+		evicted = append(evicted, p.evict())
+	}
+
+	first := p.buckets.Front()
+	if first == nil || first.Value.(*lfuBucket).frequency != 1 {
+		first = p.buckets.PushFront(&lfuBucket{frequency: 1, keys: list.New()})
+	}
+	item := &lfuItem{key: key, bucket: first}
+	p.items[key] = first.Value.(*lfuBucket).keys.PushFront(item)
+	return evicted
+}
+
+func (p *lfuPolicy) Access(key string) {
+	element, exists := p.items[key]
+	if !exists {
+		return
+	}
+
+	item := element.Value.(*lfuItem)
+	current := item.bucket
+	bucket := current.Value.(*lfuBucket)
+
+	next := current.Next()
+	if next == nil || next.Value.(*lfuBucket).frequency != bucket.frequency+1 {
+		next = p.buckets.InsertAfter(&lfuBucket{frequency: bucket.frequency + 1, keys: list.New()}, current)
+	}
+
+	bucket.keys.Remove(element)
+	item.bucket = next
+	p.items[key] = next.Value.(*lfuBucket).keys.PushFront(item)
+
+	if bucket.keys.Len() == 0 {
+		p.buckets.Remove(current)
+	}
+}
+
+func (p *lfuPolicy) Miss(key string) {}
+
+func (p *lfuPolicy) Remove(key string) {
+	element, exists := p.items[key]
+	if !exists {
+		return
+	}
+	p.unlink(element)
+}
+
+func (p *lfuPolicy) evict() string {
+	bucket := p.buckets.Front().Value.(*lfuBucket)
+	item := bucket.keys.Back().Value.(*lfuItem)
+	p.unlink(bucket.keys.Back())
+	p.evictions++
+	return item.key
+}
+
+func (p *lfuPolicy) unlink(element *list.Element) {
+	item := element.Value.(*lfuItem)
+	bucket := item.bucket.Value.(*lfuBucket)
+	bucket.keys.Remove(element)
+	if bucket.keys.Len() == 0 {
+		p.buckets.Remove(item.bucket)
+	}
+	delete(p.items, item.key)
+}
+
+func (p *lfuPolicy) Len() int {
+	return len(p.items)
+}
+
+func (p *lfuPolicy) Reset() {
+	p.buckets.Init()
+	p.items = make(map[string]*list.Element)
+}
+
+func (p *lfuPolicy) Stats() EvictionStats {
+	stats := EvictionStats{
+		Policy:    EvictionLFU,
+		Capacity:  p.capacity,
+		Tracked:   len(p.items),
+		Evictions: p.evictions,
+	}
+	if first := p.buckets.Front(); first != nil {
+		stats.MinFrequency = first.Value.(*lfuBucket).frequency
+	}
+	return stats
+}
+
+// tinyLFUPolicy is a W-TinyLFU: new keys land in a small LRU window, and a
+// key falling out of the window only replaces the main LRU's victim if the
+// count-min sketch has seen it requested more often. One-off lookups
+// therefore cannot flush the keys that are actually hot.
+type tinyLFUPolicy struct {
+	capacity   int
+	windowSize int
+	window     *recencyPolicy
+	main       *recencyPolicy
+	sketch     *countMinSketch
+	evictions  int64
+	admitted   int64
+	rejected   int64
+}
+
+func newTinyLFUPolicy(capacity int) *tinyLFUPolicy {
+	windowSize := capacity / 100
+	if windowSize < 1 && capacity > 1 {
+		windowSize = 1
+	}
+
+	return &tinyLFUPolicy{
+		capacity:   capacity,
+		windowSize: windowSize,
+		window:     newRecencyPolicy("window", capacity, true),
+		main:       newRecencyPolicy("main", capacity, true),
+		sketch:     newCountMinSketch(capacity),
+	}
+}
+
+func (p *tinyLFUPolicy) Name() string {
+	return EvictionTinyLFU
+}
+
+func (p *tinyLFUPolicy) Add(key string) []string {
+	p.sketch.increment(key)
+
+	if _, exists := p.window.items[key]; exists {
+		p.window.Access(key)
+		return nil
+	}
+	if _, exists := p.main.items[key]; exists {
+		p.main.Access(key)
+		return nil
+	}
+
+	p.window.Add(key)
+
+	var evicted []string
+	for p.window.Len() > p.windowSize {
+		candidate := p.window.order.Back().Value.(string)
+		p.window.Remove(candidate)
+
+		if p.main.Len() < p.capacity-p.windowSize {
+			p.main.Add(candidate)
+			continue
+		}
+
+		victim := p.main.order.Back().Value.(string)
+		if p.sketch.estimate(candidate) > p.sketch.estimate(victim) {
+			p.main.Remove(victim)
+			p.main.Add(candidate)
+			evicted = append(evicted, victim)
+			p.evictions++
+			p.admitted++
+		} else {
+			evicted = append(evicted, candidate)
+			p.rejected++
+		}
+	}
+	return evicted
+}
+
+func (p *tinyLFUPolicy) Access(key string) {
+	p.sketch.increment(key)
+	p.window.Access(key)
+	p.main.Access(key)
+}
+
+func (p *tinyLFUPolicy) Miss(key string) {
+	p.sketch.increment(key)
+}
+
+func (p *tinyLFUPolicy) Remove(key string) {
+	p.window.Remove(key)
+	p.main.Remove(key)
+}
+
+func (p *tinyLFUPolicy) Len() int {
+	return p.window.Len() + p.main.Len()
+}
+
+func (p *tinyLFUPolicy) Reset() {
+	p.window.Reset()
+	p.main.Reset()
+	p.sketch.clear()
+}
+
+func (p *tinyLFUPolicy) Stats() EvictionStats {
+	return EvictionStats{
+		Policy:       EvictionTinyLFU,
+		Capacity:     p.capacity,
+		Tracked:      p.Len(),
+		Evictions:    p.evictions,
+		Admitted:     p.admitted,
+		Rejected:     p.rejected,
+		SketchResets: p.sketch.resets,
+	}
+}
+
+const (
+	sketchDepth      = 4
+	sketchMaxCount   = 15
+	sketchSampleRate = 10
+)
+
+// countMinSketch estimates request frequency with 4-bit saturating counters.
+// After sampleSize increments every counter is halved (amortized O(1) per
+// increment), so the estimate follows recent popularity instead of all-time
+// totals.
+type countMinSketch struct {
+	rows       [sketchDepth][]uint8
+	mask       uint64
+	additions  int
+	sampleSize int
+	resets     int64
+}
+
+func newCountMinSketch(capacity int) *countMinSketch {
+	// Four counters per cached key keep collisions with the much larger
+	// population of keys that are only requested once reasonably rare.
+	width := 16
+	for width < 4*capacity {
+		width <<= 1
+	}
+
+	sketch := &countMinSketch{
+		mask:       uint64(width - 1),
+		sampleSize: sketchSampleRate * capacity,
+	}
+	for i := range sketch.rows {
+		sketch.rows[i] = make([]uint8, width)
+	}
+	return sketch
+}
+
+func (s *countMinSketch) increment(key string) {
+	h1, h2 := sketchHashes(key)
+	for i := range s.rows {
+		index := (h1 + uint64(i)*h2) & s.mask
+		if s.rows[i][index] < sketchMaxCount {
+			s.rows[i][index]++
+		}
+	}
+
+	s.additions++
+	if s.additions >= s.sampleSize {
+		s.age()
+	}
+}
+
+func (s *countMinSketch) estimate(key string) uint8 {
+	h1, h2 := sketchHashes(key)
+	minimum := uint8(sketchMaxCount)
+	for i := range s.rows {
+		if count := s.rows[i][(h1+uint64(i)*h2)&s.mask]; count < minimum {
+			minimum = count
+		}
+	}
+	return minimum
+}
+
+func (s *countMinSketch) age() {
+	for i := range s.rows {
+		for j := range s.rows[i] {
+			s.rows[i][j] >>= 1
+		}
+	}
+	s.additions /= 2
+	s.resets++
+}
+
+func (s *countMinSketch) clear() {
+	for i := range s.rows {
+		clear(s.rows[i])
+	}
+	s.additions = 0
+}
+
+// sketchHashes remixes hashKey before use: every key in a shard shares the
+// low bits that picked the shard, which would otherwise crowd the sketch.
+func sketchHashes(key string) (uint64, uint64) {
+	sum := hashKey(key)
+	sum ^= sum >> 33
+	sum *= 0xff51afd7ed558ccd
+	sum ^= sum >> 33
+	return sum, (sum >> 32) | 1
+}
@@ -0,0 +1,175 @@
+package cache
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestLFUBreaksTiesByRecency(t *testing.T) {
+	policy := newLFUPolicy(3)
+	for _, key := range []string{"a", "b", "c"} {
+		policy.Add(key)
+	}
+	policy.Access("a")
+
+	// b and c are both at frequency 1; b was used longer ago.
+	if evicted := policy.Add("d"); !reflect.DeepEqual(evicted, []string{"b"}) {
+		t.Fatalf("evicted = %v, want [b]", evicted)
+	}
+
+	// Touching c again puts it ahead of d, which is now the oldest at 1.
+	policy.Access("c")
+	if evicted := policy.Add("e"); !reflect.DeepEqual(evicted, []string{"d"}) {
+		t.Fatalf("evicted = %v, want [d]", evicted)
+	}
+
+	stats := policy.Stats()
+	if stats.Evictions != 2 || stats.Tracked != 3 || stats.MinFrequency != 1 {
+		t.Errorf("stats = %+v", stats)
+	}
+}
+
+func TestTinyLFURejectsColdCandidate(t *testing.T) {
+	// Capacity 100 gives a window of one key and a main LRU of 99.
+	policy := newTinyLFUPolicy(100)
+	for i := 0; i < 99; i++ {
+		policy.Add(fmt.Sprintf("hot_%d", i))
+	}
+	for round := 0; round < 3; round++ {
+		for i := 0; i < 99; i++ {
+			policy.Access(fmt.Sprintf("hot_%d", i))
+		}
+	}
+
+	policy.Add("cold_1")
+	if evicted := policy.Add("cold_2"); !reflect.DeepEqual(evicted, []string{"cold_1"}) {
+		t.Fatalf("evicted = %v, want the cold candidate", evicted)
+	}
+	if _, exists := policy.main.items["hot_0"]; !exists {
+		t.Fatal("sıcak kurban soğuk aday yüzünden çıkarıldı")
+	}
+
+	// A key that kept missing builds up frequency and is admitted over the
+	// least recently used hot key.
+	for i := 0; i < 6; i++ {
+		policy.Miss("popular")
+	}
+	policy.Add("popular")
+	evicted := policy.Add("cold_3")
+	if len(evicted) != 1 || evicted[0] != "hot_0" {
+		t.Fatalf("evicted = %v, want [hot_0]", evicted)
+	}
+	if _, exists := policy.main.items["popular"]; !exists {
+		t.Fatal("sık istenen aday kabul edilmedi")
+	}
+
+	stats := policy.Stats()
+	if stats.Rejected != 2 || stats.Admitted != 1 || stats.Evictions != 1 || stats.Tracked != 100 {
+		t.Errorf("stats = %+v", stats)
+	}
+}
+
+func TestEvictionPoliciesWithCapacityOne(t *testing.T) {
+	for _, name := range []string{EvictionLRU, EvictionFIFO, EvictionLFU, EvictionTinyLFU} {
+		t.Run(name, func(t *testing.T) {
+			for _, capacity := range []int{1, 0, -5} {
+				policy, err := NewEvictionPolicy(name, capacity)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				dropped := 0
+				for i := 0; i < 5; i++ {
+					dropped += len(policy.Add(fmt.Sprintf("key_%d", i)))
+					if policy.Len() != 1 {
+						t.Fatalf("capacity %d: Len = %d after %d adds", capacity, policy.Len(), i+1)
+					}
+				}
+				if dropped != 4 {
+					t.Errorf("capacity %d: %d key dropped, want 4", capacity, dropped)
+				}
+				if stats := policy.Stats(); stats.Capacity != 1 {
+					t.Errorf("capacity %d: stats = %+v", capacity, stats)
+				}
+			}
+		})
+	}
+}
+
+func TestCacheSmallerThanMinShardCapacity(t *testing.T) {
+	for _, maxSize := range []int{1, 5, minShardCapacity - 1} {
+		t.Run(fmt.Sprint(maxSize), func(t *testing.T) {
+			c := NewCache(maxSize, time.Minute, time.Hour)
+			defer c.Stop()
+			c.SetShards(8)
+
+			for i := 0; i < maxSize+10; i++ {
+				c.Set(fmt.Sprintf("key_%d", i), i)
+			}
+
+			stats := c.GetStats()
+			if stats.Shards != 1 {
+				t.Errorf("shards = %d, want 1", stats.Shards)
+			}
+			if stats.Size != maxSize || stats.Eviction.Capacity != maxSize {
+				t.Errorf("size = %d, capacity = %d, want %d", stats.Size, stats.Eviction.Capacity, maxSize)
+			}
+			if stats.Evictions != 10 || stats.Eviction.Evictions != 10 {
+				t.Errorf("evictions = %d / %d, want 10", stats.Evictions, stats.Eviction.Evictions)
+			}
+			if _, ok := c.Get(fmt.Sprintf("key_%d", maxSize+9)); !ok {
+				t.Error("son eklenen anahtar cache'te yok")
+			}
+		})
+	}
+}
+
+func TestEvictionStatsSumAcrossShards(t *testing.T) {
+	for _, name := range []string{EvictionLRU, EvictionLFU, EvictionTinyLFU} {
+		t.Run(name, func(t *testing.T) {
+			c := NewCache(64, time.Minute, time.Hour)
+			defer c.Stop()
+			c.SetShards(4)
+			if err := c.SetEvictionPolicy(name); err != nil {
+				t.Fatal(err)
+			}
+
+			for i := 0; i < 300; i++ {
+				c.Set(fmt.Sprintf("key_%d", i), i)
+			}
+
+			stats := c.GetStats()
+			var perShard EvictionStats
+			for _, shard := range c.shards.Load().shards {
+				shardStats := shard.policy.Stats()
+				if shardStats.Capacity != 16 {
+					t.Errorf("shard capacity = %d, want 16", shardStats.Capacity)
+				}
+				perShard.Capacity += shardStats.Capacity
+				perShard.Tracked += shardStats.Tracked
+				perShard.Evictions += shardStats.Evictions
+				perShard.Rejected += shardStats.Rejected
+			}
+
+			if stats.Shards != 4 || stats.Eviction.Policy != name {
+				t.Errorf("stats = %+v", stats)
+			}
+			if stats.Eviction.Capacity != 64 || stats.Eviction.Capacity != perShard.Capacity {
+				t.Errorf("capacity = %d, shard toplamı %d", stats.Eviction.Capacity, perShard.Capacity)
+			}
+			if stats.Eviction.Tracked != perShard.Tracked || stats.Eviction.Tracked != stats.Size {
+				t.Errorf("tracked = %d, shard toplamı %d, size %d", stats.Eviction.Tracked, perShard.Tracked, stats.Size)
+			}
+			if stats.Eviction.Evictions != perShard.Evictions || stats.Eviction.Rejected != perShard.Rejected {
+				t.Errorf("eviction = %+v, shard toplamı %+v", stats.Eviction, perShard)
+			}
+			// Every key that is not cached left through an eviction or a
+			// rejected admission, and each was counted exactly once.
+			if dropped := stats.Eviction.Evictions + stats.Eviction.Rejected; dropped != stats.Evictions || int(dropped) != 300-stats.Size {
+				t.Errorf("evictions = %d, policy toplamı %d, size %d", stats.Evictions, dropped, stats.Size)
+			}
+		})
+	}
+}
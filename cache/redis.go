@@ -0,0 +1,390 @@
+package cache
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	redisPoolSize    = 8
+	redisDialTimeout = 5 * time.Second
+	redisIOTimeout   = 5 * time.Second
+	redisScanCount   = 500
+)
+
+// RedisStore speaks RESP directly, which is all a handful of GET/SET/SCAN
+// calls need, and works against Redis, Valkey, KeyDB or miniredis. Keys are
+// namespaced with prefix so several replicas (or apps) can share a database;
+// every entry also gets a native expiry of TTL+StaleTTL, so Redis drops
+// dead entries even when no replica runs cleanup.
+type RedisStore struct {
+	address  string
+	username string
+	password string
+	database int
+	prefix   string
+	codec    Codec
+	pool     chan *redisConn
+}
+
+type redisConn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+	broken bool
+}
+
+type redisError string
+
+func (e redisError) Error() string {
+	return "redis: " + string(e)
+}
+
+// NewRedisStore accepts redis://[user:password@]host:port[/db] URLs.
+func NewRedisStore(rawURL, prefix string, codec Codec) (*RedisStore, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Scheme != "redis" || parsed.Host == "" {
+		return nil, fmt.Errorf("geçersiz Redis URL'i: %q", rawURL)
+	}
+
+	store := &RedisStore{
+		address: parsed.Host,
+		prefix:  prefix,
+		codec:   codec,
+		pool:    make(chan *redisConn, redisPoolSize),
+	}
+	if parsed.Port() == "" {
+		store.address = net.JoinHostPort(parsed.Hostname(), "6379")
+	}
+	if parsed.User != nil {
+		store.username = parsed.User.Username()
+		store.password, _ = parsed.User.Password()
+	}
+	if db := strings.Trim(parsed.Path, "/"); db != "" {
+		if store.database, err = strconv.Atoi(db); err != nil {
+			return nil, fmt.Errorf("geçersiz Redis veritabanı: %q", db)
+		}
+	}
+
+	if _, err := store.do("PING"); err != nil {
+		return nil, fmt.Errorf("Redis'e bağlanılamadı (%s): %w", store.address, err)
+	}
+
+	log.Printf("🧱 Redis cache bağlandı: %s (db %d, prefix %q)", store.address, store.database, prefix)
+	return store, nil
+}
+
+func (s *RedisStore) Name() string {
+	return StoreRedis
+}
+
+func (s *RedisStore) Load(key string) (*CacheEntry, bool, error) {
+	reply, err := s.do("GET", s.prefix+key)
+	if err != nil || reply == nil {
+		return nil, false, err
+	}
+
+	payload, ok := reply.([]byte)
+	if !ok {
+		return nil, false, fmt.Errorf("beklenmeyen Redis yanıtı: %T", reply)
+	}
+
+	entry, err := s.codec.Unmarshal(payload)
+	if err != nil {
+		return nil, false, err
+	}
+	return entry, true, nil
+}
+
+func (s *RedisStore) Save(key string, entry *CacheEntry) error {
+	payload, err := s.codec.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	args := []string{"SET", s.prefix + key, string(payload)}
+	if expiry := entry.TTL + entry.StaleTTL; expiry > 0 {
+		args = append(args, "PX", strconv.FormatInt(expiry.Milliseconds()+1, 10))
+	}
+	_, err = s.do(args...)
+	return err
+}
+
+func (s *RedisStore) Delete(key string) (bool, error) {
+	reply, err := s.do("DEL", s.prefix+key)
+	if err != nil {
+		return false, err
+	}
+	count, _ := reply.(int64)
+	return count > 0, nil
+}
+
+func (s *RedisStore) Keys(prefix string) ([]string, error) {
+	var keys []string
+	err := s.scan(prefix, func(batch []string) error {
+		for _, key := range batch {
+			keys = append(keys, strings.TrimPrefix(key, s.prefix))
+		}
+		return nil
+	})
+	return keys, err
+}
+
+func (s *RedisStore) Range(fn func(key string, entry *CacheEntry) bool) error {
+	stop := errors.New("stop")
+
+	err := s.scan("", func(batch []string) error {
+		if len(batch) == 0 {
+			return nil
+		}
+
+		reply, err := s.do(append([]string{"MGET"}, batch...)...)
+		if err != nil {
+			return err
+		}
+		values, _ := reply.([]interface{})
+
+		for i, value := range values {
+			payload, ok := value.([]byte)
+			if !ok || i >= len(batch) {
+				continue
+			}
+			entry, err := s.codec.Unmarshal(payload)
+			if err != nil {
+				continue
+			}
+			if !fn(strings.TrimPrefix(batch[i], s.prefix), entry) {
+				return stop
+			}
+		}
+		return nil
+	})
+	if err == stop {
+		return nil
+	}
+	return err
+}
+
+func (s *RedisStore) Len() (int, error) {
+	count := 0
+	err := s.scan("", func(batch []string) error {
+		count += len(batch)
+		return nil
+	})
+	return count, err
+}
+
+// Clear only removes keys under the store prefix, never the whole database.
+func (s *RedisStore) Clear() (int, error) {
+	count := 0
+	err := s.scan("", func(batch []string) error {
+		if len(batch) == 0 {
+			return nil
+		}
+		reply, err := s.do(append([]string{"DEL"}, batch...)...)
+		if deleted, ok := reply.(int64); ok {
+			count += int(deleted)
+		}
+		return err
+	})
+	return count, err
+}
+
+func (s *RedisStore) Close() error {
+	for {
+		select {
+		case conn := <-s.pool:
+			conn.conn.Close()
+		default:
+			return nil
+		}
+	}
+}
+
+func (s *RedisStore) scan(prefix string, fn func(batch []string) error) error {
+	pattern := escapeRedisPattern(s.prefix+prefix) + "*"
+	cursor := "0"
+
+	for {
+		reply, err := s.do("SCAN", cursor, "MATCH", pattern, "COUNT", strconv.Itoa(redisScanCount))
+		if err != nil {
+			return err
+		}
+
+		parts, ok := reply.([]interface{})
+		if !ok || len(parts) != 2 {
+			return fmt.Errorf("beklenmeyen SCAN yanıtı: %v", reply)
+		}
+		next, _ := parts[0].([]byte)
+		items, _ := parts[1].([]interface{})
+
+		batch := make([]string, 0, len(items))
+		for _, item := range items {
+			if key, ok := item.([]byte); ok {
+				batch = append(batch, string(key))
+			}
+		}
+		if err := fn(batch); err != nil {
+			return err
+		}
+
+		cursor = string(next)
+		if cursor == "0" || cursor == "" {
+			return nil
+		}
+	}
+}
+
+func (s *RedisStore) do(args ...string) (interface{}, error) {
+	conn, err := s.acquire()
+	if err != nil {
+		return nil, err
+	}
+	defer s.release(conn)
+
+	return conn.do(args...)
+}
+
+func (s *RedisStore) acquire() (*redisConn, error) {
+	select {
+	case conn := <-s.pool:
+		return conn, nil
+	default:
+	}
+
+	netConn, err := net.DialTimeout("tcp", s.address, redisDialTimeout)
+	if err != nil {
+		return nil, err
+	}
+	conn := &redisConn{conn: netConn, reader: bufio.NewReader(netConn)}
+
+	if s.password != "" {
+		args := []string{"AUTH", s.password}
+		if s.username != "" {
+			args = []string{"AUTH", s.username, s.password}
+		}
+		if _, err := conn.do(args...); err != nil {
+			netConn.Close()
+			return nil, err
+		}
+	}
+	if s.database != 0 {
+		if _, err := conn.do("SELECT", strconv.Itoa(s.database)); err != nil {
+			netConn.Close()
+			return nil, err
+		}
+	}
+	return conn, nil
+}
+
+func (s *RedisStore) release(conn *redisConn) {
+	if conn.broken {
+		conn.conn.Close()
+		return
+	}
+	select {
+	case s.pool <- conn:
+	default:
+		conn.conn.Close()
+	}
+}
+
+// do sends one command and reads its reply. Redis errors leave the
+// connection usable; I/O and protocol errors mark it broken.
+func (c *redisConn) do(args ...string) (interface{}, error) {
+	c.conn.SetDeadline(time.Now().Add(redisIOTimeout))
+
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&builder, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if _, err := io.WriteString(c.conn, builder.String()); err != nil {
+		c.broken = true
+		return nil, err
+	}
+
+	reply, err := c.readReply()
+	if err != nil {
+		var replyErr redisError
+		if !errors.As(err, &replyErr) {
+			c.broken = true
+		}
+		return nil, err
+	}
+	return reply, nil
+}
+
+func (c *redisConn) readReply() (interface{}, error) {
+	line, err := c.readLine()
+	if err != nil {
+		return nil, err
+	}
+	if line == "" {
+		return nil, errors.New("boş Redis yanıtı")
+	}
+
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, redisError(line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		size, err := strconv.Atoi(line[1:])
+		if err != nil || size < 0 {
+			return nil, err
+		}
+		data := make([]byte, size+2)
+		if _, err := io.ReadFull(c.reader, data); err != nil {
+			return nil, err
+		}
+		return data[:size], nil
+	case '*':
+		count, err := strconv.Atoi(line[1:])
+		if err != nil || count < 0 {
+			return nil, err
+		}
+		items := make([]interface{}, count)
+		for i := range items {
+			item, err := c.readReply()
+			var replyErr redisError
+			if errors.As(err, &replyErr) {
+				item = replyErr
+			} else if err != nil {
+				return nil, err
+			}
+			items[i] = item
+		}
+		return items, nil
+	}
+	return nil, fmt.Errorf("bilinmeyen Redis yanıt tipi: %q", line[0])
+}
+
+func (c *redisConn) readLine() (string, error) {
+	line, err := c.reader.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(line, "\r\n"), nil
+}
+
+func escapeRedisPattern(value string) string {
+	var builder strings.Builder
+	for _, r := range value {
+		switch r {
+		case '*', '?', '[', ']', '\\':
+			builder.WriteByte('\\')
+		}
+		builder.WriteRune(r)
+	}
+	return builder.String()
+}
@@ -0,0 +1,158 @@
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+// The prefix contains glob characters on purpose: an unescaped SCAN pattern
+// would also match the foreign "test1:" key.
+const redisTestPrefix = "test[1]:"
+
+func startRedis(t *testing.T) (*miniredis.Miniredis, *RedisStore) {
+	t.Helper()
+
+	server := miniredis.RunT(t)
+	store, err := NewRedisStore("redis://"+server.Addr(), redisTestPrefix, NewJSONCodec())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return server, store
+}
+
+func TestRedisStoreScanAndClear(t *testing.T) {
+	server, store := startRedis(t)
+	server.Set("test1:guild_1", "foreign")
+	server.Set("other", "foreign")
+
+	for i := 0; i < 5; i++ {
+		key := fmt.Sprintf("guild_%d", i)
+		if err := store.Save(key, &CacheEntry{Data: diskTestValue{Name: key, Count: i}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := store.Save("user_1", &CacheEntry{Data: diskTestValue{Name: "user"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	keys, err := store.Keys("guild_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(keys)
+	if len(keys) != 5 || keys[0] != "guild_0" || keys[4] != "guild_4" {
+		t.Errorf("keys = %v", keys)
+	}
+
+	if count, err := store.Len(); err != nil || count != 6 {
+		t.Errorf("Len = %d, %v, want 6", count, err)
+	}
+
+	ranged := make(map[string]diskTestValue)
+	if err := store.Range(func(key string, entry *CacheEntry) bool {
+		ranged[key] = entry.Data.(diskTestValue)
+		return true
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if len(ranged) != 6 || ranged["guild_3"].Count != 3 || ranged["user_1"].Name != "user" {
+		t.Errorf("Range = %v", ranged)
+	}
+
+	cleared, err := store.Clear()
+	if err != nil || cleared != 6 {
+		t.Fatalf("Clear = %d, %v, want 6", cleared, err)
+	}
+	if !server.Exists("test1:guild_1") || !server.Exists("other") {
+		t.Errorf("Clear prefix dışındaki anahtarları sildi: %v", server.Keys())
+	}
+	if count, _ := store.Len(); count != 0 {
+		t.Errorf("Len = %d after Clear", count)
+	}
+}
+
+func TestRedisStoreExpiry(t *testing.T) {
+	server, store := startRedis(t)
+
+	entry := &CacheEntry{Data: diskTestValue{Name: "guild"}, TTL: time.Second, StaleTTL: 2 * time.Second}
+	if err := store.Save("guild_1", entry); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Save("guild_2", &CacheEntry{Data: diskTestValue{Name: "kalıcı"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if ttl := server.TTL(redisTestPrefix + "guild_1"); ttl != 3*time.Second+time.Millisecond {
+		t.Errorf("TTL = %v, want TTL+StaleTTL", ttl)
+	}
+	if ttl := server.TTL(redisTestPrefix + "guild_2"); ttl != 0 {
+		t.Errorf("TTL'siz kayıt için TTL = %v", ttl)
+	}
+
+	server.FastForward(2 * time.Second)
+	if _, exists, err := store.Load("guild_1"); !exists || err != nil {
+		t.Fatalf("stale süresi dolmadan kayıt silindi: %v, %v", exists, err)
+	}
+
+	server.FastForward(2 * time.Second)
+	if _, exists, err := store.Load("guild_1"); exists || err != nil {
+		t.Errorf("Load = %v, %v, want expired", exists, err)
+	}
+	if _, exists, _ := store.Load("guild_2"); !exists {
+		t.Error("TTL'siz kayıt silindi")
+	}
+}
+
+func TestRedisStoreDropsBrokenConnections(t *testing.T) {
+	server, store := startRedis(t)
+	if err := store.Save("guild_1", &CacheEntry{Data: diskTestValue{Name: "guild"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	// A Redis error reply leaves the connection in the pool.
+	server.Set(redisTestPrefix+"counter", "not a number")
+	pooled := <-store.pool
+	store.pool <- pooled
+	if _, err := store.do("INCR", redisTestPrefix+"counter"); err == nil {
+		t.Fatal("INCR hata vermeliydi")
+	}
+	var replyErr redisError
+	if _, err := store.do("INCR", redisTestPrefix+"counter"); !errors.As(err, &replyErr) {
+		t.Fatalf("err = %v, want redisError", err)
+	}
+	if len(store.pool) != 1 || <-store.pool != pooled {
+		t.Fatal("Redis hata yanıtı bağlantıyı havuzdan düşürdü")
+	}
+
+	// Kill the socket under the pooled connection: the next call fails on
+	// I/O and must not put the connection back.
+	pooled.conn.Close()
+	store.pool <- pooled
+	if _, _, err := store.Load("guild_1"); err == nil {
+		t.Fatal("kapalı bağlantıyla Load hata vermeliydi")
+	}
+	if len(store.pool) != 0 {
+		t.Fatal("bozuk bağlantı havuza geri kondu")
+	}
+
+	entry, exists, err := store.Load("guild_1")
+	if err != nil || !exists || entry.Data.(diskTestValue).Name != "guild" {
+		t.Fatalf("yeni bağlantıyla Load = %v, %v, %v", entry, exists, err)
+	}
+
+	// A server restart kills every pooled connection at once.
+	server.Close()
+	if err := server.Restart(); err != nil {
+		t.Fatal(err)
+	}
+	store.Load("guild_1")
+	if _, exists, err := store.Load("guild_1"); err != nil || !exists {
+		t.Errorf("yeniden başlatma sonrası Load = %v, %v", exists, err)
+	}
+}
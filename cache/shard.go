@@ -0,0 +1,112 @@
+package cache
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// minShardCapacity keeps per-shard eviction meaningful: with fewer keys per
+// shard, uneven hashing would evict long before the cache is full.
+const minShardCapacity = 16
+
+// cacheShard owns one slice of the key space. Everything keyed by cache key
+// (the eviction policy, loaders, in-flight refreshes and hit counts) lives in
+// the shard and is guarded by its mutex, so keys in different shards never
+// contend.
+type cacheShard struct {
+	mutex      sync.Mutex
+	policy     EvictionPolicy
+	loaders    map[string]Loader
+	refreshing map[string]bool
+	hits       map[string]int
+}
+
+// shardSet is swapped as a whole by SetShards and SetEvictionPolicy, so
+// readers always see a slice and mask that belong together.
+type shardSet struct {
+	shards []*cacheShard
+	mask   uint64
+}
+
+type cacheCounters struct {
+	hits          atomic.Int64
+	misses        atomic.Int64
+	evictions     atomic.Int64
+	refreshes     atomic.Int64
+	refreshErrors atomic.Int64
+	staleHits     atomic.Int64
+	revalidations atomic.Int64
+	storeErrors   atomic.Int64
+	lastCleanup   atomic.Int64
+}
+
+func newCacheShard(policy EvictionPolicy) *cacheShard {
+	return &cacheShard{
+		policy:     policy,
+		loaders:    make(map[string]Loader),
+		refreshing: make(map[string]bool),
+		hits:       make(map[string]int),
+	}
+}
+
+// recordHit counts a read of entry. Disk and Redis stores hand out a fresh
+// copy on every load, so the count lives here and is written back with the
+// entry's next save.
+func (s *cacheShard) recordHit(key string, entry *CacheEntry) int {
+	entry.Hits = s.hitCount(key, entry) + 1
+	s.hits[key] = entry.Hits
+	return entry.Hits
+}
+
+func (s *cacheShard) hitCount(key string, entry *CacheEntry) int {
+	if hits, exists := s.hits[key]; exists {
+		return hits
+	}
+	return entry.Hits
+}
+
+func (s *shardSet) get(key string) *cacheShard {
+	return s.shards[hashKey(key)&s.mask]
+}
+
+// defaultShardCount scales with GOMAXPROCS, rounded down to a power of two
+// so the shard index is a mask, and never splits maxSize below
+// minShardCapacity keys per shard.
+func defaultShardCount(maxSize int) int {
+	return clampShardCount(4*runtime.GOMAXPROCS(0), maxSize)
+}
+
+func clampShardCount(count, maxSize int) int {
+	if limit := maxSize / minShardCapacity; count > limit {
+		count = limit
+	}
+	shards := 1
+	for shards*2 <= count {
+		shards *= 2
+	}
+	return shards
+}
+
+func shardCapacity(maxSize, shards int) int {
+	return (maxSize + shards - 1) / shards
+}
+
+// hashKey is an allocation-free FNV-1a, shared by shard selection, the
+// memory store and the TinyLFU sketch.
+func hashKey(key string) uint64 {
+	hash := uint64(14695981039346656037)
+	for i := 0; i < len(key); i++ {
+		hash ^= uint64(key[i])
+		hash *= 1099511628211
+	}
+	return hash
+}
+
+func (c *cacheCounters) lastCleanupTime() time.Time {
+	if nanos := c.lastCleanup.Load(); nanos != 0 {
+		return time.Unix(0, nanos)
+	}
+	return time.Time{}
+}
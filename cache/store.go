@@ -0,0 +1,162 @@
+package cache
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+
+	"discord-user-api/config"
+)
+
+const (
+	StoreMemory = "memory"
+	StoreDisk   = "disk"
+	StoreRedis  = "redis"
+)
+
+// Store is where Cache keeps its entries. Loaders, refresh bookkeeping and
+// statistics stay in the Cache itself, so a Store only has to persist
+// entries; anything other than MemoryStore goes through a Codec and returns
+// a fresh copy from every Load.
+type Store interface {
+	Name() string
+	Load(key string) (*CacheEntry, bool, error)
+	Save(key string, entry *CacheEntry) error
+	Delete(key string) (bool, error)
+	Keys(prefix string) ([]string, error)
+	Range(fn func(key string, entry *CacheEntry) bool) error
+	Len() (int, error)
+	Clear() (int, error)
+	Close() error
+}
+
+// OpenStore builds the store selected in the cache configuration.
+func OpenStore(cfg config.CacheConfig) (Store, error) {
+	switch strings.ToLower(cfg.Store) {
+	case "", StoreMemory:
+		return NewMemoryStore(), nil
+	case StoreDisk:
+		return NewDiskStore(cfg.DiskPath, NewJSONCodec())
+	case StoreRedis:
+		return NewRedisStore(cfg.RedisURL, cfg.RedisPrefix, NewJSONCodec())
+	}
+	return nil, fmt.Errorf("bilinmeyen cache store: %q (memory, disk veya redis olmalı)", cfg.Store)
+}
+
+const memoryStoreShards = 64
+
+// MemoryStore splits its map so that Cache shards, which hash keys the same
+// way, mostly land on different locks.
+type MemoryStore struct {
+	shards [memoryStoreShards]memoryStoreShard
+}
+
+type memoryStoreShard struct {
+	mutex sync.RWMutex
+	data  map[string]*CacheEntry
+}
+
+func NewMemoryStore() *MemoryStore {
+	store := &MemoryStore{}
+	for i := range store.shards {
+		store.shards[i].data = make(map[string]*CacheEntry)
+	}
+	return store
+}
+
+func (s *MemoryStore) Name() string {
+	return StoreMemory
+}
+
+func (s *MemoryStore) Load(key string) (*CacheEntry, bool, error) {
+	shard := s.shard(key)
+	shard.mutex.RLock()
+	defer shard.mutex.RUnlock()
+	entry, exists := shard.data[key]
+	return entry, exists, nil
+}
+
+func (s *MemoryStore) Save(key string, entry *CacheEntry) error {
+	shard := s.shard(key)
+	shard.mutex.Lock()
+	defer shard.mutex.Unlock()
+	shard.data[key] = entry
+	return nil
+}
+
+func (s *MemoryStore) Delete(key string) (bool, error) {
+	shard := s.shard(key)
+	shard.mutex.Lock()
+	defer shard.mutex.Unlock()
+	_, exists := shard.data[key]
+	delete(shard.data, key)
+	return exists, nil
+}
+
+func (s *MemoryStore) Keys(prefix string) ([]string, error) {
+	var keys []string
+	for i := range s.shards {
+		shard := &s.shards[i]
+		shard.mutex.RLock()
+		for key := range shard.data {
+			if strings.HasPrefix(key, prefix) {
+				keys = append(keys, key)
+			}
+		}
+		shard.mutex.RUnlock()
+	}
+	return keys, nil
+}
+
+// Range holds one shard's read lock at a time, so fn must not call back
+// into the store for writes.
+func (s *MemoryStore) Range(fn func(key string, entry *CacheEntry) bool) error {
+	for i := range s.shards {
+		shard := &s.shards[i]
+		shard.mutex.RLock()
+		for key, entry := range shard.data {
+			if !fn(key, entry) {
+				shard.mutex.RUnlock()
+				return nil
+			}
+		}
+		shard.mutex.RUnlock()
+	}
+	return nil
+}
+
+func (s *MemoryStore) Len() (int, error) {
+	count := 0
+	for i := range s.shards {
+		shard := &s.shards[i]
+		shard.mutex.RLock()
+		count += len(shard.data)
+		shard.mutex.RUnlock()
+	}
+	return count, nil
+}
+
+func (s *MemoryStore) Clear() (int, error) {
+	count := 0
+	for i := range s.shards {
+		shard := &s.shards[i]
+		shard.mutex.Lock()
+		count += len(shard.data)
+		shard.data = make(map[string]*CacheEntry)
+		shard.mutex.Unlock()
+	}
+	return count, nil
+}
+
+func (s *MemoryStore) Close() error {
+	return nil
+}
+
+func (s *MemoryStore) shard(key string) *memoryStoreShard {
+	return &s.shards[hashKey(key)%memoryStoreShards]
+}
+
+func logStoreError(store Store, operation, key string, err error) {
+	log.Printf("⚠️ Cache store hatası (%s %s, %s): %v", store.Name(), operation, key, err)
+}
@@ -0,0 +1,172 @@
+package cdn
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"discord-user-api/snowflake"
+)
+
+const (
+	BaseURL  = "https://cdn.discordapp.com"
+	MediaURL = "https://media.discordapp.net"
+)
+
+type Format string
+
+const (
+	FormatPNG  Format = "png"
+	FormatJPEG Format = "jpg"
+	FormatWebP Format = "webp"
+	FormatGIF  Format = "gif"
+)
+
+type Options struct {
+	Size   int
+	Format Format
+}
+
+func ParseFormat(value string) (Format, error) {
+	switch strings.ToLower(value) {
+	case "":
+		return "", nil
+	case "png":
+		return FormatPNG, nil
+	case "jpg", "jpeg":
+		return FormatJPEG, nil
+	case "webp":
+		return FormatWebP, nil
+	case "gif":
+		return FormatGIF, nil
+	}
+	return "", fmt.Errorf("desteklenmeyen görsel formatı: %s", value)
+}
+
+func ValidSize(size int) bool {
+	return size >= 16 && size <= 4096 && size&(size-1) == 0
+}
+
+func (o Options) Validate() error {
+	if o.Size != 0 && !ValidSize(o.Size) {
+		return fmt.Errorf("geçersiz görsel boyutu: %d (16-4096 arası 2'nin kuvveti olmalı)", o.Size)
+	}
+	return nil
+}
+
+func IsAnimated(hash string) bool {
+	return strings.HasPrefix(hash, "a_")
+}
+
+func UserAvatar(userID snowflake.Snowflake, discriminator, hash string, opts Options) string {
+	if hash == "" {
+		return DefaultUserAvatar(userID, discriminator)
+	}
+	return build(fmt.Sprintf("avatars/%s/%s", userID, hash), hash, opts)
+}
+
+func DefaultUserAvatar(userID snowflake.Snowflake, discriminator string) string {
+	var index uint64
+	if discriminator == "" || discriminator == "0" {
+		index = (uint64(userID) >> 22) % 6
+	} else if value, err := strconv.Atoi(discriminator); err == nil {
+		index = uint64(value % 5)
+	}
+	return fmt.Sprintf("%s/embed/avatars/%d.png", BaseURL, index)
+}
+
+func UserBanner(userID snowflake.Snowflake, hash string, opts Options) string {
+	if hash == "" {
+		return ""
+	}
+	return build(fmt.Sprintf("banners/%s/%s", userID, hash), hash, opts)
+}
+
+func GuildMemberAvatar(guildID, userID snowflake.Snowflake, hash string, opts Options) string {
+	if hash == "" {
+		return ""
+	}
+	return build(fmt.Sprintf("guilds/%s/users/%s/avatars/%s", guildID, userID, hash), hash, opts)
+}
+
+func GuildIcon(guildID snowflake.Snowflake, hash string, opts Options) string {
+	if hash == "" {
+		return ""
+	}
+	return build(fmt.Sprintf("icons/%s/%s", guildID, hash), hash, opts)
+}
+
+func GuildSplash(guildID snowflake.Snowflake, hash string, opts Options) string {
+	if hash == "" {
+		return ""
+	}
+	return build(fmt.Sprintf("splashes/%s/%s", guildID, hash), hash, opts)
+}
+
+func GuildDiscoverySplash(guildID snowflake.Snowflake, hash string, opts Options) string {
+	if hash == "" {
+		return ""
+	}
+	return build(fmt.Sprintf("discovery-splashes/%s/%s", guildID, hash), hash, opts)
+}
+
+func GuildBanner(guildID snowflake.Snowflake, hash string, opts Options) string {
+	if hash == "" {
+		return ""
+	}
+	return build(fmt.Sprintf("banners/%s/%s", guildID, hash), hash, opts)
+}
+
+func RoleIcon(roleID snowflake.Snowflake, hash string, opts Options) string {
+	if hash == "" {
+		return ""
+	}
+	return build(fmt.Sprintf("role-icons/%s/%s", roleID, hash), hash, opts)
+}
+
+func Emoji(emojiID snowflake.Snowflake, animated bool, opts Options) string {
+	if !emojiID.IsValid() {
+		return ""
+	}
+	hash := ""
+	if animated {
+		hash = "a_"
+	}
+	return build(fmt.Sprintf("emojis/%s", emojiID), hash, opts)
+}
+
+// Sticker builds the URL for a sticker of the given format type (1 PNG,
+// 2 APNG, 3 Lottie, 4 GIF). Lottie stickers are served as JSON and GIF
+// stickers from the media proxy, so the requested format only applies to
+// PNG/APNG stickers.
+func Sticker(stickerID snowflake.Snowflake, formatType int, opts Options) string {
+	if !stickerID.IsValid() {
+		return ""
+	}
+	switch formatType {
+	case 3:
+		return fmt.Sprintf("%s/stickers/%s.json", BaseURL, stickerID)
+	case 4:
+		return fmt.Sprintf("%s/stickers/%s.gif", MediaURL, stickerID)
+	}
+	return build(fmt.Sprintf("stickers/%s", stickerID), "", opts)
+}
+
+func build(path, hash string, opts Options) string {
+	format := opts.Format
+	if format == "" {
+		format = FormatPNG
+		if IsAnimated(hash) {
+			format = FormatGIF
+		}
+	}
+	if format == FormatGIF && !IsAnimated(hash) {
+		format = FormatPNG
+	}
+
+	url := fmt.Sprintf("%s/%s.%s", BaseURL, path, format)
+	if opts.Size != 0 {
+		url += "?size=" + strconv.Itoa(opts.Size)
+	}
+	return url
+}
@@ -0,0 +1,102 @@
+package cdn
+
+import (
+	"testing"
+
+	"discord-user-api/snowflake"
+)
+
+const (
+	userID  snowflake.Snowflake = 175928847299117063
+	guildID snowflake.Snowflake = 41771983423143937
+)
+
+func TestURLs(t *testing.T) {
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"static avatar", UserAvatar(userID, "0", "abc123", Options{}), "https://cdn.discordapp.com/avatars/175928847299117063/abc123.png"},
+		{"animated avatar", UserAvatar(userID, "0", "a_abc123", Options{}), "https://cdn.discordapp.com/avatars/175928847299117063/a_abc123.gif"},
+		{"animated avatar as webp", UserAvatar(userID, "0", "a_abc123", Options{Format: FormatWebP, Size: 256}), "https://cdn.discordapp.com/avatars/175928847299117063/a_abc123.webp?size=256"},
+		{"gif falls back to png", UserAvatar(userID, "0", "abc123", Options{Format: FormatGIF}), "https://cdn.discordapp.com/avatars/175928847299117063/abc123.png"},
+		{"jpeg with size", GuildIcon(guildID, "icon1", Options{Format: FormatJPEG, Size: 1024}), "https://cdn.discordapp.com/icons/41771983423143937/icon1.jpg?size=1024"},
+		{"member avatar", GuildMemberAvatar(guildID, userID, "a_member", Options{}), "https://cdn.discordapp.com/guilds/41771983423143937/users/175928847299117063/avatars/a_member.gif"},
+		{"user banner", UserBanner(userID, "banner1", Options{}), "https://cdn.discordapp.com/banners/175928847299117063/banner1.png"},
+		{"guild splash", GuildSplash(guildID, "splash1", Options{}), "https://cdn.discordapp.com/splashes/41771983423143937/splash1.png"},
+		{"discovery splash", GuildDiscoverySplash(guildID, "splash1", Options{}), "https://cdn.discordapp.com/discovery-splashes/41771983423143937/splash1.png"},
+		{"role icon", RoleIcon(userID, "role1", Options{}), "https://cdn.discordapp.com/role-icons/175928847299117063/role1.png"},
+		{"missing hash", GuildBanner(guildID, "", Options{}), ""},
+		{"static emoji", Emoji(userID, false, Options{}), "https://cdn.discordapp.com/emojis/175928847299117063.png"},
+		{"animated emoji", Emoji(userID, true, Options{Size: 64}), "https://cdn.discordapp.com/emojis/175928847299117063.gif?size=64"},
+		{"png sticker", Sticker(userID, 1, Options{Format: FormatWebP}), "https://cdn.discordapp.com/stickers/175928847299117063.webp"},
+		{"lottie sticker", Sticker(userID, 3, Options{Format: FormatPNG}), "https://cdn.discordapp.com/stickers/175928847299117063.json"},
+		{"gif sticker", Sticker(userID, 4, Options{}), "https://media.discordapp.net/stickers/175928847299117063.gif"},
+	}
+
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s = %q, want %q", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestDefaultUserAvatar(t *testing.T) {
+	tests := []struct {
+		name          string
+		discriminator string
+		want          string
+	}{
+		// (175928847299117063 >> 22) % 6 == 2
+		{"new username", "0", "https://cdn.discordapp.com/embed/avatars/2.png"},
+		{"no discriminator", "", "https://cdn.discordapp.com/embed/avatars/2.png"},
+		{"legacy discriminator", "1234", "https://cdn.discordapp.com/embed/avatars/4.png"},
+		{"legacy discriminator divisible by five", "0005", "https://cdn.discordapp.com/embed/avatars/0.png"},
+	}
+
+	for _, tt := range tests {
+		if got := DefaultUserAvatar(userID, tt.discriminator); got != tt.want {
+			t.Errorf("%s: DefaultUserAvatar = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	if got := UserAvatar(userID, "1234", "", Options{Size: 128}); got != "https://cdn.discordapp.com/embed/avatars/4.png" {
+		t.Errorf("UserAvatar without hash = %q", got)
+	}
+}
+
+func TestSizeValidation(t *testing.T) {
+	for _, size := range []int{16, 32, 64, 128, 256, 512, 1024, 2048, 4096} {
+		if !ValidSize(size) {
+			t.Errorf("ValidSize(%d) = false", size)
+		}
+	}
+	for _, size := range []int{-16, 0, 8, 15, 100, 8192} {
+		if ValidSize(size) {
+			t.Errorf("ValidSize(%d) = true", size)
+		}
+	}
+
+	if err := (Options{}).Validate(); err != nil {
+		t.Errorf("default size: %v", err)
+	}
+	if err := (Options{Size: 512}).Validate(); err != nil {
+		t.Errorf("size 512: %v", err)
+	}
+	if err := (Options{Size: 300}).Validate(); err == nil {
+		t.Error("size 300 kabul edildi")
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	tests := map[string]Format{"": "", "PNG": FormatPNG, "jpeg": FormatJPEG, "jpg": FormatJPEG, "webp": FormatWebP, "gif": FormatGIF}
+	for value, want := range tests {
+		if got, err := ParseFormat(value); err != nil || got != want {
+			t.Errorf("ParseFormat(%q) = %q, %v, want %q", value, got, err, want)
+		}
+	}
+	if _, err := ParseFormat("bmp"); err == nil {
+		t.Error("bmp kabul edildi")
+	}
+}
@@ -1,133 +1,255 @@
-package config
-
-import (
-	"os"
-	"strconv"
-	"time"
-
-	"github.com/joho/godotenv"
-)
-
-type Config struct {
-	Server     ServerConfig
-	Discord    DiscordConfig
-	Cache      CacheConfig
-	RateLimit  RateLimitConfig
-	Logging    LoggingConfig
-}
-
-type ServerConfig struct {
-	Host         string
-	Port         string
-	ReadTimeout  time.Duration
-	WriteTimeout time.Duration
-	IdleTimeout  time.Duration
-}
-
-type DiscordConfig struct {
-	Token         string
-	APIURL        string
-	APIVersion    string
-	RequestTimeout time.Duration
-	MaxRetries    int
-	RetryDelay    time.Duration
-}
-
-type CacheConfig struct {
-	Enabled           bool
-	TTL               time.Duration
-	MaxSize           int
-	CleanupInterval   time.Duration
-	AutoRefresh       bool
-	RefreshInterval   time.Duration
-}
-
-type RateLimitConfig struct {
-	Enabled           bool
-	RequestsPerMinute int
-	BurstSize         int
-}
-
-type LoggingConfig struct {
-	Level      string
-	Format     string
-	WithEmojis bool
-}
-
-func Load() (*Config, error) {
-	if err := godotenv.Load(); err != nil {
-		return nil, err
-	}
-
-	config := &Config{
-		Server: ServerConfig{
-			Host:         getEnv("HOST", "0.0.0.0"),
-			Port:         getEnv("PORT", "8080"),
-			ReadTimeout:  getDurationEnv("READ_TIMEOUT", 30*time.Second),
-			WriteTimeout: getDurationEnv("WRITE_TIMEOUT", 30*time.Second),
-			IdleTimeout:  getDurationEnv("IDLE_TIMEOUT", 60*time.Second),
-		},
-		Discord: DiscordConfig{
-			Token:         getEnv("DISCORD_TOKEN", ""),
-			APIURL:        getEnv("DISCORD_API_URL", "https://discord.com/api"),
-			APIVersion:    getEnv("DISCORD_API_VERSION", "v9"),
-			RequestTimeout: getDurationEnv("DISCORD_REQUEST_TIMEOUT", 30*time.Second),
-			MaxRetries:    getIntEnv("DISCORD_MAX_RETRIES", 3),
-			RetryDelay:    getDurationEnv("DISCORD_RETRY_DELAY", 1*time.Second),
-		},
-		Cache: CacheConfig{
-			Enabled:         getBoolEnv("CACHE_ENABLED", true),
-			TTL:             getDurationEnv("CACHE_TTL", 5*time.Minute),
-			MaxSize:         getIntEnv("CACHE_MAX_SIZE", 1000),
-			CleanupInterval: getDurationEnv("CACHE_CLEANUP_INTERVAL", 10*time.Minute),
-			AutoRefresh:     getBoolEnv("CACHE_AUTO_REFRESH", true),
-			RefreshInterval: getDurationEnv("CACHE_REFRESH_INTERVAL", 2*time.Minute),
-		},
-		RateLimit: RateLimitConfig{
-			Enabled:           getBoolEnv("RATE_LIMIT_ENABLED", true),
-			RequestsPerMinute: getIntEnv("RATE_LIMIT_REQUESTS_PER_MINUTE", 60),
-			BurstSize:         getIntEnv("RATE_LIMIT_BURST_SIZE", 10),
-		},
-		Logging: LoggingConfig{
-			Level:      getEnv("LOG_LEVEL", "info"),
-			Format:     getEnv("LOG_FORMAT", "text"),
-			WithEmojis: getBoolEnv("LOG_WITH_EMOJIS", true),
-		},
-	}
-
-	return config, nil
-}
-
-func getEnv(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
-	}
-	return defaultValue
-}
-
-func getIntEnv(key string, defaultValue int) int {
-	if value := os.Getenv(key); value != "" {
-		if intValue, err := strconv.Atoi(value); err == nil {
-			return intValue
-		}
-	}
-	return defaultValue
-}
-
-func getBoolEnv(key string, defaultValue bool) bool {
-	if value := os.Getenv(key); value != "" {
-		if boolValue, err := strconv.ParseBool(value); err == nil {
-			return boolValue
-		}
-	}
-	return defaultValue
-}
-
-func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
-	if value := os.Getenv(key); value != "" {
-		if duration, err := time.ParseDuration(value); err == nil {
-			return duration
-		}
-	}
-	return defaultValue
-} 
\ No newline at end of file
+package config
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+)
+
+type Config struct {
+	Server    ServerConfig
+	Discord   DiscordConfig
+	Cache     CacheConfig
+	RateLimit RateLimitConfig
+	Logging   LoggingConfig
+}
+
+type ServerConfig struct {
+	Host         string
+	Port         string
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+	IdleTimeout  time.Duration
+}
+
+type DiscordConfig struct {
+	Token                   string
+	Tokens                  []string
+	APIURL                  string
+	APIVersion              string
+	RequestTimeout          time.Duration
+	MaxRetries              int
+	RetryDelay              time.Duration
+	MaxRetryDelay           time.Duration
+	RetryBudgetRatio        float64
+	RetryBudgetMinPerSecond float64
+	ProxyURL                string
+	MaxIdleConns            int
+	MaxIdleConnsPerHost     int
+	MaxConnsPerHost         int
+	IdleConnTimeout         time.Duration
+	KeepAlive               time.Duration
+	DisableKeepAlives       bool
+	TLSMinVersion           string
+	CABundle                string
+	UserAgent               string
+	Gateway                 GatewayConfig
+	CircuitBreaker          CircuitBreakerConfig
+	Recording               RecordingConfig
+}
+
+type GatewayConfig struct {
+	Enabled           bool
+	URL               string
+	Intents           int
+	Compress          bool
+	LargeThreshold    int
+	HandshakeTimeout  time.Duration
+	MaxReconnectDelay time.Duration
+}
+
+type CircuitBreakerConfig struct {
+	Enabled             bool
+	FailureThreshold    int
+	SuccessThreshold    int
+	OpenTimeout         time.Duration
+	HalfOpenMaxRequests int
+}
+
+// RecordingConfig controls upstream traffic capture. Mode is "off",
+// "record" (pass through and write fixtures) or "replay" (serve fixtures,
+// never call Discord).
+type RecordingConfig struct {
+	Mode       string
+	FixtureDir string
+}
+
+type CacheConfig struct {
+	Enabled            bool
+	TTL                time.Duration
+	MaxSize            int
+	CleanupInterval    time.Duration
+	AutoRefresh        bool
+	RefreshInterval    time.Duration
+	RefreshConcurrency int
+	RefreshTimeout     time.Duration
+	StaleTTL           time.Duration
+	Store              string
+	DiskPath           string
+	RedisURL           string
+	RedisPrefix        string
+	EvictionPolicy     string
+	Shards             int
+}
+
+type RateLimitConfig struct {
+	Enabled           bool
+	RequestsPerMinute int
+	BurstSize         int
+}
+
+type LoggingConfig struct {
+	Level      string
+	Format     string
+	WithEmojis bool
+}
+
+func Load() (*Config, error) {
+	if err := godotenv.Load(); err != nil {
+		return nil, err
+	}
+
+	config := &Config{
+		Server: ServerConfig{
+			Host:         getEnv("HOST", "0.0.0.0"),
+			Port:         getEnv("PORT", "8080"),
+			ReadTimeout:  getDurationEnv("READ_TIMEOUT", 30*time.Second),
+			WriteTimeout: getDurationEnv("WRITE_TIMEOUT", 30*time.Second),
+			IdleTimeout:  getDurationEnv("IDLE_TIMEOUT", 60*time.Second),
+		},
+		Discord: DiscordConfig{
+			Token:                   getEnv("DISCORD_TOKEN", ""),
+			Tokens:                  getListEnv("DISCORD_TOKENS", nil),
+			APIURL:                  getEnv("DISCORD_API_URL", "https://discord.com/api"),
+			APIVersion:              getEnv("DISCORD_API_VERSION", "v9"),
+			RequestTimeout:          getDurationEnv("DISCORD_REQUEST_TIMEOUT", 30*time.Second),
+			MaxRetries:              getIntEnv("DISCORD_MAX_RETRIES", 3),
+			RetryDelay:              getDurationEnv("DISCORD_RETRY_DELAY", 1*time.Second),
+			MaxRetryDelay:           getDurationEnv("DISCORD_MAX_RETRY_DELAY", 30*time.Second),
+			RetryBudgetRatio:        getFloatEnv("DISCORD_RETRY_BUDGET_RATIO", 0.2),
+			RetryBudgetMinPerSecond: getFloatEnv("DISCORD_RETRY_BUDGET_MIN_PER_SECOND", 1),
+			ProxyURL:                getEnv("DISCORD_PROXY_URL", ""),
+			MaxIdleConns:            getIntEnv("DISCORD_MAX_IDLE_CONNS", 100),
+			MaxIdleConnsPerHost:     getIntEnv("DISCORD_MAX_IDLE_CONNS_PER_HOST", 10),
+			MaxConnsPerHost:         getIntEnv("DISCORD_MAX_CONNS_PER_HOST", 0),
+			IdleConnTimeout:         getDurationEnv("DISCORD_IDLE_CONN_TIMEOUT", 90*time.Second),
+			KeepAlive:               getDurationEnv("DISCORD_KEEP_ALIVE", 30*time.Second),
+			DisableKeepAlives:       getBoolEnv("DISCORD_DISABLE_KEEP_ALIVES", false),
+			TLSMinVersion:           getEnv("DISCORD_TLS_MIN_VERSION", "1.2"),
+			CABundle:                getEnv("DISCORD_CA_BUNDLE", ""),
+			UserAgent:               getEnv("DISCORD_USER_AGENT", "Discord-API-Client/1.0"),
+			Gateway: GatewayConfig{
+				Enabled:           getBoolEnv("DISCORD_GATEWAY_ENABLED", false),
+				URL:               getEnv("DISCORD_GATEWAY_URL", "wss://gateway.discord.gg"),
+				Intents:           getIntEnv("DISCORD_GATEWAY_INTENTS", 1|2|8),
+				Compress:          getBoolEnv("DISCORD_GATEWAY_COMPRESS", true),
+				LargeThreshold:    getIntEnv("DISCORD_GATEWAY_LARGE_THRESHOLD", 250),
+				HandshakeTimeout:  getDurationEnv("DISCORD_GATEWAY_HANDSHAKE_TIMEOUT", 15*time.Second),
+				MaxReconnectDelay: getDurationEnv("DISCORD_GATEWAY_MAX_RECONNECT_DELAY", 2*time.Minute),
+			},
+			CircuitBreaker: CircuitBreakerConfig{
+				Enabled:             getBoolEnv("DISCORD_CIRCUIT_BREAKER_ENABLED", true),
+				FailureThreshold:    getIntEnv("DISCORD_CIRCUIT_FAILURE_THRESHOLD", 5),
+				SuccessThreshold:    getIntEnv("DISCORD_CIRCUIT_SUCCESS_THRESHOLD", 2),
+				OpenTimeout:         getDurationEnv("DISCORD_CIRCUIT_OPEN_TIMEOUT", 30*time.Second),
+				HalfOpenMaxRequests: getIntEnv("DISCORD_CIRCUIT_HALF_OPEN_MAX_REQUESTS", 1),
+			},
+			Recording: RecordingConfig{
+				Mode:       getEnv("DISCORD_RECORDING_MODE", "off"),
+				FixtureDir: getEnv("DISCORD_FIXTURE_DIR", "fixtures"),
+			},
+		},
+		Cache: CacheConfig{
+			Enabled:            getBoolEnv("CACHE_ENABLED", true),
+			TTL:                getDurationEnv("CACHE_TTL", 5*time.Minute),
+			MaxSize:            getIntEnv("CACHE_MAX_SIZE", 1000),
+			CleanupInterval:    getDurationEnv("CACHE_CLEANUP_INTERVAL", 10*time.Minute),
+			AutoRefresh:        getBoolEnv("CACHE_AUTO_REFRESH", true),
+			RefreshInterval:    getDurationEnv("CACHE_REFRESH_INTERVAL", 2*time.Minute),
+			RefreshConcurrency: getIntEnv("CACHE_REFRESH_CONCURRENCY", 4),
+			RefreshTimeout:     getDurationEnv("CACHE_REFRESH_TIMEOUT", 30*time.Second),
+			StaleTTL:           getDurationEnv("CACHE_STALE_TTL", 10*time.Minute),
+			Store:              getEnv("CACHE_STORE", "memory"),
+			DiskPath:           getEnv("CACHE_DISK_PATH", "data/cache"),
+			RedisURL:           getEnv("CACHE_REDIS_URL", "redis://localhost:6379/0"),
+			RedisPrefix:        getEnv("CACHE_REDIS_PREFIX", "discord-user-api:"),
+			EvictionPolicy:     getEnv("CACHE_EVICTION_POLICY", "lru"),
+			Shards:             getIntEnv("CACHE_SHARDS", 0),
+		},
+		RateLimit: RateLimitConfig{
+			Enabled:           getBoolEnv("RATE_LIMIT_ENABLED", true),
+			RequestsPerMinute: getIntEnv("RATE_LIMIT_REQUESTS_PER_MINUTE", 60),
+			BurstSize:         getIntEnv("RATE_LIMIT_BURST_SIZE", 10),
+		},
+		Logging: LoggingConfig{
+			Level:      getEnv("LOG_LEVEL", "info"),
+			Format:     getEnv("LOG_FORMAT", "text"),
+			WithEmojis: getBoolEnv("LOG_WITH_EMOJIS", true),
+		},
+	}
+
+	if len(config.Discord.Tokens) == 0 && config.Discord.Token != "" {
+		config.Discord.Tokens = []string{config.Discord.Token}
+	}
+
+	return config, nil
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+func getListEnv(key string, defaultValue []string) []string {
+	if value := os.Getenv(key); value != "" {
+		var items []string
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		return items
+	}
+	return defaultValue
+}
+
+func getIntEnv(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if intValue, err := strconv.Atoi(value); err == nil {
+			return intValue
+		}
+	}
+	return defaultValue
+}
+
+func getFloatEnv(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatValue
+		}
+	}
+	return defaultValue
+}
+
+func getBoolEnv(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if boolValue, err := strconv.ParseBool(value); err == nil {
+			return boolValue
+		}
+	}
+	return defaultValue
+}
+
+func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if duration, err := time.ParseDuration(value); err == nil {
+			return duration
+		}
+	}
+	return defaultValue
+}
@@ -0,0 +1,83 @@
+package discord
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/url"
+	"strconv"
+	"time"
+
+	"discord-user-api/models"
+	"discord-user-api/snowflake"
+)
+
+const maxAuditLogPageSize = 100
+
+type AuditLogQuery struct {
+	UserID     snowflake.Snowflake
+	ActionType models.AuditLogEvent
+	Before     snowflake.Snowflake
+	After      snowflake.Snowflake
+	Limit      int
+}
+
+func (q AuditLogQuery) values() url.Values {
+	values := url.Values{}
+	values.Set("limit", strconv.Itoa(q.Limit))
+	if q.UserID.IsValid() {
+		values.Set("user_id", q.UserID.String())
+	}
+	if q.ActionType > 0 {
+		values.Set("action_type", strconv.Itoa(int(q.ActionType)))
+	}
+	if q.Before.IsValid() {
+		values.Set("before", q.Before.String())
+	}
+	if q.After.IsValid() {
+		values.Set("after", q.After.String())
+	}
+	return values
+}
+
+func (c *Client) GetGuildAuditLog(ctx context.Context, guildID snowflake.Snowflake, query AuditLogQuery) (*models.AuditLog, error) {
+	if query.Limit <= 0 || query.Limit > maxAuditLogPageSize {
+		query.Limit = 50
+	}
+
+	values := query.values()
+	cacheKey := fmt.Sprintf("guild_audit_log_%s_%s", guildID, values.Encode())
+
+	cached, exists, fallback := c.cachedValue(ctx, cacheKey)
+	if exists {
+		if auditLog, ok := cached.(*models.AuditLog); ok {
+			log.Printf("📤 Cache'den audit log getirildi: %s (%d kayıt)", guildID, len(auditLog.AuditLogEntries))
+			return auditLog, nil
+		}
+	}
+
+	url := fmt.Sprintf("%s/%s/guilds/%s/audit-logs?%s", c.config.Discord.APIURL, c.config.Discord.APIVersion, guildID, values.Encode())
+
+	auditLog, err := c.coalescedRequest(ctx, cacheKey, "GET", url, decodeAuditLog, func(value interface{}) {
+		c.cache.SetWithTTL(cacheKey, value, 30*time.Second)
+	})
+	if err != nil {
+		if stale, ok := c.serveStale(ctx, cacheKey, fallback, err).(*models.AuditLog); ok {
+			return stale, nil
+		}
+		return nil, fmt.Errorf("audit log getirilemedi: %w", err)
+	}
+
+	auditLogData := auditLog.(*models.AuditLog)
+
+	log.Printf("✅ Audit log başarıyla getirildi: %s (%d kayıt)", guildID, len(auditLogData.AuditLogEntries))
+	return auditLogData, nil
+}
+
+func decodeAuditLog(body io.Reader) (interface{}, error) {
+	var auditLog models.AuditLog
+	err := json.NewDecoder(body).Decode(&auditLog)
+	return &auditLog, err
+}
@@ -0,0 +1,337 @@
+package discord
+
+import (
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"discord-user-api/config"
+	"discord-user-api/models"
+	"discord-user-api/websocket"
+)
+
+const (
+	CircuitClosed   = "closed"
+	CircuitOpen     = "open"
+	CircuitHalfOpen = "half_open"
+)
+
+type breakerOutcome int
+
+const (
+	outcomeIgnored breakerOutcome = iota
+	outcomeSuccess
+	outcomeFailure
+	outcomeClientError
+)
+
+type CircuitBreaker struct {
+	mutex     sync.Mutex
+	config    config.CircuitBreakerConfig
+	groups    map[string]*circuit
+	wsManager *websocket.WebSocketManager
+}
+
+type circuit struct {
+	group         string
+	state         string
+	failures      int
+	successes     int
+	probes        int
+	openedAt      time.Time
+	lastChange    time.Time
+	lastError     string
+	generation    int64
+	opens         int64
+	rejected      int64
+	totalFailures int64
+}
+
+type CircuitStats struct {
+	Group               string `json:"group"`
+	State               string `json:"state"`
+	ConsecutiveFailures int    `json:"consecutive_failures"`
+	Failures            int64  `json:"failures"`
+	Opens               int64  `json:"opens"`
+	Rejected            int64  `json:"rejected"`
+	LastChange          string `json:"last_change,omitempty"`
+	RetryIn             string `json:"retry_in,omitempty"`
+	LastError           string `json:"last_error,omitempty"`
+}
+
+// circuitPermit is handed out for every attempt that passes the breaker.
+// Exactly one outcome is recorded per permit so half-open probe slots are
+// always given back.
+type circuitPermit struct {
+	breaker    *CircuitBreaker
+	circuit    *circuit
+	generation int64
+	done       bool
+}
+
+func NewCircuitBreaker(cfg config.CircuitBreakerConfig) *CircuitBreaker {
+	if cfg.FailureThreshold <= 0 {
+		cfg.FailureThreshold = 1
+	}
+	if cfg.SuccessThreshold <= 0 {
+		cfg.SuccessThreshold = 1
+	}
+	if cfg.HalfOpenMaxRequests <= 0 {
+		cfg.HalfOpenMaxRequests = 1
+	}
+
+	return &CircuitBreaker{
+		config: cfg,
+		groups: make(map[string]*circuit),
+	}
+}
+
+func (b *CircuitBreaker) SetWebSocketManager(wsManager *websocket.WebSocketManager) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.wsManager = wsManager
+}
+
+// routeGroup maps a bucket route such as "GET /guilds/123/members" to the
+// top-level resource it belongs to ("guilds").
+func routeGroup(route string) string {
+	path := route
+	if i := strings.IndexByte(route, ' '); i >= 0 {
+		path = route[i+1:]
+	}
+
+	path = strings.TrimPrefix(path, "/")
+	if i := strings.IndexByte(path, '/'); i >= 0 {
+		path = path[:i]
+	}
+	if path == "" {
+		return "root"
+	}
+	return path
+}
+
+func (b *CircuitBreaker) Allow(group string) (*circuitPermit, error) {
+	if !b.config.Enabled {
+		return nil, nil
+	}
+
+	b.mutex.Lock()
+	c := b.circuit(group)
+	now := time.Now()
+
+	var event *models.CircuitBreakerEvent
+	if c.state == CircuitOpen && now.Sub(c.openedAt) >= b.config.OpenTimeout {
+		event = b.transition(c, CircuitHalfOpen, now)
+	}
+
+	var err error
+	switch c.state {
+	case CircuitOpen:
+		err = &CircuitOpenError{Group: group, RetryAfter: b.config.OpenTimeout - now.Sub(c.openedAt)}
+	case CircuitHalfOpen:
+		if c.probes >= b.config.HalfOpenMaxRequests {
+			err = &CircuitOpenError{Group: group, HalfOpen: true}
+		} else {
+			c.probes++
+		}
+	}
+	if err != nil {
+		c.rejected++
+	}
+	b.mutex.Unlock()
+
+	b.broadcast(event)
+	if err != nil {
+		return nil, err
+	}
+	return &circuitPermit{breaker: b, circuit: c, generation: c.generation}, nil
+}
+
+func (p *circuitPermit) Done(outcome breakerOutcome, err error) {
+	if p == nil || p.done {
+		return
+	}
+	p.done = true
+	p.breaker.record(p.circuit, p.generation, outcome, err)
+}
+
+func (b *CircuitBreaker) record(c *circuit, generation int64, outcome breakerOutcome, err error) {
+	b.mutex.Lock()
+	now := time.Now()
+
+	// Outcomes of requests admitted before the last state change must not
+	// decide the new state (e.g. a slow pre-open success closing a fresh
+	// half-open circuit).
+	if generation != c.generation {
+		if outcome == outcomeFailure {
+			c.totalFailures++
+		}
+		b.mutex.Unlock()
+		return
+	}
+
+	halfOpen := c.state == CircuitHalfOpen
+	if halfOpen && c.probes > 0 {
+		c.probes--
+	}
+
+	var event *models.CircuitBreakerEvent
+	switch outcome {
+	case outcomeSuccess:
+		c.failures = 0
+		if halfOpen {
+			c.successes++
+			if c.successes >= b.config.SuccessThreshold {
+				event = b.transition(c, CircuitClosed, now)
+			}
+		}
+
+	case outcomeClientError:
+		// A 4xx proves the upstream answers but says nothing about whether
+		// it has recovered, so it only counts as a success while closed.
+		if !halfOpen {
+			c.failures = 0
+		}
+
+	case outcomeFailure:
+		c.failures++
+		c.totalFailures++
+		if err != nil {
+			c.lastError = err.Error()
+		}
+		if halfOpen || (c.state == CircuitClosed && c.failures >= b.config.FailureThreshold) {
+			event = b.transition(c, CircuitOpen, now)
+		}
+	}
+	b.mutex.Unlock()
+
+	b.broadcast(event)
+}
+
+func (b *CircuitBreaker) circuit(group string) *circuit {
+	c, exists := b.groups[group]
+	if !exists {
+		c = &circuit{group: group, state: CircuitClosed, lastChange: time.Now()}
+		b.groups[group] = c
+	}
+	return c
+}
+
+func (b *CircuitBreaker) transition(c *circuit, state string, now time.Time) *models.CircuitBreakerEvent {
+	from := c.state
+	c.state = state
+	c.lastChange = now
+	c.generation++
+	c.successes = 0
+	c.probes = 0
+
+	switch state {
+	case CircuitOpen:
+		c.openedAt = now
+		c.opens++
+		log.Printf("🔌 Circuit açıldı: %s (%d ardışık hata, %v sonra tekrar denenecek): %s", c.group, c.failures, b.config.OpenTimeout, c.lastError)
+	case CircuitHalfOpen:
+		log.Printf("🔌 Circuit yarı açık: %s, deneme istekleri gönderiliyor", c.group)
+	case CircuitClosed:
+		c.failures = 0
+		log.Printf("✅ Circuit kapandı: %s", c.group)
+	}
+
+	return &models.CircuitBreakerEvent{
+		Group:     c.group,
+		From:      from,
+		To:        state,
+		Failures:  c.failures,
+		LastError: c.lastError,
+		Timestamp: now.UTC().Format(time.RFC3339),
+	}
+}
+
+func (b *CircuitBreaker) broadcast(event *models.CircuitBreakerEvent) {
+	if event == nil {
+		return
+	}
+
+	b.mutex.Lock()
+	wsManager := b.wsManager
+	b.mutex.Unlock()
+
+	if wsManager != nil {
+		wsManager.Broadcast(models.WebSocketEvent{
+			Type:      "circuit_breaker",
+			Data:      event,
+			Timestamp: event.Timestamp,
+		})
+	}
+}
+
+// States reports the current state of every route group seen so far.
+// Open circuits whose timeout has elapsed are reported as half-open.
+func (b *CircuitBreaker) States() map[string]string {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	states := make(map[string]string, len(b.groups))
+	now := time.Now()
+	for group, c := range b.groups {
+		states[group] = b.currentState(c, now)
+	}
+	return states
+}
+
+func (b *CircuitBreaker) Stats() []CircuitStats {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	now := time.Now()
+	stats := make([]CircuitStats, 0, len(b.groups))
+	for _, c := range b.groups {
+		entry := CircuitStats{
+			Group:               c.group,
+			State:               b.currentState(c, now),
+			ConsecutiveFailures: c.failures,
+			Failures:            c.totalFailures,
+			Opens:               c.opens,
+			Rejected:            c.rejected,
+			LastChange:          c.lastChange.UTC().Format(time.RFC3339),
+			LastError:           c.lastError,
+		}
+		if entry.State == CircuitOpen {
+			if remaining := b.config.OpenTimeout - now.Sub(c.openedAt); remaining > 0 {
+				entry.RetryIn = remaining.Round(time.Millisecond).String()
+			}
+		}
+		stats = append(stats, entry)
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Group < stats[j].Group
+	})
+	return stats
+}
+
+func (b *CircuitBreaker) currentState(c *circuit, now time.Time) string {
+	if c.state == CircuitOpen && now.Sub(c.openedAt) >= b.config.OpenTimeout {
+		return CircuitHalfOpen
+	}
+	return c.state
+}
+
+func (b *CircuitBreaker) Enabled() bool {
+	return b.config.Enabled
+}
+
+func outcomeForStatus(status int) breakerOutcome {
+	switch {
+	case status >= http.StatusInternalServerError:
+		return outcomeFailure
+	case status == http.StatusTooManyRequests:
+		return outcomeIgnored
+	case status >= http.StatusBadRequest:
+		return outcomeClientError
+	}
+	return outcomeSuccess
+}
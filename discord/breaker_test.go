@@ -0,0 +1,69 @@
+package discord
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"discord-user-api/config"
+)
+
+func openBreaker(t *testing.T) *CircuitBreaker {
+	t.Helper()
+	b := NewCircuitBreaker(config.CircuitBreakerConfig{
+		Enabled:          true,
+		FailureThreshold: 1,
+		SuccessThreshold: 1,
+		OpenTimeout:      time.Millisecond,
+	})
+
+	permit, err := b.Allow("guilds")
+	if err != nil {
+		t.Fatal(err)
+	}
+	permit.Done(outcomeFailure, errors.New("HTTP 502"))
+	time.Sleep(2 * time.Millisecond)
+	return b
+}
+
+func TestHalfOpenClientErrorIsIgnored(t *testing.T) {
+	b := openBreaker(t)
+
+	permit, err := b.Allow("guilds")
+	if err != nil {
+		t.Fatalf("yarı açık circuit deneme isteğini reddetti: %v", err)
+	}
+	permit.Done(outcomeForStatus(http.StatusNotFound), nil)
+
+	if state := b.States()["guilds"]; state != CircuitHalfOpen {
+		t.Fatalf("404 sonrası state = %q, want %q", state, CircuitHalfOpen)
+	}
+
+	// The probe slot must have been given back.
+	permit, err = b.Allow("guilds")
+	if err != nil {
+		t.Fatalf("deneme slotu geri verilmedi: %v", err)
+	}
+	permit.Done(outcomeForStatus(http.StatusOK), nil)
+
+	if state := b.States()["guilds"]; state != CircuitClosed {
+		t.Fatalf("200 sonrası state = %q, want %q", state, CircuitClosed)
+	}
+}
+
+func TestClosedClientErrorResetsFailures(t *testing.T) {
+	b := NewCircuitBreaker(config.CircuitBreakerConfig{Enabled: true, FailureThreshold: 2})
+
+	for _, status := range []int{http.StatusBadGateway, http.StatusForbidden, http.StatusBadGateway} {
+		permit, err := b.Allow("guilds")
+		if err != nil {
+			t.Fatal(err)
+		}
+		permit.Done(outcomeForStatus(status), nil)
+	}
+
+	if state := b.States()["guilds"]; state != CircuitClosed {
+		t.Fatalf("state = %q, want %q", state, CircuitClosed)
+	}
+}
@@ -0,0 +1,30 @@
+package discord
+
+import (
+	"discord-user-api/cache"
+	"discord-user-api/models"
+)
+
+// cachedTypes lists every value the client (and the gateway, through
+// Cache.Update) puts in the cache. They have to be registered so disk and
+// Redis stores hand back the same types the getters assert on.
+var cachedTypes = []interface{}{
+	[]models.DiscordGuild{},
+	&models.DiscordGuild{},
+	&models.DiscordProfile{},
+	[]models.DiscordGuildMember{},
+	&models.DiscordGuildMember{},
+	[]models.DiscordChannel{},
+	&models.DiscordChannel{},
+	[]models.DiscordMessage{},
+	[]models.DiscordRole{},
+	[]models.DiscordEmoji{},
+	[]models.DiscordSticker{},
+	[]models.DiscordBan{},
+	[]models.DiscordInvite{},
+	&models.AuditLog{},
+}
+
+func init() {
+	cache.RegisterType(cachedTypes...)
+}
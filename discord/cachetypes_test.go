@@ -0,0 +1,165 @@
+package discord
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"discord-user-api/cache"
+	"discord-user-api/discordtest"
+	"discord-user-api/models"
+)
+
+func cachedTypeSamples(seed discordtest.Seed) []interface{} {
+	guild := seed.Guilds[0]
+	channel := seed.Channels[0]
+	member := seed.Members[guild.ID][1]
+
+	return []interface{}{
+		seed.Guilds,
+		&guild,
+		&models.DiscordProfile{User: seed.Users[1], PremiumType: 2},
+		seed.Members[guild.ID],
+		&member,
+		seed.Channels,
+		&channel,
+		seed.Messages[channel.ID],
+		guild.Roles,
+		guild.Emojis,
+		guild.Stickers,
+		seed.Bans[guild.ID],
+		seed.Invites[guild.ID],
+		&models.AuditLog{AuditLogEntries: seed.AuditLogs[guild.ID], Users: seed.Users},
+	}
+}
+
+func TestCodecRoundTripsCachedTypes(t *testing.T) {
+	samples := make(map[reflect.Type]interface{})
+	for _, sample := range cachedTypeSamples(discordtest.DefaultSeed()) {
+		samples[reflect.TypeOf(sample)] = sample
+	}
+
+	codec := cache.NewJSONCodec()
+	for _, registered := range cachedTypes {
+		valueType := reflect.TypeOf(registered)
+		t.Run(valueType.String(), func(t *testing.T) {
+			sample, ok := samples[valueType]
+			if !ok {
+				t.Fatalf("%s için örnek değer yok", valueType)
+			}
+
+			data, err := codec.Marshal(&cache.CacheEntry{Data: sample, TTL: time.Minute})
+			if err != nil {
+				t.Fatal(err)
+			}
+			entry, err := codec.Unmarshal(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := reflect.TypeOf(entry.Data); got != valueType {
+				t.Fatalf("decode edilen tip = %v, want %v", got, valueType)
+			}
+			want, _ := json.Marshal(sample)
+			got, _ := json.Marshal(entry.Data)
+			if string(got) != string(want) {
+				t.Errorf("round trip farklı:\n got %s\nwant %s", got, want)
+			}
+		})
+	}
+}
+
+// Every getter has to find its own value again after it went through a disk
+// store, or it would silently refetch on every call.
+func TestGettersServeFromDiskStore(t *testing.T) {
+	server, seed := startServer(t)
+	dir := t.TempDir()
+	guildID := seed.Guilds[0].ID
+	channelID := seed.Channels[0].ID
+	userID := seed.Users[1].ID
+
+	getters := map[string]func(context.Context, *Client) error{
+		"GetGuilds": func(ctx context.Context, c *Client) error { _, err := c.GetGuilds(ctx); return err },
+		"GetUser":   func(ctx context.Context, c *Client) error { _, err := c.GetUser(ctx, userID); return err },
+		"GetGuild":  func(ctx context.Context, c *Client) error { _, err := c.GetGuild(ctx, guildID); return err },
+		"GetGuildMembers": func(ctx context.Context, c *Client) error {
+			_, err := c.GetGuildMembers(ctx, guildID, 10)
+			return err
+		},
+		"GetAllGuildMembers": func(ctx context.Context, c *Client) error {
+			_, err := c.GetAllGuildMembers(ctx, guildID)
+			return err
+		},
+		"GetGuildMember": func(ctx context.Context, c *Client) error {
+			_, err := c.GetGuildMember(ctx, guildID, userID)
+			return err
+		},
+		"GetGuildChannels": func(ctx context.Context, c *Client) error {
+			_, err := c.GetGuildChannels(ctx, guildID)
+			return err
+		},
+		"GetChannel": func(ctx context.Context, c *Client) error { _, err := c.GetChannel(ctx, channelID); return err },
+		"GetChannelMessages": func(ctx context.Context, c *Client) error {
+			_, err := c.GetChannelMessages(ctx, channelID, MessageQuery{})
+			return err
+		},
+		"GetGuildRoles": func(ctx context.Context, c *Client) error { _, err := c.GetGuildRoles(ctx, guildID); return err },
+		"GetGuildEmojis": func(ctx context.Context, c *Client) error {
+			_, err := c.GetGuildEmojis(ctx, guildID)
+			return err
+		},
+		"GetGuildStickers": func(ctx context.Context, c *Client) error {
+			_, err := c.GetGuildStickers(ctx, guildID)
+			return err
+		},
+		"GetGuildBans": func(ctx context.Context, c *Client) error {
+			_, err := c.GetGuildBans(ctx, guildID, BanQuery{})
+			return err
+		},
+		"GetGuildInvites": func(ctx context.Context, c *Client) error {
+			_, err := c.GetGuildInvites(ctx, guildID)
+			return err
+		},
+		"GetGuildAuditLog": func(ctx context.Context, c *Client) error {
+			_, err := c.GetGuildAuditLog(ctx, guildID, AuditLogQuery{})
+			return err
+		},
+	}
+
+	newDiskClient := func() *Client {
+		store, err := cache.NewDiskStore(dir, cache.NewJSONCodec())
+		if err != nil {
+			t.Fatal(err)
+		}
+		diskCache := cache.NewCacheWithStore(store, 100, time.Minute, time.Minute)
+		t.Cleanup(diskCache.Stop)
+
+		client, err := NewClient(newTestConfig(server), diskCache)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return client
+	}
+
+	ctx := context.Background()
+	warm := newDiskClient()
+	for name, get := range getters {
+		if err := get(ctx, warm); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+	}
+
+	cold := newDiskClient()
+	server.ResetRequests()
+	for name, get := range getters {
+		if err := get(ctx, cold); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+		if requests := server.Requests(); len(requests) > 0 {
+			t.Errorf("%s diskteki kaydı kullanmadı: %s %s", name, requests[0].Method, requests[0].Path)
+			server.ResetRequests()
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package discord
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"discord-user-api/models"
+	"discord-user-api/snowflake"
+)
+
+func (c *Client) GetGuildChannels(ctx context.Context, guildID snowflake.Snowflake) ([]models.DiscordChannel, error) {
+	cacheKey := fmt.Sprintf("guild_channels_%s", guildID)
+
+	cached, exists, fallback := c.cachedValue(ctx, cacheKey)
+	if exists {
+		if channels, ok := cached.([]models.DiscordChannel); ok {
+			log.Printf("📤 Cache'den guild kanalları getirildi: %s (%d adet)", guildID, len(channels))
+			return channels, nil
+		}
+	}
+
+	url := fmt.Sprintf("%s/%s/guilds/%s/channels", c.config.Discord.APIURL, c.config.Discord.APIVersion, guildID)
+
+	channels, err := c.coalescedRequest(ctx, cacheKey, "GET", url, decodeChannels, func(value interface{}) {
+		c.cache.SetWithAutoRefresh(cacheKey, value, c.config.Cache.TTL, true, 2*time.Minute)
+		c.registerLoader(cacheKey, url, decodeChannels)
+	})
+
+	if err != nil {
+		if stale, ok := c.serveStale(ctx, cacheKey, fallback, err).([]models.DiscordChannel); ok {
+			return stale, nil
+		}
+		return nil, fmt.Errorf("guild kanalları getirilemedi: %w", err)
+	}
+
+	channelsList := channels.([]models.DiscordChannel)
+
+	log.Printf("✅ Guild kanalları başarıyla getirildi: %s (%d adet)", guildID, len(channelsList))
+	return channelsList, nil
+}
+
+func (c *Client) GetChannel(ctx context.Context, channelID snowflake.Snowflake) (*models.DiscordChannel, error) {
+	cacheKey := fmt.Sprintf("channel_%s", channelID)
+
+	cached, exists, fallback := c.cachedValue(ctx, cacheKey)
+	if exists {
+		if channel, ok := cached.(*models.DiscordChannel); ok {
+			log.Printf("📤 Cache'den kanal getirildi: %s (%s)", channelID, channel.Name)
+			return channel, nil
+		}
+	}
+
+	url := fmt.Sprintf("%s/%s/channels/%s", c.config.Discord.APIURL, c.config.Discord.APIVersion, channelID)
+
+	channel, err := c.coalescedRequest(ctx, cacheKey, "GET", url, decodeChannel, func(value interface{}) {
+		c.cache.SetWithAutoRefresh(cacheKey, value, c.config.Cache.TTL, true, 2*time.Minute)
+		c.registerLoader(cacheKey, url, decodeChannel)
+	})
+
+	if err != nil {
+		if stale, ok := c.serveStale(ctx, cacheKey, fallback, err).(*models.DiscordChannel); ok {
+			return stale, nil
+		}
+		return nil, fmt.Errorf("kanal getirilemedi: %w", err)
+	}
+
+	channelData := channel.(*models.DiscordChannel)
+
+	log.Printf("✅ Kanal başarıyla getirildi: %s (%s)", channelID, channelData.Name)
+	return channelData, nil
+}
+
+func (c *Client) RefreshGuildChannels(ctx context.Context, guildID snowflake.Snowflake) ([]models.DiscordChannel, error) {
+	cacheKey := fmt.Sprintf("guild_channels_%s", guildID)
+
+	if c.config.Cache.Enabled {
+		c.cache.Delete(cacheKey)
+	}
+
+	channels, err := c.GetGuildChannels(ctx, guildID)
+	if err != nil {
+		return nil, fmt.Errorf("guild kanalları yenilenemedi: %w", err)
+	}
+
+	log.Printf("🔄 Guild kanalları yenilendi: %s", guildID)
+	return channels, nil
+}
+
+func (c *Client) RefreshChannel(ctx context.Context, channelID snowflake.Snowflake) (*models.DiscordChannel, error) {
+	cacheKey := fmt.Sprintf("channel_%s", channelID)
+
+	if c.config.Cache.Enabled {
+		c.cache.Delete(cacheKey)
+	}
+
+	channel, err := c.GetChannel(ctx, channelID)
+	if err != nil {
+		return nil, fmt.Errorf("kanal yenilenemedi: %w", err)
+	}
+
+	log.Printf("🔄 Kanal yenilendi: %s", channelID)
+	return channel, nil
+}
+
+func decodeChannels(body io.Reader) (interface{}, error) {
+	var channels []models.DiscordChannel
+	err := json.NewDecoder(body).Decode(&channels)
+	return channels, err
+}
+
+func decodeChannel(body io.Reader) (interface{}, error) {
+	var channel models.DiscordChannel
+	err := json.NewDecoder(body).Decode(&channel)
+	return &channel, err
+}
@@ -1,335 +1,659 @@
-package discord
-
-import (
-	"encoding/json"
-	"fmt"
-	"io"
-	"log"
-	"net/http"
-	"strconv"
-	"time"
-
-	"discord-user-api/cache"
-	"discord-user-api/config"
-	"discord-user-api/models"
-)
-
-type Client struct {
-	config     *config.Config
-	httpClient *http.Client
-	cache      *cache.Cache
-	rateLimiter *RateLimiter
-}
-
-type RateLimiter struct {
-	limit     int
-	remaining int
-	reset     int64
-	resetTime time.Time
-}
-
-func NewClient(cfg *config.Config, cache *cache.Cache) *Client {
-	client := &Client{
-		config: cfg,
-		httpClient: &http.Client{
-			Timeout: cfg.Discord.RequestTimeout,
-		},
-		cache:      cache,
-		rateLimiter: &RateLimiter{},
-	}
-
-	log.Printf("🤖 Discord Client başlatıldı")
-	return client
-}
-
-func (c *Client) GetGuilds() ([]models.DiscordGuild, error) {
-	cacheKey := "guilds"
-	
-	if c.config.Cache.Enabled {
-		if cached, exists := c.cache.Get(cacheKey); exists {
-			if guilds, ok := cached.([]models.DiscordGuild); ok {
-				log.Printf("📤 Cache'den guild'ler getirildi: %d adet", len(guilds))
-				return guilds, nil
-			}
-		}
-	}
-
-	url := fmt.Sprintf("%s/%s/users/@me/guilds", c.config.Discord.APIURL, c.config.Discord.APIVersion)
-	
-	guilds, err := c.makeRequest("GET", url, nil, func(body io.Reader) (interface{}, error) {
-		var guilds []models.DiscordGuild
-		err := json.NewDecoder(body).Decode(&guilds)
-		return guilds, err
-	})
-
-	if err != nil {
-		return nil, fmt.Errorf("guild'ler getirilemedi: %v", err)
-	}
-
-	guildsList := guilds.([]models.DiscordGuild)
-	
-	if c.config.Cache.Enabled {
-		c.cache.SetWithAutoRefresh(cacheKey, guildsList, c.config.Cache.TTL, true, 5*time.Minute)
-	}
-
-	log.Printf("✅ Guild'ler başarıyla getirildi: %d adet", len(guildsList))
-	return guildsList, nil
-}
-
-func (c *Client) GetUser(userID string) (*models.DiscordProfile, error) {
-	cacheKey := fmt.Sprintf("user_%s", userID)
-	
-	if c.config.Cache.Enabled {
-		if cached, exists := c.cache.Get(cacheKey); exists {
-			if profile, ok := cached.(*models.DiscordProfile); ok {
-				log.Printf("📤 Cache'den kullanıcı getirildi: %s (%s)", userID, profile.User.Username)
-				return profile, nil
-			}
-		}
-	}
-
-	url := fmt.Sprintf("%s/%s/users/%s/profile", c.config.Discord.APIURL, c.config.Discord.APIVersion, userID)
-	
-	profile, err := c.makeRequest("GET", url, nil, func(body io.Reader) (interface{}, error) {
-		var profile models.DiscordProfile
-		err := json.NewDecoder(body).Decode(&profile)
-		return &profile, err
-	})
-
-	if err != nil {
-		return nil, fmt.Errorf("kullanıcı profili getirilemedi: %v", err)
-	}
-
-	profileData := profile.(*models.DiscordProfile)
-	
-	if c.config.Cache.Enabled {
-		c.cache.SetWithAutoRefresh(cacheKey, profileData, c.config.Cache.TTL, true, 10*time.Minute)
-	}
-
-	log.Printf("✅ Kullanıcı profili başarıyla getirildi: %s (%s)", userID, profileData.User.Username)
-	return profileData, nil
-}
-
-func (c *Client) GetGuild(guildID string) (*models.DiscordGuild, error) {
-	cacheKey := fmt.Sprintf("guild_%s", guildID)
-	
-	if c.config.Cache.Enabled {
-		if cached, exists := c.cache.Get(cacheKey); exists {
-			if guild, ok := cached.(*models.DiscordGuild); ok {
-				log.Printf("📤 Cache'den guild getirildi: %s (%s)", guildID, guild.Name)
-				return guild, nil
-			}
-		}
-	}
-
-	url := fmt.Sprintf("%s/%s/guilds/%s", c.config.Discord.APIURL, c.config.Discord.APIVersion, guildID)
-	
-	guild, err := c.makeRequest("GET", url, nil, func(body io.Reader) (interface{}, error) {
-		var guild models.DiscordGuild
-		err := json.NewDecoder(body).Decode(&guild)
-		return &guild, err
-	})
-
-	if err != nil {
-		return nil, fmt.Errorf("guild getirilemedi: %v", err)
-	}
-
-	guildData := guild.(*models.DiscordGuild)
-	
-	if c.config.Cache.Enabled {
-		c.cache.SetWithAutoRefresh(cacheKey, guildData, c.config.Cache.TTL, true, 2*time.Minute)
-	}
-
-	log.Printf("✅ Guild başarıyla getirildi: %s (%s) - %d rol, %d emoji", 
-		guildID, guildData.Name, len(guildData.Roles), len(guildData.Emojis))
-	return guildData, nil
-}
-
-func (c *Client) GetGuildMembers(guildID string, limit int) ([]models.DiscordGuildMember, error) {
-	cacheKey := fmt.Sprintf("guild_members_%s_%d", guildID, limit)
-	
-	if c.config.Cache.Enabled {
-		if cached, exists := c.cache.Get(cacheKey); exists {
-			if members, ok := cached.([]models.DiscordGuildMember); ok {
-				log.Printf("📤 Cache'den guild üyeleri getirildi: %s (%d adet)", guildID, len(members))
-				return members, nil
-			}
-		}
-	}
-
-	if limit <= 0 {
-		limit = 1000
-	}
-
-	url := fmt.Sprintf("%s/%s/guilds/%s/members?limit=%d", c.config.Discord.APIURL, c.config.Discord.APIVersion, guildID, limit)
-	
-	members, err := c.makeRequest("GET", url, nil, func(body io.Reader) (interface{}, error) {
-		var members []models.DiscordGuildMember
-		err := json.NewDecoder(body).Decode(&members)
-		return members, err
-	})
-
-	if err != nil {
-		return nil, fmt.Errorf("guild üyeleri getirilemedi: %v", err)
-	}
-
-	membersList := members.([]models.DiscordGuildMember)
-	
-	if c.config.Cache.Enabled {
-		c.cache.SetWithAutoRefresh(cacheKey, membersList, c.config.Cache.TTL, true, 3*time.Minute)
-	}
-
-	log.Printf("✅ Guild üyeleri başarıyla getirildi: %s (%d adet)", guildID, len(membersList))
-	return membersList, nil
-}
-
-func (c *Client) RefreshGuild(guildID string) error {
-	cacheKey := fmt.Sprintf("guild_%s", guildID)
-	
-	if c.config.Cache.Enabled {
-		c.cache.Delete(cacheKey)
-	}
-	
-	_, err := c.GetGuild(guildID)
-	if err != nil {
-		return fmt.Errorf("guild yenilenemedi: %v", err)
-	}
-	
-	log.Printf("🔄 Guild yenilendi: %s", guildID)
-	return nil
-}
-
-func (c *Client) RefreshGuildMembers(guildID string, limit int) error {
-	cacheKey := fmt.Sprintf("guild_members_%s_%d", guildID, limit)
-	
-	if c.config.Cache.Enabled {
-		c.cache.Delete(cacheKey)
-	}
-	
-	_, err := c.GetGuildMembers(guildID, limit)
-	if err != nil {
-		return fmt.Errorf("guild üyeleri yenilenemedi: %v", err)
-	}
-	
-	log.Printf("🔄 Guild üyeleri yenilendi: %s", guildID)
-	return nil
-}
-
-func (c *Client) makeRequest(method, url string, body io.Reader, decoder func(io.Reader) (interface{}, error)) (interface{}, error) {
-	var lastErr error
-	
-	for attempt := 0; attempt <= c.config.Discord.MaxRetries; attempt++ {
-		if attempt > 0 {
-			log.Printf("🔄 Yeniden deneme %d/%d", attempt, c.config.Discord.MaxRetries)
-			time.Sleep(c.config.Discord.RetryDelay * time.Duration(attempt))
-		}
-
-		req, err := http.NewRequest(method, url, body)
-		if err != nil {
-			return nil, fmt.Errorf("request oluşturulamadı: %v", err)
-		}
-
-		req.Header.Set("Authorization", c.config.Discord.Token)
-		req.Header.Set("User-Agent", "Discord-API-Client/1.0")
-		req.Header.Set("Accept", "application/json")
-		req.Header.Set("Content-Type", "application/json")
-
-		if c.rateLimiter.remaining <= 0 && c.rateLimiter.resetTime.After(time.Now()) {
-			waitTime := time.Until(c.rateLimiter.resetTime)
-			log.Printf("⏰ Rate limit bekleme: %v", waitTime)
-			time.Sleep(waitTime)
-		}
-
-		resp, err := c.httpClient.Do(req)
-		if err != nil {
-			lastErr = fmt.Errorf("HTTP isteği başarısız: %v", err)
-			continue
-		}
-
-		c.parseRateLimitHeaders(resp)
-
-		switch resp.StatusCode {
-		case http.StatusOK:
-			result, err := decoder(resp.Body)
-			resp.Body.Close()
-			if err != nil {
-				return nil, fmt.Errorf("response decode hatası: %v", err)
-			}
-			return result, nil
-
-		case http.StatusTooManyRequests:
-			resetTime := c.rateLimiter.resetTime
-			if resetTime.After(time.Now()) {
-				waitTime := time.Until(resetTime)
-				log.Printf("⏰ Rate limit aşıldı, bekleme: %v", waitTime)
-				time.Sleep(waitTime)
-			}
-			resp.Body.Close()
-			continue
-
-		case http.StatusUnauthorized:
-			resp.Body.Close()
-			return nil, fmt.Errorf("yetkilendirme hatası: geçersiz token")
-
-		case http.StatusForbidden:
-			resp.Body.Close()
-			return nil, fmt.Errorf("erişim reddedildi: yetersiz izinler")
-
-		case http.StatusNotFound:
-			resp.Body.Close()
-			return nil, fmt.Errorf("kaynak bulunamadı")
-
-		default:
-			bodyBytes, _ := io.ReadAll(resp.Body)
-			resp.Body.Close()
-			lastErr = fmt.Errorf("API hatası: %d - %s", resp.StatusCode, string(bodyBytes))
-			continue
-		}
-	}
-
-	return nil, fmt.Errorf("maksimum deneme sayısı aşıldı, son hata: %v", lastErr)
-}
-
-func (c *Client) parseRateLimitHeaders(resp *http.Response) {
-	if limit := resp.Header.Get("X-RateLimit-Limit"); limit != "" {
-		if val, err := strconv.Atoi(limit); err == nil {
-			c.rateLimiter.limit = val
-		}
-	}
-
-	if remaining := resp.Header.Get("X-RateLimit-Remaining"); remaining != "" {
-		if val, err := strconv.Atoi(remaining); err == nil {
-			c.rateLimiter.remaining = val
-		}
-	}
-
-	if reset := resp.Header.Get("X-RateLimit-Reset"); reset != "" {
-		if val, err := strconv.ParseInt(reset, 10, 64); err == nil {
-			c.rateLimiter.reset = val
-			c.rateLimiter.resetTime = time.Unix(val, 0)
-		}
-	}
-}
-
-func (c *Client) GetRateLimitInfo() *models.RateLimit {
-	return &models.RateLimit{
-		Limit:     c.rateLimiter.limit,
-		Remaining: c.rateLimiter.remaining,
-		Reset:     c.rateLimiter.reset,
-		ResetTime: c.rateLimiter.resetTime.Format(time.RFC3339),
-	}
-}
-
-func (c *Client) ClearCache() {
-	if c.cache != nil {
-		c.cache.Clear()
-		log.Printf("🧹 Discord client cache temizlendi")
-	}
-}
-
-func (c *Client) GetCacheStats() *cache.CacheStats {
-	if c.cache != nil {
-		return c.cache.GetStats()
-	}
-	return &cache.CacheStats{}
-} 
\ No newline at end of file
+package discord
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+
+	"discord-user-api/cache"
+	"discord-user-api/config"
+	"discord-user-api/models"
+	"discord-user-api/snowflake"
+	"discord-user-api/websocket"
+)
+
+type Client struct {
+	config     *config.Config
+	httpClient *http.Client
+	transport  *http.Transport
+	cache      *cache.Cache
+	tokens     *TokenPool
+	inflight   *requestGroup
+	retry      *RetryPolicy
+	breaker    *CircuitBreaker
+	recorder   *Recorder
+}
+
+func NewClient(cfg *config.Config, cache *cache.Cache) (*Client, error) {
+	transport, err := NewTransport(cfg.Discord)
+	if err != nil {
+		return nil, fmt.Errorf("HTTP transport oluşturulamadı: %w", err)
+	}
+
+	recorder, err := NewRecorder(cfg.Discord, transport)
+	if err != nil {
+		return nil, err
+	}
+
+	// Replayed fixtures never reach Discord, so a real token is optional.
+	tokens := cfg.Discord.Tokens
+	if recorder.Mode() == RecordingReplay && len(tokens) == 0 {
+		tokens = []string{"replay"}
+	}
+
+	client := &Client{
+		config: cfg,
+		httpClient: &http.Client{
+			Timeout:   cfg.Discord.RequestTimeout,
+			Transport: recorder,
+		},
+		transport: transport,
+		cache:     cache,
+		tokens:    NewTokenPool(tokens),
+		inflight:  newRequestGroup(),
+		retry:     NewRetryPolicy(cfg.Discord),
+		breaker:   NewCircuitBreaker(cfg.Discord.CircuitBreaker),
+		recorder:  recorder,
+	}
+
+	if cfg.Discord.ProxyURL != "" {
+		if proxyURL, err := url.Parse(cfg.Discord.ProxyURL); err == nil {
+			log.Printf("🌐 Discord istekleri proxy üzerinden gönderilecek: %s", proxyURL.Redacted())
+		}
+	}
+
+	log.Printf("🤖 Discord Client başlatıldı")
+	return client, nil
+}
+
+// GetGuilds merges the guild lists of every healthy token. Each token's list
+// is cached and refreshed on its own, since round-robin would otherwise
+// cache whichever account happened to serve the request.
+func (c *Client) GetGuilds(ctx context.Context) ([]models.DiscordGuild, error) {
+	names := c.tokens.Names()
+	if len(names) == 0 {
+		return nil, fmt.Errorf("guild'ler getirilemedi: %w", ErrNoHealthyToken)
+	}
+
+	var merged []models.DiscordGuild
+	var firstErr error
+	seen := make(map[snowflake.Snowflake]bool)
+	succeeded := 0
+
+	for _, name := range names {
+		guilds, err := c.getAccountGuilds(ctx, name)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			log.Printf("⚠️ %s guild listesi getirilemedi: %v", name, err)
+			continue
+		}
+
+		succeeded++
+		for _, guild := range guilds {
+			if !seen[guild.ID] {
+				seen[guild.ID] = true
+				merged = append(merged, guild)
+			}
+		}
+	}
+
+	if succeeded == 0 {
+		return nil, firstErr
+	}
+	return merged, nil
+}
+
+func (c *Client) getAccountGuilds(ctx context.Context, tokenName string) ([]models.DiscordGuild, error) {
+	cacheKey := AccountCacheKey("guilds", tokenName)
+	ctx = withToken(ctx, tokenName)
+
+	cached, exists, fallback := c.cachedValue(ctx, cacheKey)
+	if exists {
+		if guilds, ok := cached.([]models.DiscordGuild); ok {
+			log.Printf("📤 Cache'den guild'ler getirildi: %d adet (%s)", len(guilds), tokenName)
+			return guilds, nil
+		}
+	}
+
+	url := fmt.Sprintf("%s/%s/users/@me/guilds", c.config.Discord.APIURL, c.config.Discord.APIVersion)
+
+	guilds, err := c.coalescedRequest(ctx, cacheKey, "GET", url, decodeGuilds, func(value interface{}) {
+		c.cache.SetWithAutoRefresh(cacheKey, value, c.config.Cache.TTL, true, 5*time.Minute)
+		c.registerAccountLoader(cacheKey, tokenName, url, decodeGuilds)
+	})
+
+	if err != nil {
+		if stale, ok := c.serveStale(ctx, cacheKey, fallback, err).([]models.DiscordGuild); ok {
+			return stale, nil
+		}
+		return nil, fmt.Errorf("guild'ler getirilemedi: %w", err)
+	}
+
+	guildsList := guilds.([]models.DiscordGuild)
+
+	log.Printf("✅ Guild'ler başarıyla getirildi: %d adet (%s)", len(guildsList), tokenName)
+	return guildsList, nil
+}
+
+// GetUser always asks as the first healthy token: the profile (and @me)
+// depends on the account, so the answer has to be cached per token.
+func (c *Client) GetUser(ctx context.Context, userID snowflake.Snowflake) (*models.DiscordProfile, error) {
+	names := c.tokens.Names()
+	if len(names) == 0 {
+		return nil, fmt.Errorf("kullanıcı profili getirilemedi: %w", ErrNoHealthyToken)
+	}
+	tokenName := names[0]
+	cacheKey := AccountCacheKey(fmt.Sprintf("user_%s", userID), tokenName)
+	ctx = withToken(ctx, tokenName)
+
+	cached, exists, fallback := c.cachedValue(ctx, cacheKey)
+	if exists {
+		if profile, ok := cached.(*models.DiscordProfile); ok {
+			log.Printf("📤 Cache'den kullanıcı getirildi: %s (%s)", userID, profile.User.Username)
+			return profile, nil
+		}
+	}
+
+	url := fmt.Sprintf("%s/%s/users/%s/profile", c.config.Discord.APIURL, c.config.Discord.APIVersion, userID)
+
+	profile, err := c.coalescedRequest(ctx, cacheKey, "GET", url, decodeProfile, func(value interface{}) {
+		c.cache.SetWithAutoRefresh(cacheKey, value, c.config.Cache.TTL, true, 10*time.Minute)
+		c.registerAccountLoader(cacheKey, tokenName, url, decodeProfile)
+	})
+
+	if err != nil {
+		if stale, ok := c.serveStale(ctx, cacheKey, fallback, err).(*models.DiscordProfile); ok {
+			return stale, nil
+		}
+		return nil, fmt.Errorf("kullanıcı profili getirilemedi: %w", err)
+	}
+
+	profileData := profile.(*models.DiscordProfile)
+
+	log.Printf("✅ Kullanıcı profili başarıyla getirildi: %s (%s)", userID, profileData.User.Username)
+	return profileData, nil
+}
+
+func (c *Client) GetGuild(ctx context.Context, guildID snowflake.Snowflake) (*models.DiscordGuild, error) {
+	cacheKey := fmt.Sprintf("guild_%s", guildID)
+
+	cached, exists, fallback := c.cachedValue(ctx, cacheKey)
+	if exists {
+		if guild, ok := cached.(*models.DiscordGuild); ok {
+			log.Printf("📤 Cache'den guild getirildi: %s (%s)", guildID, guild.Name)
+			return guild, nil
+		}
+	}
+
+	url := fmt.Sprintf("%s/%s/guilds/%s", c.config.Discord.APIURL, c.config.Discord.APIVersion, guildID)
+
+	guild, err := c.coalescedRequest(ctx, cacheKey, "GET", url, decodeGuild, func(value interface{}) {
+		c.cache.SetWithAutoRefresh(cacheKey, value, c.config.Cache.TTL, true, 2*time.Minute)
+		c.registerLoader(cacheKey, url, decodeGuild)
+	})
+
+	if err != nil {
+		if stale, ok := c.serveStale(ctx, cacheKey, fallback, err).(*models.DiscordGuild); ok {
+			return stale, nil
+		}
+		return nil, fmt.Errorf("guild getirilemedi: %w", err)
+	}
+
+	guildData := guild.(*models.DiscordGuild)
+
+	log.Printf("✅ Guild başarıyla getirildi: %s (%s) - %d rol, %d emoji",
+		guildID, guildData.Name, len(guildData.Roles), len(guildData.Emojis))
+	return guildData, nil
+}
+
+func (c *Client) GetGuildMembers(ctx context.Context, guildID snowflake.Snowflake, limit int) ([]models.DiscordGuildMember, error) {
+	return c.GetGuildMembersAfter(ctx, guildID, 0, limit)
+}
+
+func (c *Client) GetGuildMembersAfter(ctx context.Context, guildID, after snowflake.Snowflake, limit int) ([]models.DiscordGuildMember, error) {
+	if limit <= 0 || limit > maxMembersPageSize {
+		limit = maxMembersPageSize
+	}
+
+	cacheKey := fmt.Sprintf("guild_members_%s_%d", guildID, limit)
+	if after.IsValid() {
+		cacheKey = fmt.Sprintf("guild_members_%s_%d_after_%s", guildID, limit, after)
+	}
+
+	cached, exists, fallback := c.cachedValue(ctx, cacheKey)
+	if exists {
+		if members, ok := cached.([]models.DiscordGuildMember); ok {
+			log.Printf("📤 Cache'den guild üyeleri getirildi: %s (%d adet)", guildID, len(members))
+			return members, nil
+		}
+	}
+
+	url := c.guildMembersURL(guildID, after, limit)
+
+	members, err := c.coalescedRequest(ctx, cacheKey, "GET", url, decodeGuildMembers, func(value interface{}) {
+		c.cache.SetWithAutoRefresh(cacheKey, value, c.config.Cache.TTL, true, 3*time.Minute)
+		c.registerLoader(cacheKey, url, decodeGuildMembers)
+	})
+
+	if err != nil {
+		if stale, ok := c.serveStale(ctx, cacheKey, fallback, err).([]models.DiscordGuildMember); ok {
+			return stale, nil
+		}
+		return nil, fmt.Errorf("guild üyeleri getirilemedi: %w", err)
+	}
+
+	membersList := members.([]models.DiscordGuildMember)
+
+	log.Printf("✅ Guild üyeleri başarıyla getirildi: %s (%d adet)", guildID, len(membersList))
+	return membersList, nil
+}
+
+func (c *Client) RefreshGuild(ctx context.Context, guildID snowflake.Snowflake) error {
+	cacheKey := fmt.Sprintf("guild_%s", guildID)
+
+	if c.config.Cache.Enabled {
+		c.cache.Delete(cacheKey)
+	}
+
+	_, err := c.GetGuild(ctx, guildID)
+	if err != nil {
+		return fmt.Errorf("guild yenilenemedi: %w", err)
+	}
+
+	log.Printf("🔄 Guild yenilendi: %s", guildID)
+	return nil
+}
+
+func (c *Client) RefreshGuildMembers(ctx context.Context, guildID snowflake.Snowflake, limit int) error {
+	if limit <= 0 || limit > maxMembersPageSize {
+		limit = maxMembersPageSize
+	}
+	cacheKey := fmt.Sprintf("guild_members_%s_%d", guildID, limit)
+
+	if c.config.Cache.Enabled {
+		c.cache.Delete(cacheKey)
+	}
+
+	_, err := c.GetGuildMembers(ctx, guildID, limit)
+	if err != nil {
+		return fmt.Errorf("guild üyeleri yenilenemedi: %w", err)
+	}
+
+	log.Printf("🔄 Guild üyeleri yenilendi: %s", guildID)
+	return nil
+}
+
+func (c *Client) registerLoader(cacheKey, url string, decoder func(io.Reader) (interface{}, error)) {
+	if !c.config.Cache.AutoRefresh {
+		return
+	}
+
+	c.cache.RegisterLoader(cacheKey, func(ctx context.Context) (interface{}, error) {
+		return c.coalescedRequest(ctx, cacheKey, "GET", url, decoder, nil)
+	})
+}
+
+func (c *Client) registerAccountLoader(cacheKey, tokenName, url string, decoder func(io.Reader) (interface{}, error)) {
+	if !c.config.Cache.AutoRefresh {
+		return
+	}
+
+	c.cache.RegisterLoader(cacheKey, func(ctx context.Context) (interface{}, error) {
+		return c.coalescedRequest(withToken(ctx, tokenName), cacheKey, "GET", url, decoder, nil)
+	})
+}
+
+func decodeGuilds(body io.Reader) (interface{}, error) {
+	var guilds []models.DiscordGuild
+	err := json.NewDecoder(body).Decode(&guilds)
+	return guilds, err
+}
+
+func decodeProfile(body io.Reader) (interface{}, error) {
+	var profile models.DiscordProfile
+	err := json.NewDecoder(body).Decode(&profile)
+	return &profile, err
+}
+
+func decodeGuild(body io.Reader) (interface{}, error) {
+	var guild models.DiscordGuild
+	err := json.NewDecoder(body).Decode(&guild)
+	return &guild, err
+}
+
+// coalescedRequest shares one upstream request between concurrent callers of
+// the same key. store caches the result from inside that request, so it runs
+// once per fetch even if the caller that started it has already given up.
+// Loaders pass nil; the cache saves their result itself.
+func (c *Client) coalescedRequest(ctx context.Context, key, method, url string, decoder func(io.Reader) (interface{}, error), store func(interface{})) (interface{}, error) {
+	result, shared, err := c.inflight.Do(ctx, key, func(callCtx context.Context) (interface{}, error) {
+		result, err := c.makeRequest(callCtx, method, url, nil, decoder)
+		if err == nil {
+			c.storeResult(result, store)
+		}
+		return result, err
+	})
+	if shared && err == nil {
+		log.Printf("🔗 Eş zamanlı istek birleştirildi: %s", key)
+	}
+	return result, err
+}
+
+func (c *Client) storeResult(result interface{}, store func(interface{})) {
+	if store != nil && c.config.Cache.Enabled {
+		store(result)
+	}
+}
+
+func (c *Client) makeRequest(ctx context.Context, method, url string, body []byte, decoder func(io.Reader) (interface{}, error)) (interface{}, error) {
+	var lastErr error
+	var retryReason string
+	var retryAfter time.Duration
+	route := routeKey(method, url)
+	group := routeGroup(route)
+	guildID := guildIDFromRoute(route)
+	tried := make(map[*pooledToken]bool)
+	idempotent := c.retry.Idempotent(method)
+
+	c.retry.recordRequest()
+
+	for attempt := 0; attempt <= c.retry.MaxRetries; attempt++ {
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("istek iptal edildi: %w", err)
+		}
+
+		if attempt > 0 {
+			if retryReason != retryReasonTokenFailover {
+				if !c.retry.allowRetry() {
+					log.Printf("🛑 Yeniden deneme bütçesi tükendi: %s", route)
+					return nil, fmt.Errorf("%w, son hata: %w", ErrRetryBudgetExhausted, lastErr)
+				}
+
+				delay := c.retry.Backoff(attempt)
+				if retryAfter > delay {
+					delay = retryAfter
+				}
+				log.Printf("🔄 Yeniden deneme %d/%d (%s, %s), bekleme: %v", attempt, c.retry.MaxRetries, route, retryReason, delay)
+				if err := sleepContext(ctx, delay); err != nil {
+					return nil, fmt.Errorf("istek iptal edildi: %w", err)
+				}
+			}
+			c.retry.recordRetry(retryReason)
+			retryAfter = 0
+		}
+
+		token, err := c.acquireToken(ctx, guildID, tried)
+		if err != nil {
+			if lastErr != nil {
+				return nil, fmt.Errorf("%w, son hata: %w", err, lastErr)
+			}
+			return nil, err
+		}
+
+		// Every attempt needs its own reader; a reader drained by a failed
+		// attempt would send an empty body on the retry.
+		var bodyReader io.Reader
+		if body != nil {
+			bodyReader = bytes.NewReader(body)
+		}
+		req, err := http.NewRequestWithContext(ctx, method, url, bodyReader)
+		if err != nil {
+			c.tokens.Release(token, nil)
+			return nil, fmt.Errorf("request oluşturulamadı: %w", err)
+		}
+
+		req.Header.Set("Authorization", token.value)
+		req.Header.Set("User-Agent", c.userAgent(method, route, token, attempt))
+		req.Header.Set("Accept", "application/json")
+		req.Header.Set("Content-Type", "application/json")
+
+		if err := token.rateLimiter.Wait(ctx, route); err != nil {
+			c.tokens.Release(token, nil)
+			return nil, fmt.Errorf("istek iptal edildi: %w", err)
+		}
+
+		// The breaker is asked last so a half-open probe slot is only held
+		// while the request is actually in flight, not through backoff or
+		// rate limit waits. A rejected request gives its bucket slot back.
+		permit, err := c.breaker.Allow(group)
+		if err != nil {
+			token.rateLimiter.Release(route)
+			c.tokens.Release(token, nil)
+			if lastErr != nil {
+				return nil, fmt.Errorf("%w, son hata: %w", err, lastErr)
+			}
+			return nil, err
+		}
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			if ctx.Err() != nil {
+				permit.Done(outcomeIgnored, nil)
+				c.tokens.Release(token, nil)
+				return nil, fmt.Errorf("istek iptal edildi: %w", ctx.Err())
+			}
+			if errors.Is(err, ErrFixtureNotFound) {
+				permit.Done(outcomeIgnored, nil)
+				c.tokens.Release(token, nil)
+				return nil, fmt.Errorf("%w: %s", ErrFixtureNotFound, route)
+			}
+			lastErr = &UpstreamError{Err: err}
+			permit.Done(outcomeFailure, lastErr)
+			c.tokens.Release(token, lastErr)
+			if !idempotent {
+				c.retry.stats.notRetryable.Add(1)
+				return nil, lastErr
+			}
+			retryReason = retryReasonNetwork
+			continue
+		}
+
+		token.rateLimiter.Update(route, resp.Header)
+		permit.Done(outcomeForStatus(resp.StatusCode), fmt.Errorf("HTTP %s (%s)", resp.Status, route))
+
+		switch resp.StatusCode {
+		case http.StatusOK:
+			result, err := decoder(resp.Body)
+			resp.Body.Close()
+			if err != nil {
+				decodeErr := &DecodeError{Route: route, Err: err}
+				c.tokens.Release(token, decodeErr)
+				return nil, decodeErr
+			}
+			c.tokens.Release(token, nil)
+			c.recordGuildVisibility(token, guildID, result)
+			return result, nil
+
+		case http.StatusTooManyRequests:
+			bodyBytes, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			waitTime, global := token.rateLimiter.OnTooManyRequests(route, resp.Header, bodyBytes)
+			log.Printf("⏰ Rate limit aşıldı (%s, %s), bekleme: %v", token.name, route, waitTime)
+			lastErr = &RateLimitedError{
+				APIError:   newAPIError(route, resp.StatusCode, bodyBytes),
+				RetryAfter: waitTime,
+				Global:     global,
+			}
+			c.tokens.Release(token, lastErr)
+			tried[token] = true
+			if c.canFailover(ctx, guildID, tried) {
+				retryReason = retryReasonTokenFailover
+				continue
+			}
+			delete(tried, token)
+			retryReason = retryReasonRateLimited
+			retryAfter = waitTime
+			continue
+
+		case http.StatusUnauthorized:
+			bodyBytes, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			lastErr = &UnauthorizedError{APIError: newAPIError(route, resp.StatusCode, bodyBytes)}
+			c.tokens.Release(token, lastErr)
+			c.tokens.Disable(token, "401 Unauthorized")
+			if c.canFailover(ctx, guildID, tried) {
+				retryReason = retryReasonTokenFailover
+				continue
+			}
+			return nil, lastErr
+
+		case http.StatusForbidden:
+			bodyBytes, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			forbiddenErr := &ForbiddenError{APIError: newAPIError(route, resp.StatusCode, bodyBytes)}
+			lastErr = forbiddenErr
+			c.tokens.Release(token, lastErr)
+			if guildID != "" {
+				// Only a missing-access answer says the token is not in the
+				// guild; a permission 403 on bans or audit logs does not.
+				if forbiddenErr.Code == discordMissingAccess || forbiddenErr.Code == discordUnknownGuild {
+					c.tokens.MarkGuilds(token, []string{guildID}, false)
+				}
+				tried[token] = true
+				if c.canFailover(ctx, guildID, tried) {
+					log.Printf("🔑 %s guild'e erişemiyor, başka token deneniyor: %s", token.name, guildID)
+					retryReason = retryReasonTokenFailover
+					continue
+				}
+			}
+			return nil, lastErr
+
+		case http.StatusNotFound:
+			bodyBytes, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			notFoundErr := &NotFoundError{APIError: newAPIError(route, resp.StatusCode, bodyBytes)}
+			c.tokens.Release(token, nil)
+			return nil, notFoundErr
+
+		default:
+			bodyBytes, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			apiErr := newAPIError(route, resp.StatusCode, bodyBytes)
+			if resp.StatusCode < http.StatusInternalServerError {
+				c.tokens.Release(token, apiErr)
+				return nil, apiErr
+			}
+			lastErr = &UpstreamError{APIError: apiErr}
+			c.tokens.Release(token, lastErr)
+			if !idempotent || !c.retry.RetryableStatus(resp.StatusCode) {
+				c.retry.stats.notRetryable.Add(1)
+				return nil, lastErr
+			}
+			retryReason = retryReasonServerError
+			continue
+		}
+	}
+
+	c.retry.stats.gaveUp.Add(1)
+	return nil, fmt.Errorf("maksimum deneme sayısı aşıldı, son hata: %w", lastErr)
+}
+
+type tokenContextKey struct{}
+
+// withToken pins every request made with ctx to one token, with no failover
+// to the rest of the pool.
+func withToken(ctx context.Context, tokenName string) context.Context {
+	return context.WithValue(ctx, tokenContextKey{}, tokenName)
+}
+
+func pinnedToken(ctx context.Context) string {
+	name, _ := ctx.Value(tokenContextKey{}).(string)
+	return name
+}
+
+func (c *Client) acquireToken(ctx context.Context, guildID string, tried map[*pooledToken]bool) (*pooledToken, error) {
+	if name := pinnedToken(ctx); name != "" {
+		return c.tokens.AcquireNamed(name)
+	}
+	return c.tokens.Acquire(guildID, tried)
+}
+
+func (c *Client) canFailover(ctx context.Context, guildID string, tried map[*pooledToken]bool) bool {
+	return pinnedToken(ctx) == "" && c.tokens.HasCandidate(guildID, tried)
+}
+
+func (c *Client) recordGuildVisibility(token *pooledToken, guildID string, result interface{}) {
+	if guilds, ok := result.([]models.DiscordGuild); ok {
+		guildIDs := make([]string, 0, len(guilds))
+		for _, guild := range guilds {
+			guildIDs = append(guildIDs, guild.ID.String())
+		}
+		c.tokens.MarkGuilds(token, guildIDs, true)
+		return
+	}
+
+	if guildID != "" {
+		c.tokens.MarkGuilds(token, []string{guildID}, true)
+	}
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (c *Client) GetRateLimitInfo() *models.RateLimit {
+	return c.tokens.RateLimitInfo()
+}
+
+func (c *Client) GetTokenStats() []TokenStats {
+	return c.tokens.Stats()
+}
+
+func (c *Client) SetWebSocketManager(wsManager *websocket.WebSocketManager) {
+	c.breaker.SetWebSocketManager(wsManager)
+}
+
+func (c *Client) GetCircuitStates() map[string]string {
+	return c.breaker.States()
+}
+
+func (c *Client) GetRecorderStats() RecorderStats {
+	return c.recorder.Stats()
+}
+
+func (c *Client) GetCircuitStats() []CircuitStats {
+	return c.breaker.Stats()
+}
+
+func (c *Client) GetRetryStats() RetryStats {
+	return c.retry.Stats()
+}
+
+func (c *Client) GetCoalesceStats() CoalesceStats {
+	return c.inflight.Stats()
+}
+
+func (c *Client) ClearCache() {
+	if c.cache != nil {
+		c.cache.Clear()
+		log.Printf("🧹 Discord client cache temizlendi")
+	}
+}
+
+func (c *Client) GetCacheStats() *cache.CacheStats {
+	if c.cache != nil {
+		return c.cache.GetStats()
+	}
+	return &cache.CacheStats{}
+}
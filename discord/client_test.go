@@ -0,0 +1,436 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"discord-user-api/cache"
+	"discord-user-api/config"
+	"discord-user-api/discordtest"
+	"discord-user-api/models"
+	"discord-user-api/snowflake"
+)
+
+func TestMain(m *testing.M) {
+	log.SetOutput(io.Discard)
+	os.Exit(m.Run())
+}
+
+func newTestConfig(server *discordtest.Server) *config.Config {
+	cfg := &config.Config{}
+	cfg.Cache.Enabled = true
+	cfg.Cache.TTL = time.Minute
+	cfg.Discord.RequestTimeout = time.Second
+	cfg.Discord.MaxRetries = 2
+	cfg.Discord.RetryBudgetRatio = 0.2
+	cfg.Discord.RetryBudgetMinPerSecond = 1
+	cfg.Discord.CircuitBreaker.FailureThreshold = 5
+	server.ConfigureClient(cfg)
+	return cfg
+}
+
+func newTestClient(t *testing.T, server *discordtest.Server, cfg *config.Config) *Client {
+	t.Helper()
+
+	store := cache.NewCache(100, time.Minute, time.Minute)
+	t.Cleanup(store.Stop)
+
+	client, err := NewClient(cfg, store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func startServer(t *testing.T) (*discordtest.Server, discordtest.Seed) {
+	t.Helper()
+	seed := discordtest.DefaultSeed()
+	server := discordtest.NewServer(seed)
+	t.Cleanup(server.Close)
+	return server, seed
+}
+
+func TestRateLimitedRetriesAfterRetryAfter(t *testing.T) {
+	server, seed := startServer(t)
+	client := newTestClient(t, server, newTestConfig(server))
+	guildID := seed.Guilds[0].ID
+
+	server.InjectFault(discordtest.Fault{
+		Path:       "/guilds/" + guildID.String(),
+		Status:     http.StatusTooManyRequests,
+		RetryAfter: 50 * time.Millisecond,
+		Count:      1,
+	})
+
+	start := time.Now()
+	guild, err := client.GetGuild(context.Background(), guildID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if guild.Name != seed.Guilds[0].Name {
+		t.Errorf("guild = %q", guild.Name)
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("retry_after beklenmedi: %v", elapsed)
+	}
+
+	requests := server.Requests()
+	if len(requests) != 2 || requests[0].Status != http.StatusTooManyRequests || requests[1].Status != http.StatusOK {
+		t.Errorf("requests = %+v", requests)
+	}
+	if stats := client.GetRetryStats(); stats.ByReason[retryReasonRateLimited] != 1 {
+		t.Errorf("retry stats = %+v", stats)
+	}
+}
+
+func TestServerErrorRetries(t *testing.T) {
+	tests := []struct {
+		name         string
+		count        int
+		wantErr      bool
+		wantRequests int
+	}{
+		{"recovers after one 502", 1, false, 2},
+		{"gives up after max retries", 0, true, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, seed := startServer(t)
+			client := newTestClient(t, server, newTestConfig(server))
+			guildID := seed.Guilds[0].ID
+
+			server.InjectFault(discordtest.Fault{
+				Path:   "/guilds/" + guildID.String(),
+				Status: http.StatusBadGateway,
+				Count:  tt.count,
+			})
+
+			_, err := client.GetGuild(context.Background(), guildID)
+			if tt.wantErr {
+				var upstreamErr *UpstreamError
+				if !errors.As(err, &upstreamErr) || upstreamErr.APIError == nil || upstreamErr.APIError.StatusCode != http.StatusBadGateway {
+					t.Fatalf("err = %v, want UpstreamError 502", err)
+				}
+			} else if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := server.RequestCount(); got != tt.wantRequests {
+				t.Errorf("requests = %d, want %d", got, tt.wantRequests)
+			}
+			if stats := client.GetRetryStats(); stats.ByReason[retryReasonServerError] != int64(tt.wantRequests-1) {
+				t.Errorf("retry stats = %+v", stats)
+			}
+		})
+	}
+}
+
+const secondToken = "discordtest-token-2"
+
+// newPoolClient configures two tokens; the pool hands out token_1 first.
+func newPoolClient(t *testing.T, server *discordtest.Server) *Client {
+	t.Helper()
+	server.AddToken(secondToken)
+	cfg := newTestConfig(server)
+	cfg.Cache.Enabled = false
+	cfg.Discord.Tokens = []string{discordtest.DefaultToken, secondToken}
+	return newTestClient(t, server, cfg)
+}
+
+func TestUnauthorizedTokenIsDisabledAndFailsOver(t *testing.T) {
+	server, seed := startServer(t)
+	client := newPoolClient(t, server)
+	server.RevokeToken(discordtest.DefaultToken)
+	guildID := seed.Guilds[0].ID
+
+	if _, err := client.GetGuild(context.Background(), guildID); err != nil {
+		t.Fatal(err)
+	}
+
+	requests := server.Requests()
+	if len(requests) != 2 || requests[0].Status != http.StatusUnauthorized || requests[1].Status != http.StatusOK {
+		t.Errorf("requests = %+v", requests)
+	}
+	stats := client.GetTokenStats()
+	if stats[0].Healthy || stats[0].DisabledReason == "" {
+		t.Errorf("token_1 rotasyondan çıkarılmadı: %+v", stats[0])
+	}
+	if !stats[1].Healthy {
+		t.Errorf("token_2 = %+v", stats[1])
+	}
+
+	// The disabled token is not tried again.
+	server.ResetRequests()
+	if _, err := client.GetGuild(context.Background(), guildID); err != nil {
+		t.Fatal(err)
+	}
+	if got := server.RequestCount(); got != 1 {
+		t.Errorf("requests = %d, want 1", got)
+	}
+}
+
+func TestRateLimitedTokenFailsOverWithoutWaiting(t *testing.T) {
+	server, seed := startServer(t)
+	client := newPoolClient(t, server)
+	guildID := seed.Guilds[0].ID
+
+	server.InjectFault(discordtest.Fault{
+		Path:       "/guilds/" + guildID.String(),
+		Status:     http.StatusTooManyRequests,
+		RetryAfter: 5 * time.Second,
+		Count:      1,
+	})
+
+	start := time.Now()
+	if _, err := client.GetGuild(context.Background(), guildID); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("failover yerine retry_after beklendi: %v", elapsed)
+	}
+
+	requests := server.Requests()
+	if len(requests) != 2 || requests[0].Status != http.StatusTooManyRequests || requests[1].Status != http.StatusOK {
+		t.Errorf("requests = %+v", requests)
+	}
+	stats := client.GetRetryStats()
+	if stats.ByReason[retryReasonTokenFailover] != 1 || stats.ByReason[retryReasonRateLimited] != 0 {
+		t.Errorf("retry stats = %+v", stats)
+	}
+	if tokens := client.GetTokenStats(); !tokens[0].Healthy {
+		t.Errorf("429 token'ı devre dışı bırakmamalı: %+v", tokens[0])
+	}
+}
+
+func TestForbiddenMarksGuildInvisible(t *testing.T) {
+	tests := []struct {
+		name          string
+		code          int
+		wantInvisible bool
+	}{
+		{"missing access", discordMissingAccess, true},
+		{"unknown guild", discordUnknownGuild, true},
+		{"missing permissions", 50013, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, seed := startServer(t)
+			client := newPoolClient(t, server)
+			guildID := seed.Guilds[0].ID
+
+			server.InjectFault(discordtest.Fault{
+				Path:   "/guilds/" + guildID.String(),
+				Status: http.StatusForbidden,
+				Code:   tt.code,
+				Count:  1,
+			})
+
+			if _, err := client.GetGuild(context.Background(), guildID); err != nil {
+				t.Fatal(err)
+			}
+			if got := server.RequestCount(); got != 2 {
+				t.Errorf("requests = %d, want 2", got)
+			}
+
+			first := client.tokens.tokens[0]
+			client.tokens.mutex.Lock()
+			visible, known := first.guilds[guildID.String()]
+			client.tokens.mutex.Unlock()
+			if invisible := known && !visible; invisible != tt.wantInvisible {
+				t.Errorf("guild görünmez = %v, want %v", invisible, tt.wantInvisible)
+			}
+		})
+	}
+}
+
+func TestBreakerRejectionKeepsRateLimitSlot(t *testing.T) {
+	server, seed := startServer(t)
+	server.SetRateLimit(discordtest.RateLimit{Limit: 2, Window: time.Minute})
+
+	cfg := newTestConfig(server)
+	cfg.Cache.Enabled = false
+	cfg.Discord.MaxRetries = 0
+	cfg.Discord.CircuitBreaker = config.CircuitBreakerConfig{Enabled: true, FailureThreshold: 1, OpenTimeout: 50 * time.Millisecond}
+	client := newTestClient(t, server, cfg)
+	guildID := seed.Guilds[0].ID
+
+	// Learns the bucket with one request left for this guild.
+	if _, err := client.GetGuild(context.Background(), guildID); err != nil {
+		t.Fatal(err)
+	}
+
+	// A failure on the other guild opens the circuit for the whole group.
+	server.InjectFault(discordtest.Fault{Path: "/guilds/" + seed.Guilds[1].ID.String(), Status: http.StatusBadGateway, Count: 1})
+	client.GetGuild(context.Background(), seed.Guilds[1].ID)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	for i := 0; i < 3; i++ {
+		var circuitErr *CircuitOpenError
+		if _, err := client.GetGuild(ctx, guildID); !errors.As(err, &circuitErr) {
+			t.Fatalf("istek %d: err = %v, want CircuitOpenError", i, err)
+		}
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if _, err := client.GetGuild(ctx, guildID); err != nil {
+		t.Fatalf("reddedilen istekler rate limit slotunu harcadı: %v", err)
+	}
+	if got := server.RequestCount(); got != 3 {
+		t.Errorf("requests = %d, want 3", got)
+	}
+}
+
+func TestConcurrentGettersShareOneRequest(t *testing.T) {
+	const callers = 8
+
+	server, seed := startServer(t)
+	client := newTestClient(t, server, newTestConfig(server))
+	guildID := seed.Guilds[0].ID
+	server.InjectFault(discordtest.Fault{Path: "/guilds/" + guildID.String(), Latency: 200 * time.Millisecond})
+
+	// The caller that starts the request gives up once the others joined;
+	// the shared result must still reach them and the cache.
+	leaderCtx, cancelLeader := context.WithCancel(context.Background())
+	leaderDone := make(chan error, 1)
+	go func() {
+		_, err := client.GetGuild(leaderCtx, guildID)
+		leaderDone <- err
+	}()
+	waitFor(t, func() bool { return client.inflight.Stats().InFlight == 1 })
+
+	results := make(chan error, callers)
+	for i := 0; i < callers; i++ {
+		go func() {
+			guild, err := client.GetGuild(context.Background(), guildID)
+			if err == nil && guild.ID != guildID {
+				err = fmt.Errorf("guild = %s", guild.ID)
+			}
+			results <- err
+		}()
+	}
+	waitFor(t, func() bool { return client.inflight.Stats().Coalesced == callers })
+	cancelLeader()
+
+	if err := <-leaderDone; !errors.Is(err, context.Canceled) {
+		t.Errorf("leader err = %v, want context.Canceled", err)
+	}
+	for i := 0; i < callers; i++ {
+		if err := <-results; err != nil {
+			t.Error(err)
+		}
+	}
+
+	if _, err := client.GetGuild(context.Background(), guildID); err != nil {
+		t.Fatal(err)
+	}
+	if got := server.RequestCount(); got != 1 {
+		t.Errorf("requests = %d, want 1", got)
+	}
+	if _, ok := client.cache.Get(fmt.Sprintf("guild_%s", guildID)); !ok {
+		t.Error("paylaşılan sonuç cache'e yazılmadı")
+	}
+}
+
+func waitFor(t *testing.T, condition func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal("koşul zamanında sağlanmadı")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestMalformedJSONReturnsDecodeError(t *testing.T) {
+	server, seed := startServer(t)
+	client := newTestClient(t, server, newTestConfig(server))
+	guildID := seed.Guilds[0].ID
+
+	server.InjectFault(discordtest.Fault{Path: "/guilds/" + guildID.String(), Malformed: true})
+
+	_, err := client.GetGuild(context.Background(), guildID)
+	var decodeErr *DecodeError
+	if !errors.As(err, &decodeErr) {
+		t.Fatalf("err = %v, want DecodeError", err)
+	}
+	if decodeErr.Route != "GET /guilds/"+guildID.String() {
+		t.Errorf("route = %q", decodeErr.Route)
+	}
+	if got := server.RequestCount(); got != 1 {
+		t.Errorf("decode hatası yeniden denenmemeliydi, requests = %d", got)
+	}
+}
+
+func TestUnknownGuildReturnsNotFoundError(t *testing.T) {
+	server, _ := startServer(t)
+	client := newTestClient(t, server, newTestConfig(server))
+
+	_, err := client.GetGuild(context.Background(), snowflake.Snowflake(1))
+	var notFoundErr *NotFoundError
+	if !errors.As(err, &notFoundErr) {
+		t.Fatalf("err = %v, want NotFoundError", err)
+	}
+	if notFoundErr.Code != discordUnknownGuild {
+		t.Errorf("code = %d, want %d", notFoundErr.Code, discordUnknownGuild)
+	}
+	if got := server.RequestCount(); got != 1 {
+		t.Errorf("404 yeniden denenmemeliydi, requests = %d", got)
+	}
+}
+
+func TestGuildMemberPagination(t *testing.T) {
+	server, seed := startServer(t)
+	client := newTestClient(t, server, newTestConfig(server))
+	guildID := seed.Guilds[0].ID
+	ctx := context.Background()
+
+	it := client.NewGuildMemberIterator(guildID, 0, 2)
+	var members []models.DiscordGuildMember
+	for !it.Done() {
+		page, err := it.Next(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		members = append(members, page...)
+	}
+
+	want := seed.Members[guildID]
+	if len(members) != len(want) || it.Fetched() != len(want) {
+		t.Fatalf("members = %d, fetched = %d, want %d", len(members), it.Fetched(), len(want))
+	}
+	for i := 1; i < len(members); i++ {
+		if members[i].User.ID <= members[i-1].User.ID {
+			t.Errorf("üyeler sıralı değil: %s <= %s", members[i].User.ID, members[i-1].User.ID)
+		}
+	}
+	if it.Cursor() != members[len(members)-1].User.ID {
+		t.Errorf("cursor = %s", it.Cursor())
+	}
+
+	requests := server.Requests()
+	if len(requests) != 2 {
+		t.Fatalf("requests = %+v, want 2 pages", requests)
+	}
+	if requests[0].Query != "limit=2" || requests[1].Query != "limit=2&after="+members[1].User.ID.String() {
+		t.Errorf("queries = %q, %q", requests[0].Query, requests[1].Query)
+	}
+
+	all, err := client.GetAllGuildMembers(ctx, guildID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != len(want) {
+		t.Errorf("GetAllGuildMembers = %d üye, want %d", len(all), len(want))
+	}
+}
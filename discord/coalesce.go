@@ -0,0 +1,112 @@
+package discord
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+type requestGroup struct {
+	mutex sync.Mutex
+	calls map[string]*inflightCall
+	stats coalesceCounters
+}
+
+type inflightCall struct {
+	done    chan struct{}
+	result  interface{}
+	err     error
+	waiters int
+	cancel  context.CancelFunc
+}
+
+type coalesceCounters struct {
+	requests   atomic.Int64
+	executions atomic.Int64
+	coalesced  atomic.Int64
+	abandoned  atomic.Int64
+}
+
+type CoalesceStats struct {
+	Requests   int64 `json:"requests"`
+	Executions int64 `json:"executions"`
+	Coalesced  int64 `json:"coalesced"`
+	Abandoned  int64 `json:"abandoned"`
+	InFlight   int   `json:"in_flight"`
+}
+
+func newRequestGroup() *requestGroup {
+	return &requestGroup{
+		calls: make(map[string]*inflightCall),
+	}
+}
+
+func (g *requestGroup) Do(ctx context.Context, key string, fn func(context.Context) (interface{}, error)) (interface{}, bool, error) {
+	g.stats.requests.Add(1)
+
+	g.mutex.Lock()
+	call, shared := g.calls[key]
+	if shared {
+		call.waiters++
+		g.stats.coalesced.Add(1)
+	} else {
+		callCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		call = &inflightCall{
+			done:    make(chan struct{}),
+			waiters: 1,
+			cancel:  cancel,
+		}
+		g.calls[key] = call
+		g.stats.executions.Add(1)
+
+		go g.execute(callCtx, key, call, fn)
+	}
+	g.mutex.Unlock()
+
+	select {
+	case <-call.done:
+		return call.result, shared, call.err
+	case <-ctx.Done():
+		g.leave(call)
+		return nil, shared, ctx.Err()
+	}
+}
+
+func (g *requestGroup) execute(ctx context.Context, key string, call *inflightCall, fn func(context.Context) (interface{}, error)) {
+	defer call.cancel()
+
+	call.result, call.err = fn(ctx)
+
+	g.mutex.Lock()
+	if g.calls[key] == call {
+		delete(g.calls, key)
+	}
+	g.mutex.Unlock()
+
+	close(call.done)
+}
+
+func (g *requestGroup) leave(call *inflightCall) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	call.waiters--
+	if call.waiters == 0 {
+		g.stats.abandoned.Add(1)
+		call.cancel()
+	}
+}
+
+func (g *requestGroup) Stats() CoalesceStats {
+	g.mutex.Lock()
+	inFlight := len(g.calls)
+	g.mutex.Unlock()
+
+	return CoalesceStats{
+		Requests:   g.stats.requests.Load(),
+		Executions: g.stats.executions.Load(),
+		Coalesced:  g.stats.coalesced.Load(),
+		Abandoned:  g.stats.abandoned.Load(),
+		InFlight:   inFlight,
+	}
+}
@@ -0,0 +1,197 @@
+package discord
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const (
+	ErrorCodeAPI          = "discord_api_error"
+	ErrorCodeUnauthorized = "discord_unauthorized"
+	ErrorCodeForbidden    = "discord_forbidden"
+	ErrorCodeNotFound     = "discord_not_found"
+	ErrorCodeRateLimited  = "discord_rate_limited"
+	ErrorCodeUpstream     = "discord_upstream_error"
+	ErrorCodeDecode       = "discord_decode_error"
+	ErrorCodeCircuitOpen  = "discord_circuit_open"
+)
+
+// JSON error codes Discord sends with a 403 when the token cannot see the
+// guild at all, as opposed to 50013 (missing permissions) which only means
+// this action is not allowed.
+const (
+	discordUnknownGuild  = 10004
+	discordMissingAccess = 50001
+)
+
+type APIError struct {
+	StatusCode int             `json:"status_code"`
+	Route      string          `json:"route"`
+	Code       int             `json:"code"`
+	Message    string          `json:"message"`
+	Errors     json.RawMessage `json:"errors,omitempty"`
+}
+
+type UnauthorizedError struct {
+	*APIError
+}
+
+type ForbiddenError struct {
+	*APIError
+}
+
+type NotFoundError struct {
+	*APIError
+}
+
+type RateLimitedError struct {
+	*APIError
+	RetryAfter time.Duration
+	Global     bool
+}
+
+type UpstreamError struct {
+	*APIError
+	Err error
+}
+
+type CircuitOpenError struct {
+	Group      string
+	RetryAfter time.Duration
+	HalfOpen   bool
+}
+
+type DecodeError struct {
+	Route string
+	Err   error
+}
+
+func newAPIError(route string, statusCode int, body []byte) *APIError {
+	apiErr := &APIError{
+		StatusCode: statusCode,
+		Route:      route,
+	}
+
+	var payload struct {
+		Code    int             `json:"code"`
+		Message string          `json:"message"`
+		Errors  json.RawMessage `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &payload); err == nil {
+		apiErr.Code = payload.Code
+		apiErr.Message = payload.Message
+		apiErr.Errors = payload.Errors
+	}
+
+	if apiErr.Message == "" {
+		apiErr.Message = http.StatusText(statusCode)
+	}
+
+	return apiErr
+}
+
+func (e *APIError) Error() string {
+	if e.Code != 0 {
+		return fmt.Sprintf("Discord API hatası %d (%s): %s [kod %d]", e.StatusCode, e.Route, e.Message, e.Code)
+	}
+	return fmt.Sprintf("Discord API hatası %d (%s): %s", e.StatusCode, e.Route, e.Message)
+}
+
+func (e *APIError) ErrorCode() string {
+	return ErrorCodeAPI
+}
+
+func (e *UnauthorizedError) Error() string {
+	return fmt.Sprintf("yetkilendirme hatası: geçersiz token - %s", e.APIError.Error())
+}
+
+func (e *UnauthorizedError) Unwrap() error {
+	return e.APIError
+}
+
+func (e *UnauthorizedError) ErrorCode() string {
+	return ErrorCodeUnauthorized
+}
+
+func (e *ForbiddenError) Error() string {
+	return fmt.Sprintf("erişim reddedildi: yetersiz izinler - %s", e.APIError.Error())
+}
+
+func (e *ForbiddenError) Unwrap() error {
+	return e.APIError
+}
+
+func (e *ForbiddenError) ErrorCode() string {
+	return ErrorCodeForbidden
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("kaynak bulunamadı - %s", e.APIError.Error())
+}
+
+func (e *NotFoundError) Unwrap() error {
+	return e.APIError
+}
+
+func (e *NotFoundError) ErrorCode() string {
+	return ErrorCodeNotFound
+}
+
+func (e *RateLimitedError) Error() string {
+	return fmt.Sprintf("rate limit aşıldı (global: %t, bekleme: %v) - %s", e.Global, e.RetryAfter, e.APIError.Error())
+}
+
+func (e *RateLimitedError) Unwrap() error {
+	return e.APIError
+}
+
+func (e *RateLimitedError) ErrorCode() string {
+	return ErrorCodeRateLimited
+}
+
+func (e *UpstreamError) Error() string {
+	if e.APIError == nil {
+		return fmt.Sprintf("HTTP isteği başarısız: %v", e.Err)
+	}
+	return fmt.Sprintf("sunucu hatası - %s", e.APIError.Error())
+}
+
+func (e *UpstreamError) Unwrap() []error {
+	var errs []error
+	if e.APIError != nil {
+		errs = append(errs, e.APIError)
+	}
+	if e.Err != nil {
+		errs = append(errs, e.Err)
+	}
+	return errs
+}
+
+func (e *UpstreamError) ErrorCode() string {
+	return ErrorCodeUpstream
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("response decode hatası (%s): %v", e.Route, e.Err)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+func (e *DecodeError) ErrorCode() string {
+	return ErrorCodeDecode
+}
+
+func (e *CircuitOpenError) Error() string {
+	if e.HalfOpen {
+		return fmt.Sprintf("circuit yarı açık, deneme istekleri sürüyor (%s)", e.Group)
+	}
+	return fmt.Sprintf("circuit açık, istek gönderilmedi (%s, kalan: %v)", e.Group, e.RetryAfter.Round(time.Millisecond))
+}
+
+func (e *CircuitOpenError) ErrorCode() string {
+	return ErrorCodeCircuitOpen
+}
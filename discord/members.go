@@ -0,0 +1,197 @@
+package discord
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"discord-user-api/models"
+	"discord-user-api/snowflake"
+)
+
+const maxMembersPageSize = 1000
+
+type GuildMemberIterator struct {
+	client   *Client
+	guildID  snowflake.Snowflake
+	after    snowflake.Snowflake
+	pageSize int
+	fetched  int
+	done     bool
+}
+
+func (c *Client) NewGuildMemberIterator(guildID, after snowflake.Snowflake, pageSize int) *GuildMemberIterator {
+	if pageSize <= 0 || pageSize > maxMembersPageSize {
+		pageSize = maxMembersPageSize
+	}
+
+	return &GuildMemberIterator{
+		client:   c,
+		guildID:  guildID,
+		after:    after,
+		pageSize: pageSize,
+	}
+}
+
+func (it *GuildMemberIterator) Next(ctx context.Context) ([]models.DiscordGuildMember, error) {
+	if it.done {
+		return nil, nil
+	}
+
+	page, err := it.client.fetchGuildMembersPage(ctx, it.guildID, it.after, it.pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	it.fetched += len(page)
+	if len(page) < it.pageSize {
+		it.done = true
+	}
+	if len(page) > 0 {
+		it.after = page[len(page)-1].User.ID
+	}
+
+	return page, nil
+}
+
+func (it *GuildMemberIterator) Done() bool {
+	return it.done
+}
+
+func (it *GuildMemberIterator) Cursor() snowflake.Snowflake {
+	return it.after
+}
+
+func (it *GuildMemberIterator) Fetched() int {
+	return it.fetched
+}
+
+func (c *Client) ForEachGuildMemberPage(ctx context.Context, guildID snowflake.Snowflake, fn func([]models.DiscordGuildMember) error) error {
+	it := c.NewGuildMemberIterator(guildID, 0, maxMembersPageSize)
+
+	for !it.Done() {
+		page, err := it.Next(ctx)
+		if err != nil {
+			return err
+		}
+		if len(page) == 0 {
+			continue
+		}
+		if err := fn(page); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *Client) GetAllGuildMembers(ctx context.Context, guildID snowflake.Snowflake) ([]models.DiscordGuildMember, error) {
+	cacheKey := fmt.Sprintf("guild_members_all_%s", guildID)
+
+	cached, exists, fallback := c.cachedValue(ctx, cacheKey)
+	if exists {
+		if members, ok := cached.([]models.DiscordGuildMember); ok {
+			log.Printf("📤 Cache'den tüm guild üyeleri getirildi: %s (%d adet)", guildID, len(members))
+			return members, nil
+		}
+	}
+
+	result, err := c.fetchAllGuildMembers(ctx, cacheKey, guildID, func(value interface{}) {
+		c.cache.SetWithAutoRefresh(cacheKey, value, c.config.Cache.TTL, true, 10*time.Minute)
+		if c.config.Cache.AutoRefresh {
+			c.cache.RegisterLoader(cacheKey, func(ctx context.Context) (interface{}, error) {
+				return c.fetchAllGuildMembers(ctx, cacheKey, guildID, nil)
+			})
+		}
+	})
+	if err != nil {
+		if stale, ok := c.serveStale(ctx, cacheKey, fallback, err).([]models.DiscordGuildMember); ok {
+			return stale, nil
+		}
+		return nil, fmt.Errorf("tüm guild üyeleri getirilemedi: %w", err)
+	}
+
+	members := result.([]models.DiscordGuildMember)
+
+	log.Printf("✅ Tüm guild üyeleri getirildi: %s (%d adet)", guildID, len(members))
+	return members, nil
+}
+
+// fetchAllGuildMembers walks every member page once per key, for callers and
+// the cache loader alike. store works as in coalescedRequest.
+func (c *Client) fetchAllGuildMembers(ctx context.Context, cacheKey string, guildID snowflake.Snowflake, store func(interface{})) (interface{}, error) {
+	result, _, err := c.inflight.Do(ctx, cacheKey, func(callCtx context.Context) (interface{}, error) {
+		var members []models.DiscordGuildMember
+		err := c.ForEachGuildMemberPage(callCtx, guildID, func(page []models.DiscordGuildMember) error {
+			members = append(members, page...)
+			log.Printf("📄 Guild üye sayfası alındı: %s (%d adet, toplam %d)", guildID, len(page), len(members))
+			return nil
+		})
+		if err == nil {
+			c.storeResult(members, store)
+		}
+		return members, err
+	})
+	return result, err
+}
+
+func (c *Client) fetchGuildMembersPage(ctx context.Context, guildID, after snowflake.Snowflake, limit int) ([]models.DiscordGuildMember, error) {
+	members, err := c.makeRequest(ctx, "GET", c.guildMembersURL(guildID, after, limit), nil, decodeGuildMembers)
+	if err != nil {
+		return nil, fmt.Errorf("guild üye sayfası getirilemedi: %w", err)
+	}
+	return members.([]models.DiscordGuildMember), nil
+}
+
+func (c *Client) guildMembersURL(guildID, after snowflake.Snowflake, limit int) string {
+	url := fmt.Sprintf("%s/%s/guilds/%s/members?limit=%d", c.config.Discord.APIURL, c.config.Discord.APIVersion, guildID, limit)
+	if after.IsValid() {
+		url += "&after=" + after.String()
+	}
+	return url
+}
+
+func decodeGuildMembers(body io.Reader) (interface{}, error) {
+	var members []models.DiscordGuildMember
+	err := json.NewDecoder(body).Decode(&members)
+	return members, err
+}
+
+func decodeGuildMember(body io.Reader) (interface{}, error) {
+	var member models.DiscordGuildMember
+	err := json.NewDecoder(body).Decode(&member)
+	return &member, err
+}
+
+func (c *Client) GetGuildMember(ctx context.Context, guildID, userID snowflake.Snowflake) (*models.DiscordGuildMember, error) {
+	cacheKey := fmt.Sprintf("guild_member_%s_%s", guildID, userID)
+
+	cached, exists, fallback := c.cachedValue(ctx, cacheKey)
+	if exists {
+		if member, ok := cached.(*models.DiscordGuildMember); ok {
+			log.Printf("📤 Cache'den guild üyesi getirildi: %s/%s", guildID, userID)
+			return member, nil
+		}
+	}
+
+	url := fmt.Sprintf("%s/%s/guilds/%s/members/%s", c.config.Discord.APIURL, c.config.Discord.APIVersion, guildID, userID)
+
+	member, err := c.coalescedRequest(ctx, cacheKey, "GET", url, decodeGuildMember, func(value interface{}) {
+		c.cache.SetWithAutoRefresh(cacheKey, value, c.config.Cache.TTL, true, 3*time.Minute)
+		c.registerLoader(cacheKey, url, decodeGuildMember)
+	})
+	if err != nil {
+		if stale, ok := c.serveStale(ctx, cacheKey, fallback, err).(*models.DiscordGuildMember); ok {
+			return stale, nil
+		}
+		return nil, fmt.Errorf("guild üyesi getirilemedi: %w", err)
+	}
+
+	memberData := member.(*models.DiscordGuildMember)
+
+	log.Printf("✅ Guild üyesi başarıyla getirildi: %s/%s (%s)", guildID, userID, memberData.User.Username)
+	return memberData, nil
+}
@@ -0,0 +1,173 @@
+package discord
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/url"
+	"strconv"
+	"time"
+
+	"discord-user-api/models"
+	"discord-user-api/snowflake"
+)
+
+const maxMessagesPageSize = 100
+
+var ErrConflictingCursors = errors.New("before, after ve around parametrelerinden yalnızca biri kullanılabilir")
+
+type MessageQuery struct {
+	Before snowflake.Snowflake
+	After  snowflake.Snowflake
+	Around snowflake.Snowflake
+	Limit  int
+}
+
+type MessageExportOptions struct {
+	Before      snowflake.Snowflake
+	After       snowflake.Snowflake
+	MaxMessages int
+}
+
+type MessageExportProgress struct {
+	Pages    int
+	Messages int
+	Cursor   snowflake.Snowflake
+}
+
+func (q MessageQuery) validate() error {
+	cursors := 0
+	for _, cursor := range []snowflake.Snowflake{q.Before, q.After, q.Around} {
+		if cursor.IsValid() {
+			cursors++
+		}
+	}
+	if cursors > 1 {
+		return ErrConflictingCursors
+	}
+	return nil
+}
+
+func (q MessageQuery) values() url.Values {
+	values := url.Values{}
+	values.Set("limit", strconv.Itoa(q.Limit))
+	switch {
+	case q.Before.IsValid():
+		values.Set("before", q.Before.String())
+	case q.After.IsValid():
+		values.Set("after", q.After.String())
+	case q.Around.IsValid():
+		values.Set("around", q.Around.String())
+	}
+	return values
+}
+
+func (c *Client) GetChannelMessages(ctx context.Context, channelID snowflake.Snowflake, query MessageQuery) ([]models.DiscordMessage, error) {
+	if err := query.validate(); err != nil {
+		return nil, err
+	}
+	if query.Limit <= 0 || query.Limit > maxMessagesPageSize {
+		query.Limit = 50
+	}
+
+	cacheKey := fmt.Sprintf("channel_messages_%s_%s", channelID, query.values().Encode())
+
+	cached, exists, fallback := c.cachedValue(ctx, cacheKey)
+	if exists {
+		if messages, ok := cached.([]models.DiscordMessage); ok {
+			log.Printf("📤 Cache'den kanal mesajları getirildi: %s (%d adet)", channelID, len(messages))
+			return messages, nil
+		}
+	}
+
+	messages, err := c.coalescedRequest(ctx, cacheKey, "GET", c.channelMessagesURL(channelID, query), decodeMessages, func(value interface{}) {
+		c.cache.SetWithTTL(cacheKey, value, 30*time.Second)
+	})
+	if err != nil {
+		if stale, ok := c.serveStale(ctx, cacheKey, fallback, err).([]models.DiscordMessage); ok {
+			return stale, nil
+		}
+		return nil, fmt.Errorf("kanal mesajları getirilemedi: %w", err)
+	}
+
+	messagesList := messages.([]models.DiscordMessage)
+
+	log.Printf("✅ Kanal mesajları başarıyla getirildi: %s (%d adet)", channelID, len(messagesList))
+	return messagesList, nil
+}
+
+func (c *Client) ExportChannelMessages(ctx context.Context, channelID snowflake.Snowflake, opts MessageExportOptions, fn func([]models.DiscordMessage, MessageExportProgress) error) (MessageExportProgress, error) {
+	var progress MessageExportProgress
+
+	query := MessageQuery{Before: opts.Before, After: opts.After, Limit: maxMessagesPageSize}
+	if err := query.validate(); err != nil {
+		return progress, err
+	}
+
+	forward := query.After.IsValid()
+	log.Printf("📦 Kanal geçmişi dışa aktarılıyor: %s", channelID)
+
+	for {
+		if opts.MaxMessages > 0 {
+			remaining := opts.MaxMessages - progress.Messages
+			if remaining <= 0 {
+				break
+			}
+			if remaining < query.Limit {
+				query.Limit = remaining
+			}
+		}
+
+		result, err := c.makeRequest(ctx, "GET", c.channelMessagesURL(channelID, query), nil, decodeMessages)
+		if err != nil {
+			return progress, fmt.Errorf("kanal geçmişi dışa aktarılamadı (%d mesaj sonrası): %w", progress.Messages, err)
+		}
+
+		page := result.([]models.DiscordMessage)
+		if len(page) == 0 {
+			break
+		}
+
+		if forward {
+			reverseMessages(page)
+			query.After = page[len(page)-1].ID
+			progress.Cursor = query.After
+		} else {
+			query.Before = page[len(page)-1].ID
+			progress.Cursor = query.Before
+		}
+
+		progress.Pages++
+		progress.Messages += len(page)
+
+		if err := fn(page, progress); err != nil {
+			return progress, err
+		}
+
+		if len(page) < query.Limit {
+			break
+		}
+	}
+
+	log.Printf("✅ Kanal geçmişi dışa aktarıldı: %s (%d sayfa, %d mesaj)", channelID, progress.Pages, progress.Messages)
+	return progress, nil
+}
+
+func (c *Client) channelMessagesURL(channelID snowflake.Snowflake, query MessageQuery) string {
+	return fmt.Sprintf("%s/%s/channels/%s/messages?%s", c.config.Discord.APIURL, c.config.Discord.APIVersion, channelID, query.values().Encode())
+}
+
+func decodeMessages(body io.Reader) (interface{}, error) {
+	var messages []models.DiscordMessage
+	err := json.NewDecoder(body).Decode(&messages)
+	return messages, err
+}
+
+func reverseMessages(messages []models.DiscordMessage) {
+	for i, j := 0, len(messages)-1; i < j; i, j = i+1, j-1 {
+		messages[i], messages[j] = messages[j], messages[i]
+	}
+}
@@ -0,0 +1,334 @@
+package discord
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"discord-user-api/models"
+)
+
+const rateLimitPruneInterval = time.Minute
+
+type RateLimiter struct {
+	mutex       sync.Mutex
+	routes      map[string]string
+	buckets     map[string]*rateLimitBucket
+	globalReset time.Time
+	lastBucket  string
+	lastPrune   time.Time
+}
+
+type rateLimitBucket struct {
+	key       string
+	limit     int
+	remaining int
+	reset     int64
+	resetTime time.Time
+	updatedAt time.Time
+}
+
+type tooManyRequestsBody struct {
+	Message    string  `json:"message"`
+	RetryAfter float64 `json:"retry_after"`
+	Global     bool    `json:"global"`
+}
+
+func NewRateLimiter() *RateLimiter {
+	return &RateLimiter{
+		routes:  make(map[string]string),
+		buckets: make(map[string]*rateLimitBucket),
+	}
+}
+
+func (rl *RateLimiter) Wait(ctx context.Context, route string) error {
+	for {
+		wait := rl.reserve(route)
+		if wait <= 0 {
+			return nil
+		}
+
+		if err := sleepContext(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+func (rl *RateLimiter) reserve(route string) time.Duration {
+	rl.mutex.Lock()
+	defer rl.mutex.Unlock()
+
+	now := time.Now()
+	if rl.globalReset.After(now) {
+		return rl.globalReset.Sub(now)
+	}
+
+	bucket := rl.bucketFor(route)
+	if bucket == nil || bucket.limit == 0 {
+		return 0
+	}
+
+	if !bucket.resetTime.After(now) {
+		bucket.remaining = bucket.limit
+	}
+
+	if bucket.remaining <= 0 {
+		return bucket.resetTime.Sub(now)
+	}
+
+	bucket.remaining--
+	return 0
+}
+
+// Release gives back the slot Wait took for a request that was never sent.
+func (rl *RateLimiter) Release(route string) {
+	rl.mutex.Lock()
+	defer rl.mutex.Unlock()
+
+	bucket := rl.bucketFor(route)
+	if bucket == nil || bucket.limit == 0 || !bucket.resetTime.After(time.Now()) {
+		return
+	}
+	if bucket.remaining < bucket.limit {
+		bucket.remaining++
+	}
+}
+
+func (rl *RateLimiter) Update(route string, header http.Header) {
+	bucketHash := header.Get("X-RateLimit-Bucket")
+	limit := header.Get("X-RateLimit-Limit")
+	if bucketHash == "" && limit == "" {
+		return
+	}
+
+	rl.mutex.Lock()
+	defer rl.mutex.Unlock()
+
+	// Discord scopes a bucket to its hash plus the route's major parameter,
+	// so /guilds/1/members and /guilds/2/members have separate quotas.
+	key := route
+	if bucketHash != "" {
+		key = bucketHash
+		if major := majorParameter(route); major != "" {
+			key = bucketHash + ":" + major
+		}
+		rl.routes[route] = key
+	}
+
+	bucket, exists := rl.buckets[key]
+	if !exists {
+		bucket = &rateLimitBucket{key: key}
+		rl.buckets[key] = bucket
+	}
+
+	now := time.Now()
+	if val, err := strconv.Atoi(limit); err == nil {
+		bucket.limit = val
+	}
+
+	if val, err := strconv.Atoi(header.Get("X-RateLimit-Remaining")); err == nil {
+		bucket.remaining = val
+	}
+
+	if val, err := strconv.ParseFloat(header.Get("X-RateLimit-Reset-After"), 64); err == nil {
+		bucket.resetTime = now.Add(time.Duration(val * float64(time.Second)))
+		bucket.reset = bucket.resetTime.Unix()
+	} else if val, err := strconv.ParseFloat(header.Get("X-RateLimit-Reset"), 64); err == nil {
+		bucket.resetTime = time.Unix(0, int64(val*float64(time.Second)))
+		bucket.reset = int64(val)
+	}
+
+	bucket.updatedAt = now
+	rl.lastBucket = key
+	rl.prune(now)
+}
+
+func (rl *RateLimiter) OnTooManyRequests(route string, header http.Header, body []byte) (time.Duration, bool) {
+	var payload tooManyRequestsBody
+	json.Unmarshal(body, &payload)
+
+	retryAfter := time.Duration(payload.RetryAfter * float64(time.Second))
+	if retryAfter <= 0 {
+		if val, err := strconv.ParseFloat(header.Get("Retry-After"), 64); err == nil {
+			retryAfter = time.Duration(val * float64(time.Second))
+		}
+	}
+	if retryAfter <= 0 {
+		if val, err := strconv.ParseFloat(header.Get("X-RateLimit-Reset-After"), 64); err == nil {
+			retryAfter = time.Duration(val * float64(time.Second))
+		}
+	}
+
+	global := payload.Global || header.Get("X-RateLimit-Global") == "true" || header.Get("X-RateLimit-Scope") == "global"
+
+	rl.mutex.Lock()
+	defer rl.mutex.Unlock()
+
+	resetTime := time.Now().Add(retryAfter)
+	if global {
+		if resetTime.After(rl.globalReset) {
+			rl.globalReset = resetTime
+		}
+		return retryAfter, true
+	}
+
+	bucket := rl.bucketFor(route)
+	if bucket == nil {
+		bucket = &rateLimitBucket{key: route, limit: 1}
+		rl.buckets[route] = bucket
+	}
+	bucket.remaining = 0
+	bucket.resetTime = resetTime
+	bucket.reset = resetTime.Unix()
+	bucket.updatedAt = time.Now()
+	rl.lastBucket = bucket.key
+
+	return retryAfter, false
+}
+
+// prune drops buckets whose window has already reset, along with the routes
+// pointing at them, so one-off major parameters do not pile up. A pruned
+// bucket is learned again from the next response.
+func (rl *RateLimiter) prune(now time.Time) {
+	if now.Sub(rl.lastPrune) < rateLimitPruneInterval {
+		return
+	}
+	rl.lastPrune = now
+
+	for key, bucket := range rl.buckets {
+		if key != rl.lastBucket && bucket.resetTime.Before(now) {
+			delete(rl.buckets, key)
+		}
+	}
+	for route, key := range rl.routes {
+		if _, exists := rl.buckets[key]; !exists {
+			delete(rl.routes, route)
+		}
+	}
+}
+
+func (rl *RateLimiter) bucketFor(route string) *rateLimitBucket {
+	if hash, exists := rl.routes[route]; exists {
+		return rl.buckets[hash]
+	}
+	return rl.buckets[route]
+}
+
+func (rl *RateLimiter) Info() *models.RateLimit {
+	rl.mutex.Lock()
+	defer rl.mutex.Unlock()
+
+	info := &models.RateLimit{}
+	if rl.globalReset.After(time.Now()) {
+		info.Global = true
+		info.GlobalResetTime = rl.globalReset.Format(time.RFC3339)
+	}
+
+	routesByBucket := make(map[string][]string)
+	for route, hash := range rl.routes {
+		routesByBucket[hash] = append(routesByBucket[hash], route)
+	}
+
+	for key, bucket := range rl.buckets {
+		routes := routesByBucket[key]
+		if len(routes) == 0 {
+			routes = []string{key}
+		}
+		sort.Strings(routes)
+
+		state := models.RateLimitBucket{
+			Bucket:    key,
+			Routes:    routes,
+			Limit:     bucket.limit,
+			Remaining: bucket.remaining,
+			Reset:     bucket.reset,
+			ResetTime: bucket.resetTime.Format(time.RFC3339),
+		}
+		info.Buckets = append(info.Buckets, state)
+
+		if key == rl.lastBucket {
+			info.Bucket = key
+			info.Limit = bucket.limit
+			info.Remaining = bucket.remaining
+			info.Reset = bucket.reset
+			info.ResetTime = state.ResetTime
+		}
+	}
+
+	sort.Slice(info.Buckets, func(i, j int) bool {
+		return info.Buckets[i].Bucket < info.Buckets[j].Bucket
+	})
+
+	return info
+}
+
+func routeKey(method, rawURL string) string {
+	path := rawURL
+	if parsed, err := url.Parse(rawURL); err == nil {
+		path = parsed.Path
+	}
+
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	start := 0
+	for i, segment := range segments {
+		if len(segment) > 1 && segment[0] == 'v' && isNumeric(segment[1:]) {
+			start = i + 1
+			break
+		}
+	}
+	segments = segments[start:]
+
+	for i, segment := range segments {
+		if !isNumeric(segment) {
+			continue
+		}
+		if i > 0 && isMajorParameter(segments[i-1]) {
+			continue
+		}
+		segments[i] = ":id"
+	}
+
+	return method + " /" + strings.Join(segments, "/")
+}
+
+// majorParameter returns the guild, channel or webhook ID in a route key
+// built by routeKey, e.g. "guilds/123" for "GET /guilds/123/members".
+func majorParameter(route string) string {
+	if _, path, found := strings.Cut(route, " "); found {
+		route = path
+	}
+
+	segments := strings.Split(strings.Trim(route, "/"), "/")
+	for i := 1; i < len(segments); i++ {
+		if isMajorParameter(segments[i-1]) && isNumeric(segments[i]) {
+			return segments[i-1] + "/" + segments[i]
+		}
+	}
+	return ""
+}
+
+func isMajorParameter(resource string) bool {
+	switch resource {
+	case "guilds", "channels", "webhooks":
+		return true
+	}
+	return false
+}
+
+func isNumeric(value string) bool {
+	if value == "" {
+		return false
+	}
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
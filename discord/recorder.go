@@ -0,0 +1,332 @@
+package discord
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"discord-user-api/config"
+)
+
+const (
+	RecordingOff    = "off"
+	RecordingRecord = "record"
+	RecordingReplay = "replay"
+)
+
+const redactedHeader = "[REDACTED]"
+
+// sensitiveHeaders never reach a fixture, in either direction.
+var sensitiveHeaders = []string{"Authorization", "Cookie", "Set-Cookie"}
+
+var ErrFixtureNotFound = errors.New("kayıtlı fixture bulunamadı")
+
+var unsafeFixtureChars = regexp.MustCompile(`[^A-Za-z0-9]+`)
+
+// Recorder sits between the HTTP client and the transport. In record mode
+// every attempt made by makeRequest (429s and 5xx included) is written to
+// the fixture directory; in replay mode the same files are served back in
+// the order they were recorded.
+type Recorder struct {
+	mode    string
+	dir     string
+	baseURL *url.URL
+	next    http.RoundTripper
+
+	mutex     sync.Mutex
+	sequences map[string]int
+	stats     recorderCounters
+}
+
+type recorderCounters struct {
+	recorded atomic.Int64
+	replayed atomic.Int64
+	reused   atomic.Int64
+	missing  atomic.Int64
+	errors   atomic.Int64
+}
+
+type RecorderStats struct {
+	Mode       string `json:"mode"`
+	FixtureDir string `json:"fixture_dir"`
+	Recorded   int64  `json:"recorded"`
+	Replayed   int64  `json:"replayed"`
+	Reused     int64  `json:"reused"`
+	Missing    int64  `json:"missing"`
+	Errors     int64  `json:"errors"`
+}
+
+type Fixture struct {
+	Request    FixtureRequest  `json:"request"`
+	Response   FixtureResponse `json:"response"`
+	RecordedAt string          `json:"recorded_at"`
+}
+
+type FixtureRequest struct {
+	Method  string      `json:"method"`
+	URL     string      `json:"url"`
+	Headers http.Header `json:"headers"`
+	Body    string      `json:"body,omitempty"`
+}
+
+// FixtureResponse keeps JSON bodies inline so fixtures stay readable and
+// diffable; anything else (truncated or HTML error pages) goes to RawBody.
+type FixtureResponse struct {
+	Status  int             `json:"status"`
+	Headers http.Header     `json:"headers"`
+	Body    json.RawMessage `json:"body,omitempty"`
+	RawBody string          `json:"raw_body,omitempty"`
+}
+
+func NewRecorder(cfg config.DiscordConfig, next http.RoundTripper) (*Recorder, error) {
+	mode := strings.ToLower(strings.TrimSpace(cfg.Recording.Mode))
+	if mode == "" {
+		mode = RecordingOff
+	}
+
+	switch mode {
+	case RecordingOff, RecordingRecord, RecordingReplay:
+	default:
+		return nil, fmt.Errorf("geçersiz kayıt modu: %q (off, record veya replay olmalı)", cfg.Recording.Mode)
+	}
+
+	baseURL, err := url.Parse(cfg.APIURL)
+	if err != nil {
+		return nil, fmt.Errorf("geçersiz Discord API URL'i: %w", err)
+	}
+
+	recorder := &Recorder{
+		mode:      mode,
+		dir:       cfg.Recording.FixtureDir,
+		baseURL:   baseURL,
+		next:      next,
+		sequences: make(map[string]int),
+	}
+
+	switch mode {
+	case RecordingRecord:
+		if err := os.MkdirAll(recorder.dir, 0o755); err != nil {
+			return nil, fmt.Errorf("fixture dizini oluşturulamadı: %w", err)
+		}
+		log.Printf("⏺️ Discord trafiği kaydediliyor: %s", recorder.dir)
+	case RecordingReplay:
+		if _, err := os.Stat(recorder.dir); err != nil {
+			return nil, fmt.Errorf("fixture dizini okunamadı: %w", err)
+		}
+		log.Printf("▶️ Discord trafiği fixture'lardan oynatılıyor: %s", recorder.dir)
+	}
+
+	return recorder, nil
+}
+
+func (r *Recorder) Mode() string {
+	return r.mode
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	switch r.mode {
+	case RecordingRecord:
+		return r.record(req)
+	case RecordingReplay:
+		return r.replay(req)
+	}
+	return r.next.RoundTrip(req)
+}
+
+func (r *Recorder) record(req *http.Request) (*http.Response, error) {
+	var requestBody []byte
+	if req.Body != nil {
+		var err error
+		requestBody, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(requestBody))
+	}
+
+	resp, err := r.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	responseBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(responseBody))
+
+	key := r.requestKey(req)
+	fixture := Fixture{
+		Request: FixtureRequest{
+			Method:  req.Method,
+			URL:     key[len(req.Method)+1:],
+			Headers: redactHeaders(req.Header),
+			Body:    string(requestBody),
+		},
+		Response: FixtureResponse{
+			Status:  resp.StatusCode,
+			Headers: redactHeaders(resp.Header),
+		},
+		RecordedAt: time.Now().UTC().Format(time.RFC3339),
+	}
+	if json.Valid(responseBody) {
+		fixture.Response.Body = responseBody
+	} else {
+		fixture.Response.RawBody = string(responseBody)
+	}
+
+	if err := r.writeFixture(r.nextPath(key), fixture); err != nil {
+		r.stats.errors.Add(1)
+		log.Printf("⚠️ Fixture yazılamadı (%s): %v", key, err)
+	} else {
+		r.stats.recorded.Add(1)
+	}
+
+	return resp, nil
+}
+
+func (r *Recorder) replay(req *http.Request) (*http.Response, error) {
+	key := r.requestKey(req)
+	path := r.nextPath(key)
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		// Background refreshes and repeated reads outlive the recording;
+		// keep serving the last response recorded for the request.
+		path, err = r.lastPath(key)
+		if err == nil {
+			data, err = os.ReadFile(path)
+			r.stats.reused.Add(1)
+		}
+	}
+	if err != nil {
+		r.stats.missing.Add(1)
+		log.Printf("❌ Fixture bulunamadı: %s", key)
+		return nil, fmt.Errorf("%w: %s", ErrFixtureNotFound, key)
+	}
+
+	var fixture Fixture
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		r.stats.errors.Add(1)
+		return nil, fmt.Errorf("fixture okunamadı (%s): %w", filepath.Base(path), err)
+	}
+
+	body := []byte(fixture.Response.RawBody)
+	if len(fixture.Response.Body) > 0 {
+		body = fixture.Response.Body
+	}
+
+	r.stats.replayed.Add(1)
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", fixture.Response.Status, http.StatusText(fixture.Response.Status)),
+		StatusCode:    fixture.Response.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        fixture.Response.Headers,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// requestKey identifies a request independently of the host it was sent to,
+// so fixtures recorded against discord.com replay against any APIURL.
+func (r *Recorder) requestKey(req *http.Request) string {
+	path := strings.TrimPrefix(req.URL.Path, strings.TrimSuffix(r.baseURL.Path, "/"))
+	if query := req.URL.Query().Encode(); query != "" {
+		path += "?" + query
+	}
+	return req.Method + " " + path
+}
+
+func (r *Recorder) nextPath(key string) string {
+	r.mutex.Lock()
+	r.sequences[key]++
+	sequence := r.sequences[key]
+	r.mutex.Unlock()
+
+	return r.fixturePath(key, sequence)
+}
+
+func (r *Recorder) lastPath(key string) (string, error) {
+	matches, err := filepath.Glob(r.fixturePath(key, -1))
+	if err != nil {
+		return "", err
+	}
+	if len(matches) == 0 {
+		return "", os.ErrNotExist
+	}
+	// Sequence numbers are zero padded, so lexical order is recording order.
+	return matches[len(matches)-1], nil
+}
+
+// fixturePath builds "GET_v9_guilds_123_members_<hash>_0001.json". The hash
+// covers the full key because the readable prefix drops the query string.
+// A negative sequence returns a glob matching every sequence.
+func (r *Recorder) fixturePath(key string, sequence int) string {
+	method, path, _ := strings.Cut(key, " ")
+	path, _, _ = strings.Cut(path, "?")
+	name := strings.Trim(unsafeFixtureChars.ReplaceAllString(path, "_"), "_")
+	if len(name) > 80 {
+		name = name[:80]
+	}
+
+	sum := sha1.Sum([]byte(key))
+	prefix := fmt.Sprintf("%s_%s_%s", method, name, hex.EncodeToString(sum[:4]))
+	if sequence < 0 {
+		return filepath.Join(r.dir, prefix+"_[0-9][0-9][0-9][0-9].json")
+	}
+	return filepath.Join(r.dir, fmt.Sprintf("%s_%04d.json", prefix, sequence))
+}
+
+func (r *Recorder) writeFixture(path string, fixture Fixture) error {
+	data, err := json.MarshalIndent(fixture, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func (r *Recorder) Stats() RecorderStats {
+	return RecorderStats{
+		Mode:       r.mode,
+		FixtureDir: r.dir,
+		Recorded:   r.stats.recorded.Load(),
+		Replayed:   r.stats.replayed.Load(),
+		Reused:     r.stats.reused.Load(),
+		Missing:    r.stats.missing.Load(),
+		Errors:     r.stats.errors.Load(),
+	}
+}
+
+func redactHeaders(header http.Header) http.Header {
+	redacted := header.Clone()
+	for _, name := range sensitiveHeaders {
+		if redacted.Get(name) != "" {
+			redacted.Set(name, redactedHeader)
+		}
+	}
+	return redacted
+}
@@ -0,0 +1,113 @@
+package discord
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"discord-user-api/discordtest"
+	"discord-user-api/snowflake"
+)
+
+func TestRecordReplayRoundTrip(t *testing.T) {
+	server, seed := startServer(t)
+	guildID := seed.Guilds[0].ID
+	dir := t.TempDir()
+
+	cfg := newTestConfig(server)
+	cfg.Discord.Recording.Mode = RecordingRecord
+	cfg.Discord.Recording.FixtureDir = dir
+	recording := newTestClient(t, server, cfg)
+
+	// The same request is answered 502 first and 200 on the retry, so the
+	// replay only succeeds if the fixtures come back in recorded order.
+	server.InjectFault(discordtest.Fault{Path: "/guilds/" + guildID.String(), Status: http.StatusBadGateway, Count: 1})
+	if _, err := recording.GetGuild(context.Background(), guildID); err != nil {
+		t.Fatal(err)
+	}
+	if stats := recording.GetRecorderStats(); stats.Recorded != 2 {
+		t.Fatalf("recorder stats = %+v", stats)
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 2 {
+		t.Fatalf("fixtures = %v", files)
+	}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(string(data), discordtest.DefaultToken) {
+			t.Errorf("%s token içeriyor", filepath.Base(file))
+		}
+		var fixture Fixture
+		if err := json.Unmarshal(data, &fixture); err != nil {
+			t.Fatal(err)
+		}
+		if got := fixture.Request.Headers.Get("Authorization"); got != redactedHeader {
+			t.Errorf("%s Authorization = %q", filepath.Base(file), got)
+		}
+	}
+
+	replayCfg := newTestConfig(server)
+	replayCfg.Discord.APIURL = "http://127.0.0.1:1/api"
+	replayCfg.Discord.Recording.Mode = RecordingReplay
+	replayCfg.Discord.Recording.FixtureDir = dir
+	replaying := newTestClient(t, server, replayCfg)
+	server.ResetRequests()
+
+	guild, err := replaying.GetGuild(context.Background(), guildID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if guild.Name != seed.Guilds[0].Name {
+		t.Errorf("guild = %q", guild.Name)
+	}
+	if stats := replaying.GetRetryStats(); stats.ByReason[retryReasonServerError] != 1 {
+		t.Errorf("502 önce oynatılmadı, retry stats = %+v", stats)
+	}
+	if got := server.RequestCount(); got != 0 {
+		t.Errorf("replay sunucuya %d istek gönderdi", got)
+	}
+
+	_, err = replaying.GetChannel(context.Background(), snowflake.Snowflake(1))
+	if !errors.Is(err, ErrFixtureNotFound) {
+		t.Fatalf("err = %v, want ErrFixtureNotFound", err)
+	}
+
+	stats := replaying.GetRecorderStats()
+	if stats.Replayed != 2 || stats.Missing != 1 {
+		t.Errorf("recorder stats = %+v", stats)
+	}
+}
+
+func TestRedactHeaders(t *testing.T) {
+	header := http.Header{}
+	header.Set("Authorization", "secret-token")
+	header.Set("Cookie", "session=abc")
+	header.Add("Set-Cookie", "__dcfduid=abc; Path=/")
+	header.Add("Set-Cookie", "__sdcfduid=def; Path=/")
+	header.Set("Content-Type", "application/json")
+
+	redacted := redactHeaders(header)
+	for _, name := range []string{"Authorization", "Cookie", "Set-Cookie"} {
+		if values := redacted.Values(name); len(values) != 1 || values[0] != redactedHeader {
+			t.Errorf("%s = %q", name, values)
+		}
+	}
+	if got := redacted.Get("Content-Type"); got != "application/json" {
+		t.Errorf("Content-Type = %q", got)
+	}
+	if got := header.Get("Authorization"); got != "secret-token" {
+		t.Errorf("orijinal header değişti: %q", got)
+	}
+}
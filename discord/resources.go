@@ -0,0 +1,229 @@
+package discord
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/url"
+	"strconv"
+	"time"
+
+	"discord-user-api/models"
+	"discord-user-api/snowflake"
+)
+
+const maxBansPageSize = 1000
+
+// Sub-resources change at very different rates: roles are edited often,
+// emojis and stickers rarely, while bans and invites are moderation data
+// that should never be far behind Discord.
+const (
+	rolesCacheTTL    = 5 * time.Minute
+	emojisCacheTTL   = 15 * time.Minute
+	stickersCacheTTL = 15 * time.Minute
+	bansCacheTTL     = 1 * time.Minute
+	invitesCacheTTL  = 1 * time.Minute
+)
+
+type BanQuery struct {
+	Before snowflake.Snowflake
+	After  snowflake.Snowflake
+	Limit  int
+}
+
+func (q BanQuery) values() url.Values {
+	values := url.Values{}
+	values.Set("limit", strconv.Itoa(q.Limit))
+	if q.Before.IsValid() {
+		values.Set("before", q.Before.String())
+	}
+	if q.After.IsValid() {
+		values.Set("after", q.After.String())
+	}
+	return values
+}
+
+func (c *Client) GetGuildRoles(ctx context.Context, guildID snowflake.Snowflake) ([]models.DiscordRole, error) {
+	cacheKey := fmt.Sprintf("guild_roles_%s", guildID)
+
+	cached, exists, fallback := c.cachedValue(ctx, cacheKey)
+	if exists {
+		if roles, ok := cached.([]models.DiscordRole); ok {
+			log.Printf("📤 Cache'den guild rolleri getirildi: %s (%d adet)", guildID, len(roles))
+			return roles, nil
+		}
+	}
+
+	url := fmt.Sprintf("%s/%s/guilds/%s/roles", c.config.Discord.APIURL, c.config.Discord.APIVersion, guildID)
+
+	roles, err := c.coalescedRequest(ctx, cacheKey, "GET", url, decodeRoles, func(value interface{}) {
+		c.cache.SetWithAutoRefresh(cacheKey, value, rolesCacheTTL, true, 2*time.Minute)
+		c.registerLoader(cacheKey, url, decodeRoles)
+	})
+	if err != nil {
+		if stale, ok := c.serveStale(ctx, cacheKey, fallback, err).([]models.DiscordRole); ok {
+			return stale, nil
+		}
+		return nil, fmt.Errorf("guild rolleri getirilemedi: %w", err)
+	}
+
+	rolesList := roles.([]models.DiscordRole)
+
+	log.Printf("✅ Guild rolleri başarıyla getirildi: %s (%d adet)", guildID, len(rolesList))
+	return rolesList, nil
+}
+
+func (c *Client) GetGuildEmojis(ctx context.Context, guildID snowflake.Snowflake) ([]models.DiscordEmoji, error) {
+	cacheKey := fmt.Sprintf("guild_emojis_%s", guildID)
+
+	cached, exists, fallback := c.cachedValue(ctx, cacheKey)
+	if exists {
+		if emojis, ok := cached.([]models.DiscordEmoji); ok {
+			log.Printf("📤 Cache'den guild emojileri getirildi: %s (%d adet)", guildID, len(emojis))
+			return emojis, nil
+		}
+	}
+
+	url := fmt.Sprintf("%s/%s/guilds/%s/emojis", c.config.Discord.APIURL, c.config.Discord.APIVersion, guildID)
+
+	emojis, err := c.coalescedRequest(ctx, cacheKey, "GET", url, decodeEmojis, func(value interface{}) {
+		c.cache.SetWithAutoRefresh(cacheKey, value, emojisCacheTTL, true, 10*time.Minute)
+		c.registerLoader(cacheKey, url, decodeEmojis)
+	})
+	if err != nil {
+		if stale, ok := c.serveStale(ctx, cacheKey, fallback, err).([]models.DiscordEmoji); ok {
+			return stale, nil
+		}
+		return nil, fmt.Errorf("guild emojileri getirilemedi: %w", err)
+	}
+
+	emojisList := emojis.([]models.DiscordEmoji)
+
+	log.Printf("✅ Guild emojileri başarıyla getirildi: %s (%d adet)", guildID, len(emojisList))
+	return emojisList, nil
+}
+
+func (c *Client) GetGuildStickers(ctx context.Context, guildID snowflake.Snowflake) ([]models.DiscordSticker, error) {
+	cacheKey := fmt.Sprintf("guild_stickers_%s", guildID)
+
+	cached, exists, fallback := c.cachedValue(ctx, cacheKey)
+	if exists {
+		if stickers, ok := cached.([]models.DiscordSticker); ok {
+			log.Printf("📤 Cache'den guild stickerları getirildi: %s (%d adet)", guildID, len(stickers))
+			return stickers, nil
+		}
+	}
+
+	url := fmt.Sprintf("%s/%s/guilds/%s/stickers", c.config.Discord.APIURL, c.config.Discord.APIVersion, guildID)
+
+	stickers, err := c.coalescedRequest(ctx, cacheKey, "GET", url, decodeStickers, func(value interface{}) {
+		c.cache.SetWithAutoRefresh(cacheKey, value, stickersCacheTTL, true, 10*time.Minute)
+		c.registerLoader(cacheKey, url, decodeStickers)
+	})
+	if err != nil {
+		if stale, ok := c.serveStale(ctx, cacheKey, fallback, err).([]models.DiscordSticker); ok {
+			return stale, nil
+		}
+		return nil, fmt.Errorf("guild stickerları getirilemedi: %w", err)
+	}
+
+	stickersList := stickers.([]models.DiscordSticker)
+
+	log.Printf("✅ Guild stickerları başarıyla getirildi: %s (%d adet)", guildID, len(stickersList))
+	return stickersList, nil
+}
+
+func (c *Client) GetGuildBans(ctx context.Context, guildID snowflake.Snowflake, query BanQuery) ([]models.DiscordBan, error) {
+	if query.Limit <= 0 || query.Limit > maxBansPageSize {
+		query.Limit = maxBansPageSize
+	}
+
+	values := query.values()
+	cacheKey := fmt.Sprintf("guild_bans_%s_%s", guildID, values.Encode())
+
+	cached, exists, fallback := c.cachedValue(ctx, cacheKey)
+	if exists {
+		if bans, ok := cached.([]models.DiscordBan); ok {
+			log.Printf("📤 Cache'den guild banları getirildi: %s (%d adet)", guildID, len(bans))
+			return bans, nil
+		}
+	}
+
+	url := fmt.Sprintf("%s/%s/guilds/%s/bans?%s", c.config.Discord.APIURL, c.config.Discord.APIVersion, guildID, values.Encode())
+
+	bans, err := c.coalescedRequest(ctx, cacheKey, "GET", url, decodeBans, func(value interface{}) {
+		c.cache.SetWithTTL(cacheKey, value, bansCacheTTL)
+	})
+	if err != nil {
+		if stale, ok := c.serveStale(ctx, cacheKey, fallback, err).([]models.DiscordBan); ok {
+			return stale, nil
+		}
+		return nil, fmt.Errorf("guild banları getirilemedi: %w", err)
+	}
+
+	bansList := bans.([]models.DiscordBan)
+
+	log.Printf("✅ Guild banları başarıyla getirildi: %s (%d adet)", guildID, len(bansList))
+	return bansList, nil
+}
+
+func (c *Client) GetGuildInvites(ctx context.Context, guildID snowflake.Snowflake) ([]models.DiscordInvite, error) {
+	cacheKey := fmt.Sprintf("guild_invites_%s", guildID)
+
+	cached, exists, fallback := c.cachedValue(ctx, cacheKey)
+	if exists {
+		if invites, ok := cached.([]models.DiscordInvite); ok {
+			log.Printf("📤 Cache'den guild davetleri getirildi: %s (%d adet)", guildID, len(invites))
+			return invites, nil
+		}
+	}
+
+	url := fmt.Sprintf("%s/%s/guilds/%s/invites", c.config.Discord.APIURL, c.config.Discord.APIVersion, guildID)
+
+	invites, err := c.coalescedRequest(ctx, cacheKey, "GET", url, decodeInvites, func(value interface{}) {
+		c.cache.SetWithTTL(cacheKey, value, invitesCacheTTL)
+	})
+	if err != nil {
+		if stale, ok := c.serveStale(ctx, cacheKey, fallback, err).([]models.DiscordInvite); ok {
+			return stale, nil
+		}
+		return nil, fmt.Errorf("guild davetleri getirilemedi: %w", err)
+	}
+
+	invitesList := invites.([]models.DiscordInvite)
+
+	log.Printf("✅ Guild davetleri başarıyla getirildi: %s (%d adet)", guildID, len(invitesList))
+	return invitesList, nil
+}
+
+func decodeRoles(body io.Reader) (interface{}, error) {
+	var roles []models.DiscordRole
+	err := json.NewDecoder(body).Decode(&roles)
+	return roles, err
+}
+
+func decodeEmojis(body io.Reader) (interface{}, error) {
+	var emojis []models.DiscordEmoji
+	err := json.NewDecoder(body).Decode(&emojis)
+	return emojis, err
+}
+
+func decodeStickers(body io.Reader) (interface{}, error) {
+	var stickers []models.DiscordSticker
+	err := json.NewDecoder(body).Decode(&stickers)
+	return stickers, err
+}
+
+func decodeBans(body io.Reader) (interface{}, error) {
+	var bans []models.DiscordBan
+	err := json.NewDecoder(body).Decode(&bans)
+	return bans, err
+}
+
+func decodeInvites(body io.Reader) (interface{}, error) {
+	var invites []models.DiscordInvite
+	err := json.NewDecoder(body).Decode(&invites)
+	return invites, err
+}
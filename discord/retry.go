@@ -0,0 +1,210 @@
+package discord
+
+import (
+	"errors"
+	"math/rand"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"discord-user-api/config"
+)
+
+var ErrRetryBudgetExhausted = errors.New("yeniden deneme bütçesi tükendi")
+
+const (
+	retryReasonNetwork       = "network"
+	retryReasonRateLimited   = "rate_limited"
+	retryReasonServerError   = "server_error"
+	retryReasonTokenFailover = "token_failover"
+)
+
+type RetryPolicy struct {
+	MaxRetries int
+	BaseDelay  time.Duration
+	MaxDelay   time.Duration
+
+	budget *retryBudget
+	stats  retryCounters
+}
+
+type retryBudget struct {
+	mutex        sync.Mutex
+	ratio        float64
+	minPerSecond float64
+	capacity     float64
+	tokens       float64
+	lastRefill   time.Time
+}
+
+type retryCounters struct {
+	requests        atomic.Int64
+	retries         atomic.Int64
+	network         atomic.Int64
+	rateLimited     atomic.Int64
+	serverError     atomic.Int64
+	tokenFailover   atomic.Int64
+	budgetExhausted atomic.Int64
+	gaveUp          atomic.Int64
+	notRetryable    atomic.Int64
+}
+
+type RetryStats struct {
+	Requests        int64            `json:"requests"`
+	Retries         int64            `json:"retries"`
+	ByReason        map[string]int64 `json:"by_reason"`
+	BudgetExhausted int64            `json:"budget_exhausted"`
+	GaveUp          int64            `json:"gave_up"`
+	NotRetryable    int64            `json:"not_retryable"`
+	BudgetTokens    float64          `json:"budget_tokens"`
+	BudgetCapacity  float64          `json:"budget_capacity"`
+}
+
+func NewRetryPolicy(cfg config.DiscordConfig) *RetryPolicy {
+	capacity := cfg.RetryBudgetMinPerSecond * 10
+	if capacity < 10 {
+		capacity = 10
+	}
+
+	return &RetryPolicy{
+		MaxRetries: cfg.MaxRetries,
+		BaseDelay:  cfg.RetryDelay,
+		MaxDelay:   cfg.MaxRetryDelay,
+		budget: &retryBudget{
+			ratio:        cfg.RetryBudgetRatio,
+			minPerSecond: cfg.RetryBudgetMinPerSecond,
+			capacity:     capacity,
+			tokens:       capacity,
+			lastRefill:   time.Now(),
+		},
+	}
+}
+
+// Backoff returns a full-jitter exponential delay for the given retry
+// attempt: a random duration in [0, min(MaxDelay, BaseDelay*2^(attempt-1))].
+func (p *RetryPolicy) Backoff(attempt int) time.Duration {
+	if attempt <= 0 || p.BaseDelay <= 0 {
+		return 0
+	}
+
+	ceiling := p.BaseDelay
+	for i := 1; i < attempt; i++ {
+		ceiling *= 2
+		if p.MaxDelay > 0 && ceiling >= p.MaxDelay {
+			break
+		}
+	}
+	if p.MaxDelay > 0 && ceiling > p.MaxDelay {
+		ceiling = p.MaxDelay
+	}
+
+	return time.Duration(rand.Int63n(int64(ceiling) + 1))
+}
+
+// Idempotent reports whether a request can be resent after an ambiguous
+// failure (network error or 5xx) without risking a duplicate side effect.
+func (p *RetryPolicy) Idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+func (p *RetryPolicy) RetryableStatus(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+func (p *RetryPolicy) recordRequest() {
+	p.stats.requests.Add(1)
+	p.budget.deposit()
+}
+
+func (p *RetryPolicy) allowRetry() bool {
+	if p.budget.withdraw() {
+		return true
+	}
+	p.stats.budgetExhausted.Add(1)
+	return false
+}
+
+func (p *RetryPolicy) recordRetry(reason string) {
+	p.stats.retries.Add(1)
+	switch reason {
+	case retryReasonNetwork:
+		p.stats.network.Add(1)
+	case retryReasonRateLimited:
+		p.stats.rateLimited.Add(1)
+	case retryReasonServerError:
+		p.stats.serverError.Add(1)
+	case retryReasonTokenFailover:
+		p.stats.tokenFailover.Add(1)
+	}
+}
+
+func (p *RetryPolicy) Stats() RetryStats {
+	p.budget.mutex.Lock()
+	p.budget.refill(time.Now())
+	tokens := p.budget.tokens
+	p.budget.mutex.Unlock()
+
+	return RetryStats{
+		Requests: p.stats.requests.Load(),
+		Retries:  p.stats.retries.Load(),
+		ByReason: map[string]int64{
+			retryReasonNetwork:       p.stats.network.Load(),
+			retryReasonRateLimited:   p.stats.rateLimited.Load(),
+			retryReasonServerError:   p.stats.serverError.Load(),
+			retryReasonTokenFailover: p.stats.tokenFailover.Load(),
+		},
+		BudgetExhausted: p.stats.budgetExhausted.Load(),
+		GaveUp:          p.stats.gaveUp.Load(),
+		NotRetryable:    p.stats.notRetryable.Load(),
+		BudgetTokens:    tokens,
+		BudgetCapacity:  p.budget.capacity,
+	}
+}
+
+// deposit credits the budget for every original request, so sustained
+// traffic can afford retries for roughly ratio of its requests.
+func (b *retryBudget) deposit() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.refill(time.Now())
+	b.tokens += b.ratio
+	if b.tokens > b.capacity {
+		b.tokens = b.capacity
+	}
+}
+
+func (b *retryBudget) withdraw() bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.refill(time.Now())
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+func (b *retryBudget) refill(now time.Time) {
+	elapsed := now.Sub(b.lastRefill).Seconds()
+	b.lastRefill = now
+	if elapsed <= 0 {
+		return
+	}
+
+	b.tokens += elapsed * b.minPerSecond
+	if b.tokens > b.capacity {
+		b.tokens = b.capacity
+	}
+}
@@ -0,0 +1,119 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"discord-user-api/cache"
+)
+
+type cacheStatusKey struct{}
+
+type CacheStatus struct {
+	mutex        sync.Mutex
+	stale        bool
+	age          time.Duration
+	revalidating bool
+	upstreamErr  error
+}
+
+func WithCacheStatus(ctx context.Context) (context.Context, *CacheStatus) {
+	status := &CacheStatus{}
+	return context.WithValue(ctx, cacheStatusKey{}, status), status
+}
+
+func (s *CacheStatus) Stale() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.stale
+}
+
+func (s *CacheStatus) Age() time.Duration {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.age
+}
+
+func (s *CacheStatus) Revalidating() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.revalidating
+}
+
+func (s *CacheStatus) LastError() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.upstreamErr
+}
+
+func (s *CacheStatus) mark(lookup cache.Lookup, err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.stale = true
+	if lookup.Age > s.age {
+		s.age = lookup.Age
+	}
+	s.revalidating = s.revalidating || lookup.Revalidating
+	if err != nil {
+		s.upstreamErr = err
+	}
+}
+
+func markStale(ctx context.Context, lookup cache.Lookup, err error) {
+	if status, ok := ctx.Value(cacheStatusKey{}).(*CacheStatus); ok {
+		status.mark(lookup, err)
+	}
+}
+
+// cachedValue returns entries that can be served right away: fresh ones and
+// stale ones whose background revalidation has started. A stale entry that
+// cannot be revalidated is handed back as fallback for serveStale instead.
+func (c *Client) cachedValue(ctx context.Context, key string) (interface{}, bool, *cache.Lookup) {
+	if !c.config.Cache.Enabled {
+		return nil, false, nil
+	}
+
+	lookup, exists := c.cache.Lookup(key)
+	if !exists {
+		return nil, false, nil
+	}
+
+	if !lookup.Stale {
+		return lookup.Value, true, nil
+	}
+
+	if lookup.Revalidating {
+		markStale(ctx, lookup, nil)
+		return lookup.Value, true, nil
+	}
+
+	return nil, false, &lookup
+}
+
+func (c *Client) serveStale(ctx context.Context, key string, fallback *cache.Lookup, err error) interface{} {
+	if fallback == nil || !isTransient(err) {
+		return nil
+	}
+
+	log.Printf("🕰️  Upstream hatası, stale cache sunuluyor: %s (Yaş: %v): %v", key, fallback.Age.Round(time.Second), err)
+	markStale(ctx, *fallback, err)
+	return fallback.Value
+}
+
+func isTransient(err error) bool {
+	var upstreamErr *UpstreamError
+	var rateLimitErr *RateLimitedError
+	var circuitErr *CircuitOpenError
+
+	switch {
+	case errors.As(err, &upstreamErr), errors.As(err, &rateLimitErr), errors.As(err, &circuitErr):
+		return true
+	case errors.Is(err, ErrNoHealthyToken), errors.Is(err, context.DeadlineExceeded):
+		return true
+	}
+	return false
+}
@@ -0,0 +1,313 @@
+package discord
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"discord-user-api/models"
+)
+
+var ErrNoHealthyToken = errors.New("kullanılabilir Discord token'ı yok")
+
+type TokenPool struct {
+	mutex    sync.Mutex
+	tokens   []*pooledToken
+	next     int
+	lastUsed *pooledToken
+}
+
+type pooledToken struct {
+	name           string
+	value          string
+	rateLimiter    *RateLimiter
+	healthy        bool
+	disabledReason string
+	disabledAt     time.Time
+	inFlight       int
+	requests       int64
+	failures       int64
+	lastUsed       time.Time
+	lastError      string
+	guilds         map[string]bool
+}
+
+type TokenStats struct {
+	Name           string            `json:"name"`
+	Healthy        bool              `json:"healthy"`
+	DisabledReason string            `json:"disabled_reason,omitempty"`
+	DisabledAt     string            `json:"disabled_at,omitempty"`
+	InFlight       int               `json:"in_flight"`
+	Requests       int64             `json:"requests"`
+	Failures       int64             `json:"failures"`
+	LastUsed       string            `json:"last_used,omitempty"`
+	LastError      string            `json:"last_error,omitempty"`
+	VisibleGuilds  int               `json:"visible_guilds"`
+	RateLimit      *models.RateLimit `json:"rate_limit"`
+}
+
+func NewTokenPool(tokens []string) *TokenPool {
+	pool := &TokenPool{}
+
+	for _, value := range tokens {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		pool.tokens = append(pool.tokens, &pooledToken{
+			name:        TokenName(len(pool.tokens)),
+			value:       value,
+			rateLimiter: NewRateLimiter(),
+			healthy:     true,
+			guilds:      make(map[string]bool),
+		})
+	}
+
+	log.Printf("🔑 Token havuzu oluşturuldu: %d token", len(pool.tokens))
+	return pool
+}
+
+func (p *TokenPool) Acquire(guildID string, exclude map[*pooledToken]bool) (*pooledToken, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	candidates := p.candidates(guildID, exclude)
+	if len(candidates) == 0 {
+		return nil, ErrNoHealthyToken
+	}
+
+	start := p.next % len(candidates)
+	p.next++
+
+	selected := candidates[start]
+	for i := 1; i < len(candidates); i++ {
+		candidate := candidates[(start+i)%len(candidates)]
+		if candidate.inFlight < selected.inFlight {
+			selected = candidate
+		}
+	}
+
+	selected.inFlight++
+	selected.requests++
+	selected.lastUsed = time.Now()
+	p.lastUsed = selected
+
+	return selected, nil
+}
+
+// AcquireNamed hands out one specific token, for requests whose answer
+// depends on the account making them.
+func (p *TokenPool) AcquireNamed(name string) (*pooledToken, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	for _, token := range p.tokens {
+		if token.name != name {
+			continue
+		}
+		if !token.healthy {
+			break
+		}
+		token.inFlight++
+		token.requests++
+		token.lastUsed = time.Now()
+		p.lastUsed = token
+		return token, nil
+	}
+	return nil, fmt.Errorf("%w: %s", ErrNoHealthyToken, name)
+}
+
+// Names lists the healthy tokens in configuration order.
+func (p *TokenPool) Names() []string {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	var names []string
+	for _, token := range p.tokens {
+		if token.healthy {
+			names = append(names, token.name)
+		}
+	}
+	return names
+}
+
+func (p *TokenPool) HasCandidate(guildID string, exclude map[*pooledToken]bool) bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	return len(p.candidates(guildID, exclude)) > 0
+}
+
+func (p *TokenPool) candidates(guildID string, exclude map[*pooledToken]bool) []*pooledToken {
+	var healthy, visible, unknown []*pooledToken
+
+	for _, token := range p.tokens {
+		if !token.healthy || exclude[token] {
+			continue
+		}
+		healthy = append(healthy, token)
+
+		if guildID == "" {
+			continue
+		}
+		if canSee, known := token.guilds[guildID]; known {
+			if canSee {
+				visible = append(visible, token)
+			}
+		} else {
+			unknown = append(unknown, token)
+		}
+	}
+
+	if guildID == "" {
+		return healthy
+	}
+	if len(visible) > 0 {
+		return visible
+	}
+	if len(unknown) > 0 {
+		return unknown
+	}
+	return healthy
+}
+
+func (p *TokenPool) Release(token *pooledToken, err error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	token.inFlight--
+	if err != nil {
+		token.failures++
+		token.lastError = err.Error()
+	}
+}
+
+func (p *TokenPool) Disable(token *pooledToken, reason string) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if !token.healthy {
+		return
+	}
+
+	token.healthy = false
+	token.disabledReason = reason
+	token.disabledAt = time.Now()
+	log.Printf("🚫 Token rotasyondan çıkarıldı: %s (%s)", token.name, reason)
+}
+
+func (p *TokenPool) MarkGuilds(token *pooledToken, guildIDs []string, visible bool) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	for _, guildID := range guildIDs {
+		token.guilds[guildID] = visible
+	}
+}
+
+func (p *TokenPool) Size() int {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	return len(p.tokens)
+}
+
+func (p *TokenPool) Stats() []TokenStats {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	stats := make([]TokenStats, 0, len(p.tokens))
+	for _, token := range p.tokens {
+		visible := 0
+		for _, canSee := range token.guilds {
+			if canSee {
+				visible++
+			}
+		}
+
+		stat := TokenStats{
+			Name:           token.name,
+			Healthy:        token.healthy,
+			DisabledReason: token.disabledReason,
+			InFlight:       token.inFlight,
+			Requests:       token.requests,
+			Failures:       token.failures,
+			LastError:      token.lastError,
+			VisibleGuilds:  visible,
+			RateLimit:      token.rateLimiter.Info(),
+		}
+		if !token.disabledAt.IsZero() {
+			stat.DisabledAt = token.disabledAt.Format(time.RFC3339)
+		}
+		if !token.lastUsed.IsZero() {
+			stat.LastUsed = token.lastUsed.Format(time.RFC3339)
+		}
+		stats = append(stats, stat)
+	}
+
+	return stats
+}
+
+func (p *TokenPool) RateLimitInfo() *models.RateLimit {
+	p.mutex.Lock()
+	tokens := append([]*pooledToken(nil), p.tokens...)
+	lastUsed := p.lastUsed
+	p.mutex.Unlock()
+
+	info := &models.RateLimit{}
+	for _, token := range tokens {
+		tokenInfo := token.rateLimiter.Info()
+
+		if token == lastUsed {
+			info.Limit = tokenInfo.Limit
+			info.Remaining = tokenInfo.Remaining
+			info.Reset = tokenInfo.Reset
+			info.ResetTime = tokenInfo.ResetTime
+			info.Bucket = tokenInfo.Bucket
+		}
+
+		if tokenInfo.Global {
+			info.Global = true
+			if tokenInfo.GlobalResetTime > info.GlobalResetTime {
+				info.GlobalResetTime = tokenInfo.GlobalResetTime
+			}
+		}
+
+		for _, bucket := range tokenInfo.Buckets {
+			bucket.Token = token.name
+			info.Buckets = append(info.Buckets, bucket)
+		}
+	}
+
+	sort.SliceStable(info.Buckets, func(i, j int) bool {
+		return info.Buckets[i].Token < info.Buckets[j].Token
+	})
+
+	return info
+}
+
+// TokenName is the pool name of the index'th configured token. The gateway
+// connects with the first one.
+func TokenName(index int) string {
+	return fmt.Sprintf("token_%d", index+1)
+}
+
+// AccountCacheKey scopes a cache key to one token, for data that differs per
+// account such as the guild list or profiles with mutual guilds.
+func AccountCacheKey(base, tokenName string) string {
+	return base + "_" + tokenName
+}
+
+func guildIDFromRoute(route string) string {
+	segments := strings.Split(route, "/")
+	for i := 0; i < len(segments)-1; i++ {
+		if segments[i] == "guilds" && isNumeric(segments[i+1]) {
+			return segments[i+1]
+		}
+	}
+	return ""
+}
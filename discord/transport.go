@@ -0,0 +1,138 @@
+package discord
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"discord-user-api/config"
+)
+
+const defaultUserAgent = "Discord-API-Client/1.0"
+
+func NewTransport(cfg config.DiscordConfig) (*http.Transport, error) {
+	proxy, err := proxyFunc(cfg.ProxyURL)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig, err := newTLSConfig(cfg.TLSMinVersion, cfg.CABundle)
+	if err != nil {
+		return nil, err
+	}
+
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: cfg.KeepAlive,
+	}
+
+	return &http.Transport{
+		Proxy:                 proxy,
+		DialContext:           dialer.DialContext,
+		TLSClientConfig:       tlsConfig,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          cfg.MaxIdleConns,
+		MaxIdleConnsPerHost:   cfg.MaxIdleConnsPerHost,
+		MaxConnsPerHost:       cfg.MaxConnsPerHost,
+		IdleConnTimeout:       cfg.IdleConnTimeout,
+		DisableKeepAlives:     cfg.DisableKeepAlives,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+	}, nil
+}
+
+func proxyFunc(rawURL string) (func(*http.Request) (*url.URL, error), error) {
+	if rawURL == "" {
+		return http.ProxyFromEnvironment, nil
+	}
+
+	proxyURL, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("geçersiz proxy URL'i: %w", err)
+	}
+
+	switch proxyURL.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("desteklenmeyen proxy şeması: %q (http, https, socks5 veya socks5h olmalı)", proxyURL.Scheme)
+	}
+	if proxyURL.Host == "" {
+		return nil, fmt.Errorf("proxy URL'inde host yok: %q", rawURL)
+	}
+
+	return http.ProxyURL(proxyURL), nil
+}
+
+func newTLSConfig(minVersion, caBundle string) (*tls.Config, error) {
+	version, err := parseTLSVersion(minVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig := &tls.Config{MinVersion: version}
+
+	if caBundle != "" {
+		pem, err := os.ReadFile(caBundle)
+		if err != nil {
+			return nil, fmt.Errorf("CA bundle okunamadı: %w", err)
+		}
+
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("CA bundle içinde geçerli sertifika yok: %s", caBundle)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	return tlsConfig, nil
+}
+
+func parseTLSVersion(value string) (uint16, error) {
+	switch strings.TrimPrefix(strings.ToLower(strings.TrimSpace(value)), "tls") {
+	case "", "1.2":
+		return tls.VersionTLS12, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
+	case "1.0":
+		return tls.VersionTLS10, nil
+	case "1.1":
+		return tls.VersionTLS11, nil
+	}
+	return 0, fmt.Errorf("geçersiz TLS sürümü: %q (1.0, 1.1, 1.2 veya 1.3 olmalı)", value)
+}
+
+// userAgent expands the configured template. Supported placeholders are
+// {method}, {route}, {token}, {attempt}, {os} and {go_version}.
+func (c *Client) userAgent(method, route string, token *pooledToken, attempt int) string {
+	template := c.config.Discord.UserAgent
+	if template == "" {
+		return defaultUserAgent
+	}
+	if !strings.Contains(template, "{") {
+		return template
+	}
+
+	return strings.NewReplacer(
+		"{method}", method,
+		"{route}", route,
+		"{token}", token.name,
+		"{attempt}", strconv.Itoa(attempt),
+		"{os}", runtime.GOOS,
+		"{go_version}", runtime.Version(),
+	).Replace(template)
+}
+
+func (c *Client) Transport() *http.Transport {
+	return c.transport
+}
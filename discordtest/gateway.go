@@ -0,0 +1,261 @@
+package discordtest
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	ws "github.com/gorilla/websocket"
+
+	"discord-user-api/config"
+)
+
+var ErrGatewayTimeout = errors.New("discordtest: gateway beklemesi zaman aşımına uğradı")
+
+// Gateway is a fake Discord gateway. Every accepted connection gets HELLO
+// right away and is then handed to the test through NextConn, which scripts
+// the rest: READY, dispatches, op 7/9, closes or dropped sockets. Heartbeats
+// are answered with ACKs unless AckHeartbeats is turned off.
+type Gateway struct {
+	*httptest.Server
+
+	HeartbeatInterval time.Duration
+
+	mutex         sync.Mutex
+	ackHeartbeats bool
+	upgrader      ws.Upgrader
+	conns         chan *GatewayConn
+}
+
+type GatewayPayload struct {
+	Op       int             `json:"op"`
+	Data     json.RawMessage `json:"d"`
+	Sequence *int64          `json:"s,omitempty"`
+	Type     string          `json:"t,omitempty"`
+}
+
+// GatewayConn is one client connection as seen by the fake gateway.
+type GatewayConn struct {
+	Query url.Values
+
+	gateway    *Gateway
+	conn       *ws.Conn
+	writeMutex sync.Mutex
+	compressed bool
+	buffer     bytes.Buffer
+	zlib       *zlib.Writer
+	frames     chan GatewayPayload
+	heartbeats chan GatewayPayload
+	done       chan struct{}
+}
+
+func NewGateway() *Gateway {
+	g := &Gateway{
+		HeartbeatInterval: 50 * time.Millisecond,
+		ackHeartbeats:     true,
+		conns:             make(chan *GatewayConn, 16),
+	}
+	g.Server = httptest.NewServer(http.HandlerFunc(g.serveHTTP))
+	return g
+}
+
+// WebSocketURL is the ws:// address of the fake gateway.
+func (g *Gateway) WebSocketURL() string {
+	return "ws" + strings.TrimPrefix(g.URL, "http")
+}
+
+// ConfigureClient points the gateway settings in cfg at the fake gateway.
+func (g *Gateway) ConfigureClient(cfg *config.Config) {
+	cfg.Discord.Gateway.URL = g.WebSocketURL()
+	cfg.Discord.Gateway.HandshakeTimeout = time.Second
+	if cfg.Discord.APIVersion == "" {
+		cfg.Discord.APIVersion = "v9"
+	}
+	if len(cfg.Discord.Tokens) == 0 {
+		cfg.Discord.Token = DefaultToken
+		cfg.Discord.Tokens = []string{DefaultToken}
+	}
+}
+
+func (g *Gateway) SetAckHeartbeats(ack bool) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	g.ackHeartbeats = ack
+}
+
+// NextConn waits for the next client connection.
+func (g *Gateway) NextConn(timeout time.Duration) (*GatewayConn, error) {
+	select {
+	case conn := <-g.conns:
+		return conn, nil
+	case <-time.After(timeout):
+		return nil, ErrGatewayTimeout
+	}
+}
+
+func (g *Gateway) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	conn, err := g.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+
+	c := &GatewayConn{
+		Query:      r.URL.Query(),
+		gateway:    g,
+		conn:       conn,
+		compressed: r.URL.Query().Get("compress") == "zlib-stream",
+		frames:     make(chan GatewayPayload, 64),
+		heartbeats: make(chan GatewayPayload, 64),
+		done:       make(chan struct{}),
+	}
+	if c.compressed {
+		c.zlib = zlib.NewWriter(&c.buffer)
+	}
+
+	interval := g.HeartbeatInterval.Milliseconds()
+	if err := c.Send(10, map[string]int64{"heartbeat_interval": interval}); err != nil {
+		conn.Close()
+		return
+	}
+
+	go c.readLoop()
+	g.conns <- c
+}
+
+func (c *GatewayConn) readLoop() {
+	defer close(c.done)
+
+	for {
+		_, message, err := c.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		var payload GatewayPayload
+		if err := json.Unmarshal(message, &payload); err != nil {
+			continue
+		}
+
+		if payload.Op != 1 {
+			c.frames <- payload
+			continue
+		}
+
+		select {
+		case c.heartbeats <- payload:
+		default:
+		}
+
+		c.gateway.mutex.Lock()
+		ack := c.gateway.ackHeartbeats
+		c.gateway.mutex.Unlock()
+		if ack {
+			c.Send(11, nil)
+		}
+	}
+}
+
+// Read returns the next frame the client sent, heartbeats excluded.
+func (c *GatewayConn) Read(timeout time.Duration) (GatewayPayload, error) {
+	select {
+	case payload := <-c.frames:
+		return payload, nil
+	case <-time.After(timeout):
+		return GatewayPayload{}, ErrGatewayTimeout
+	}
+}
+
+// Heartbeat returns the next heartbeat the client sent.
+func (c *GatewayConn) Heartbeat(timeout time.Duration) (GatewayPayload, error) {
+	select {
+	case payload := <-c.heartbeats:
+		return payload, nil
+	case <-time.After(timeout):
+		return GatewayPayload{}, ErrGatewayTimeout
+	}
+}
+
+// Closed waits until the client side of the connection has gone away.
+func (c *GatewayConn) Closed(timeout time.Duration) error {
+	select {
+	case <-c.done:
+		return nil
+	case <-time.After(timeout):
+		return ErrGatewayTimeout
+	}
+}
+
+func (c *GatewayConn) Send(op int, data interface{}) error {
+	return c.write(op, "", nil, data)
+}
+
+func (c *GatewayConn) Dispatch(eventType string, sequence int64, data interface{}) error {
+	return c.write(0, eventType, &sequence, data)
+}
+
+// Ready dispatches READY with sessionID and points resume_gateway_url back at
+// this gateway.
+func (c *GatewayConn) Ready(sequence int64, sessionID string) error {
+	return c.Dispatch("READY", sequence, map[string]interface{}{
+		"v":                  9,
+		"session_id":         sessionID,
+		"resume_gateway_url": c.gateway.WebSocketURL(),
+	})
+}
+
+// CloseWithCode sends a close frame, as Discord does for 4xxx errors.
+func (c *GatewayConn) CloseWithCode(code int, reason string) error {
+	c.writeMutex.Lock()
+	defer c.writeMutex.Unlock()
+
+	message := ws.FormatCloseMessage(code, reason)
+	err := c.conn.WriteControl(ws.CloseMessage, message, time.Now().Add(time.Second))
+	c.conn.Close()
+	return err
+}
+
+// Drop closes the socket without a close frame, like a network failure.
+func (c *GatewayConn) Drop() error {
+	return c.conn.Close()
+}
+
+// write sends one payload. With zlib-stream every payload goes through the
+// same compressor and ends on a sync flush, as Discord sends them.
+func (c *GatewayConn) write(op int, eventType string, sequence *int64, data interface{}) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	message, err := json.Marshal(GatewayPayload{Op: op, Data: raw, Sequence: sequence, Type: eventType})
+	if err != nil {
+		return err
+	}
+
+	c.writeMutex.Lock()
+	defer c.writeMutex.Unlock()
+
+	c.conn.SetWriteDeadline(time.Now().Add(time.Second))
+	if !c.compressed {
+		return c.conn.WriteMessage(ws.TextMessage, message)
+	}
+
+	c.buffer.Reset()
+	if _, err := c.zlib.Write(message); err != nil {
+		return err
+	}
+	if err := c.zlib.Flush(); err != nil {
+		return err
+	}
+	if !bytes.HasSuffix(c.buffer.Bytes(), []byte{0x00, 0x00, 0xff, 0xff}) {
+		return fmt.Errorf("discordtest: zlib flush sonu eksik")
+	}
+	return c.conn.WriteMessage(ws.BinaryMessage, c.buffer.Bytes())
+}
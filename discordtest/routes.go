@@ -0,0 +1,420 @@
+package discordtest
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+
+	"discord-user-api/models"
+	"discord-user-api/snowflake"
+)
+
+const (
+	codeUnknownChannel = 10003
+	codeUnknownGuild   = 10004
+	codeUnknownMember  = 10007
+	codeUnknownUser    = 10013
+	codeInvalidBody    = 50035
+)
+
+func (s *Server) routes() {
+	s.mux.HandleFunc("GET /users/@me", s.handleCurrentUser)
+	s.mux.HandleFunc("GET /users/@me/guilds", s.handleCurrentUserGuilds)
+	s.mux.HandleFunc("GET /users/{user_id}/profile", s.handleUserProfile)
+	s.mux.HandleFunc("GET /guilds/{guild_id}", s.handleGuild)
+	s.mux.HandleFunc("GET /guilds/{guild_id}/members", s.handleGuildMembers)
+	s.mux.HandleFunc("GET /guilds/{guild_id}/members/{user_id}", s.handleGuildMember)
+	s.mux.HandleFunc("GET /guilds/{guild_id}/channels", s.handleGuildChannels)
+	s.mux.HandleFunc("GET /guilds/{guild_id}/roles", s.handleGuildRoles)
+	s.mux.HandleFunc("GET /guilds/{guild_id}/emojis", s.handleGuildEmojis)
+	s.mux.HandleFunc("GET /guilds/{guild_id}/stickers", s.handleGuildStickers)
+	s.mux.HandleFunc("GET /guilds/{guild_id}/bans", s.handleGuildBans)
+	s.mux.HandleFunc("GET /guilds/{guild_id}/invites", s.handleGuildInvites)
+	s.mux.HandleFunc("GET /guilds/{guild_id}/audit-logs", s.handleGuildAuditLog)
+	s.mux.HandleFunc("GET /channels/{channel_id}", s.handleChannel)
+	s.mux.HandleFunc("GET /channels/{channel_id}/messages", s.handleChannelMessages)
+}
+
+func (s *Server) handleCurrentUser(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	writeJSON(w, http.StatusOK, s.data.CurrentUser)
+}
+
+func (s *Server) handleCurrentUserGuilds(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	withCounts := r.URL.Query().Get("with_counts") == "true"
+	guilds := make([]models.DiscordGuild, 0, len(s.data.Guilds))
+	for _, guild := range s.data.Guilds {
+		partial := models.DiscordGuild{
+			ID:          guild.ID,
+			Name:        guild.Name,
+			Icon:        guild.Icon,
+			Banner:      guild.Banner,
+			Owner:       guild.Owner,
+			Permissions: guild.Permissions,
+			Features:    guild.Features,
+		}
+		if withCounts {
+			partial.ApproximateMemberCount = guild.ApproximateMemberCount
+			partial.ApproximatePresenceCount = guild.ApproximatePresenceCount
+		}
+		guilds = append(guilds, partial)
+	}
+	writeJSON(w, http.StatusOK, guilds)
+}
+
+func (s *Server) handleUserProfile(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	userID, _ := snowflake.Parse(r.PathValue("user_id"))
+	index := slices.IndexFunc(s.data.Users, func(user models.DiscordUser) bool { return user.ID == userID })
+	if index < 0 {
+		unknown(w, codeUnknownUser, "User")
+		return
+	}
+
+	profile := models.DiscordProfile{User: s.data.Users[index]}
+	for _, guild := range s.data.Guilds {
+		for _, member := range s.data.Members[guild.ID] {
+			if member.User.ID == userID {
+				profile.MutualGuilds = append(profile.MutualGuilds, struct {
+					ID   snowflake.Snowflake `json:"id"`
+					Nick string              `json:"nick"`
+				}{ID: guild.ID, Nick: member.Nick})
+			}
+		}
+	}
+
+	writeJSON(w, http.StatusOK, profile)
+}
+
+func (s *Server) handleGuild(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	guild, ok := s.guild(w, r)
+	if !ok {
+		return
+	}
+	if r.URL.Query().Get("with_counts") != "true" {
+		guild.ApproximateMemberCount = 0
+		guild.ApproximatePresenceCount = 0
+	}
+	writeJSON(w, http.StatusOK, guild)
+}
+
+func (s *Server) handleGuildMembers(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	guild, ok := s.guild(w, r)
+	if !ok {
+		return
+	}
+
+	limit, ok := queryLimit(w, r, 1, 1000)
+	if !ok {
+		return
+	}
+	after, _ := snowflake.ParseOptional(r.URL.Query().Get("after"))
+
+	members := slices.Clone(s.data.Members[guild.ID])
+	slices.SortFunc(members, func(a, b models.DiscordGuildMember) int {
+		return compareIDs(a.User.ID, b.User.ID)
+	})
+
+	page := make([]models.DiscordGuildMember, 0, limit)
+	for _, member := range members {
+		if member.User.ID > after && len(page) < limit {
+			page = append(page, member)
+		}
+	}
+	writeJSON(w, http.StatusOK, page)
+}
+
+func (s *Server) handleGuildMember(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	guild, ok := s.guild(w, r)
+	if !ok {
+		return
+	}
+
+	userID, _ := snowflake.Parse(r.PathValue("user_id"))
+	for _, member := range s.data.Members[guild.ID] {
+		if member.User.ID == userID {
+			writeJSON(w, http.StatusOK, member)
+			return
+		}
+	}
+	unknown(w, codeUnknownMember, "Member")
+}
+
+func (s *Server) handleGuildChannels(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	guild, ok := s.guild(w, r)
+	if !ok {
+		return
+	}
+
+	channels := []models.DiscordChannel{}
+	for _, channel := range s.data.Channels {
+		if channel.GuildID == guild.ID {
+			channels = append(channels, channel)
+		}
+	}
+	writeJSON(w, http.StatusOK, channels)
+}
+
+func (s *Server) handleGuildRoles(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if guild, ok := s.guild(w, r); ok {
+		writeJSON(w, http.StatusOK, append([]models.DiscordRole{}, guild.Roles...))
+	}
+}
+
+func (s *Server) handleGuildEmojis(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if guild, ok := s.guild(w, r); ok {
+		writeJSON(w, http.StatusOK, append([]models.DiscordEmoji{}, guild.Emojis...))
+	}
+}
+
+func (s *Server) handleGuildStickers(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if guild, ok := s.guild(w, r); ok {
+		writeJSON(w, http.StatusOK, append([]models.DiscordSticker{}, guild.Stickers...))
+	}
+}
+
+func (s *Server) handleGuildBans(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	guild, ok := s.guild(w, r)
+	if !ok {
+		return
+	}
+
+	limit, ok := queryLimit(w, r, 1000, 1000)
+	if !ok {
+		return
+	}
+	before, _ := snowflake.ParseOptional(r.URL.Query().Get("before"))
+	after, _ := snowflake.ParseOptional(r.URL.Query().Get("after"))
+
+	bans := slices.Clone(s.data.Bans[guild.ID])
+	slices.SortFunc(bans, func(a, b models.DiscordBan) int {
+		return compareIDs(a.User.ID, b.User.ID)
+	})
+
+	page := []models.DiscordBan{}
+	for _, ban := range bans {
+		if (before.IsValid() && ban.User.ID >= before) || ban.User.ID <= after {
+			continue
+		}
+		page = append(page, ban)
+	}
+	if len(page) > limit {
+		if before.IsValid() && !after.IsValid() {
+			page = page[len(page)-limit:]
+		} else {
+			page = page[:limit]
+		}
+	}
+	writeJSON(w, http.StatusOK, page)
+}
+
+func (s *Server) handleGuildInvites(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if guild, ok := s.guild(w, r); ok {
+		writeJSON(w, http.StatusOK, append([]models.DiscordInvite{}, s.data.Invites[guild.ID]...))
+	}
+}
+
+func (s *Server) handleGuildAuditLog(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	guild, ok := s.guild(w, r)
+	if !ok {
+		return
+	}
+
+	limit, ok := queryLimit(w, r, 50, 100)
+	if !ok {
+		return
+	}
+	query := r.URL.Query()
+	userID, _ := snowflake.ParseOptional(query.Get("user_id"))
+	before, _ := snowflake.ParseOptional(query.Get("before"))
+	after, _ := snowflake.ParseOptional(query.Get("after"))
+	actionType, _ := strconv.Atoi(query.Get("action_type"))
+
+	entries := slices.Clone(s.data.AuditLogs[guild.ID])
+	slices.SortFunc(entries, func(a, b models.AuditLogEntry) int {
+		return compareIDs(b.ID, a.ID)
+	})
+
+	auditLog := models.AuditLog{
+		AuditLogEntries: []models.AuditLogEntry{},
+		Users:           []models.DiscordUser{},
+		Webhooks:        []models.Webhook{},
+		Integrations:    []models.Integration{},
+	}
+	users := make(map[snowflake.Snowflake]bool)
+	for _, entry := range entries {
+		switch {
+		case userID.IsValid() && entry.UserID != userID:
+			continue
+		case actionType > 0 && int(entry.ActionType) != actionType:
+			continue
+		case before.IsValid() && entry.ID >= before:
+			continue
+		case entry.ID <= after:
+			continue
+		}
+		if len(auditLog.AuditLogEntries) == limit {
+			break
+		}
+		auditLog.AuditLogEntries = append(auditLog.AuditLogEntries, entry)
+
+		for _, id := range []snowflake.Snowflake{entry.UserID, entry.TargetID} {
+			if users[id] {
+				continue
+			}
+			for _, user := range s.data.Users {
+				if user.ID == id {
+					auditLog.Users = append(auditLog.Users, user)
+					users[id] = true
+				}
+			}
+		}
+	}
+	writeJSON(w, http.StatusOK, auditLog)
+}
+
+func (s *Server) handleChannel(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if channel, ok := s.channel(w, r); ok {
+		writeJSON(w, http.StatusOK, channel)
+	}
+}
+
+func (s *Server) handleChannelMessages(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	channel, ok := s.channel(w, r)
+	if !ok {
+		return
+	}
+
+	limit, ok := queryLimit(w, r, 50, 100)
+	if !ok {
+		return
+	}
+	query := r.URL.Query()
+	before, _ := snowflake.ParseOptional(query.Get("before"))
+	after, _ := snowflake.ParseOptional(query.Get("after"))
+	around, _ := snowflake.ParseOptional(query.Get("around"))
+
+	// Oldest first while selecting; Discord always answers newest first.
+	messages := slices.Clone(s.data.Messages[channel.ID])
+	slices.SortFunc(messages, func(a, b models.DiscordMessage) int {
+		return compareIDs(a.ID, b.ID)
+	})
+
+	var page []models.DiscordMessage
+	switch {
+	case after.IsValid():
+		for _, message := range messages {
+			if message.ID > after && len(page) < limit {
+				page = append(page, message)
+			}
+		}
+	case around.IsValid():
+		center, _ := slices.BinarySearchFunc(messages, around, func(message models.DiscordMessage, id snowflake.Snowflake) int {
+			return compareIDs(message.ID, id)
+		})
+		start := max(0, center-limit/2)
+		page = messages[start:min(len(messages), start+limit)]
+	default:
+		for _, message := range messages {
+			if !before.IsValid() || message.ID < before {
+				page = append(page, message)
+			}
+		}
+		if len(page) > limit {
+			page = page[len(page)-limit:]
+		}
+	}
+
+	page = append([]models.DiscordMessage{}, page...)
+	slices.Reverse(page)
+	writeJSON(w, http.StatusOK, page)
+}
+
+// guild must be called with the mutex held.
+func (s *Server) guild(w http.ResponseWriter, r *http.Request) (models.DiscordGuild, bool) {
+	guildID, _ := snowflake.Parse(r.PathValue("guild_id"))
+	for _, guild := range s.data.Guilds {
+		if guild.ID == guildID {
+			return guild, true
+		}
+	}
+	unknown(w, codeUnknownGuild, "Guild")
+	return models.DiscordGuild{}, false
+}
+
+// channel must be called with the mutex held.
+func (s *Server) channel(w http.ResponseWriter, r *http.Request) (models.DiscordChannel, bool) {
+	channelID, _ := snowflake.Parse(r.PathValue("channel_id"))
+	for _, channel := range s.data.Channels {
+		if channel.ID == channelID {
+			return channel, true
+		}
+	}
+	unknown(w, codeUnknownChannel, "Channel")
+	return models.DiscordChannel{}, false
+}
+
+func queryLimit(w http.ResponseWriter, r *http.Request, fallback, maximum int) (int, bool) {
+	value := r.URL.Query().Get("limit")
+	if value == "" {
+		return fallback, true
+	}
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit < 1 || limit > maximum {
+		writeError(w, http.StatusBadRequest, codeInvalidBody, "Invalid Form Body")
+		return 0, false
+	}
+	return limit, true
+}
+
+func compareIDs(a, b snowflake.Snowflake) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
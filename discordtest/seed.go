@@ -0,0 +1,144 @@
+package discordtest
+
+import (
+	"maps"
+	"slices"
+	"time"
+
+	"discord-user-api/models"
+	"discord-user-api/snowflake"
+)
+
+// Seed is the data the fake API serves. Per-guild and per-channel data is
+// keyed by the owning guild or channel ID.
+type Seed struct {
+	CurrentUser models.DiscordUser
+	Users       []models.DiscordUser
+	Guilds      []models.DiscordGuild
+	Channels    []models.DiscordChannel
+	Members     map[snowflake.Snowflake][]models.DiscordGuildMember
+	Messages    map[snowflake.Snowflake][]models.DiscordMessage
+	Bans        map[snowflake.Snowflake][]models.DiscordBan
+	Invites     map[snowflake.Snowflake][]models.DiscordInvite
+	AuditLogs   map[snowflake.Snowflake][]models.AuditLogEntry
+}
+
+// DefaultSeed returns a small deterministic dataset: the current user, three
+// other users and two guilds with roles, emojis, a sticker, channels,
+// messages, a ban, an invite and an audit log entry.
+func DefaultSeed() Seed {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	id := func(n int) snowflake.Snowflake {
+		return snowflake.FromTime(base.Add(time.Duration(n) * time.Minute))
+	}
+
+	me := models.DiscordUser{ID: id(1), Username: "discordtest", GlobalName: "Discord Test", Discriminator: "0", Verified: true}
+	alice := models.DiscordUser{ID: id(2), Username: "alice", GlobalName: "Alice", Discriminator: "0"}
+	bob := models.DiscordUser{ID: id(3), Username: "bob", GlobalName: "Bob", Discriminator: "0"}
+	carol := models.DiscordUser{ID: id(4), Username: "carol", GlobalName: "Carol", Discriminator: "0"}
+	spammer := models.DiscordUser{ID: id(5), Username: "spammer", Discriminator: "0"}
+
+	guildID := id(10)
+	secondGuildID := id(20)
+	moderatorRole := models.DiscordRole{ID: id(11), Name: "Moderator", Permissions: "1099511627775", Position: 1, Color: 0x3498db, Hoist: true, Mentionable: true}
+
+	general := models.DiscordChannel{ID: id(12), Type: models.ChannelTypeGuildText, GuildID: guildID, Name: "general", Position: 0}
+	voice := models.DiscordChannel{ID: id(13), Type: models.ChannelTypeGuildVoice, GuildID: guildID, Name: "Voice", Position: 1, Bitrate: 64000}
+	secondGeneral := models.DiscordChannel{ID: id(21), Type: models.ChannelTypeGuildText, GuildID: secondGuildID, Name: "general", Position: 0}
+
+	joined := base.Format(time.RFC3339)
+
+	return Seed{
+		CurrentUser: me,
+		Users:       []models.DiscordUser{me, alice, bob, carol, spammer},
+		Guilds: []models.DiscordGuild{
+			{
+				ID:          guildID,
+				Name:        "Test Guild",
+				OwnerID:     me.ID,
+				Owner:       true,
+				Permissions: "2251799813685247",
+				Features:    []string{"COMMUNITY"},
+				Roles: []models.DiscordRole{
+					{ID: guildID, Name: "@everyone", Permissions: "1071698660929"},
+					moderatorRole,
+				},
+				Emojis: []models.DiscordEmoji{
+					{ID: id(14), Name: "wave", RequireColons: true, Available: true},
+				},
+				Stickers: []models.DiscordSticker{
+					{ID: id(15), Name: "hello", Tags: "wave", Type: models.StickerTypeGuild, FormatType: models.StickerFormatPNG, Available: true, GuildID: guildID},
+				},
+				ApproximateMemberCount:   3,
+				ApproximatePresenceCount: 2,
+			},
+			{
+				ID:          secondGuildID,
+				Name:        "Second Guild",
+				OwnerID:     carol.ID,
+				Permissions: "1071698660929",
+				Features:    []string{},
+				Roles: []models.DiscordRole{
+					{ID: secondGuildID, Name: "@everyone", Permissions: "1071698660929"},
+				},
+				ApproximateMemberCount:   2,
+				ApproximatePresenceCount: 1,
+			},
+		},
+		Channels: []models.DiscordChannel{general, voice, secondGeneral},
+		Members: map[snowflake.Snowflake][]models.DiscordGuildMember{
+			guildID: {
+				{User: me, Roles: []snowflake.Snowflake{moderatorRole.ID}, JoinedAt: joined},
+				{User: alice, Nick: "Ally", Roles: []snowflake.Snowflake{}, JoinedAt: joined},
+				{User: bob, Roles: []snowflake.Snowflake{}, JoinedAt: joined},
+			},
+			secondGuildID: {
+				{User: me, Roles: []snowflake.Snowflake{}, JoinedAt: joined},
+				{User: carol, Roles: []snowflake.Snowflake{}, JoinedAt: joined},
+			},
+		},
+		Messages: map[snowflake.Snowflake][]models.DiscordMessage{
+			general.ID: {
+				{ID: id(30), ChannelID: general.ID, GuildID: guildID, Author: me, Content: "Merhaba!", Timestamp: base.Add(30 * time.Minute).Format(time.RFC3339)},
+				{ID: id(31), ChannelID: general.ID, GuildID: guildID, Author: alice, Content: "Selam 👋", Timestamp: base.Add(31 * time.Minute).Format(time.RFC3339)},
+				{ID: id(32), ChannelID: general.ID, GuildID: guildID, Author: bob, Content: "Nasılsınız?", Timestamp: base.Add(32 * time.Minute).Format(time.RFC3339)},
+			},
+		},
+		Bans: map[snowflake.Snowflake][]models.DiscordBan{
+			guildID: {{Reason: "spam", User: spammer}},
+		},
+		Invites: map[snowflake.Snowflake][]models.DiscordInvite{
+			guildID: {{
+				Code:      "discordtest",
+				Guild:     &models.InviteGuild{ID: guildID, Name: "Test Guild", Features: []string{"COMMUNITY"}},
+				Channel:   &models.InviteChannel{ID: general.ID, Name: general.Name, Type: general.Type},
+				Inviter:   &me,
+				Uses:      3,
+				MaxAge:    86400,
+				CreatedAt: joined,
+			}},
+		},
+		AuditLogs: map[snowflake.Snowflake][]models.AuditLogEntry{
+			guildID: {{
+				ID:         id(40),
+				TargetID:   spammer.ID,
+				UserID:     me.ID,
+				ActionType: models.AuditLogMemberBanAdd,
+				Reason:     "spam",
+			}},
+		},
+	}
+}
+
+func (s Seed) clone() Seed {
+	cloned := s
+	cloned.Users = slices.Clone(s.Users)
+	cloned.Guilds = slices.Clone(s.Guilds)
+	cloned.Channels = slices.Clone(s.Channels)
+	cloned.Members = maps.Clone(s.Members)
+	cloned.Messages = maps.Clone(s.Messages)
+	cloned.Bans = maps.Clone(s.Bans)
+	cloned.Invites = maps.Clone(s.Invites)
+	cloned.AuditLogs = maps.Clone(s.AuditLogs)
+	return cloned
+}
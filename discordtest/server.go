@@ -0,0 +1,355 @@
+// Package discordtest provides in-process fakes of the Discord REST API and
+// gateway for unit tests, integration tests and offline demos. Point
+// Config.Discord.APIURL at Server.URL (or call ConfigureClient) and
+// discord.Client talks to it like it would to discord.com; Gateway does the
+// same for gateway.Client.
+package discordtest
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"discord-user-api/config"
+)
+
+const DefaultToken = "discordtest-token"
+
+var versionPrefix = regexp.MustCompile(`^(/api)?/v\d+`)
+
+type Server struct {
+	*httptest.Server
+
+	mutex    sync.Mutex
+	mux      *http.ServeMux
+	data     Seed
+	tokens   map[string]bool
+	limit    RateLimit
+	global   RateLimit
+	buckets  map[string]*bucket
+	faults   []*Fault
+	requests []Request
+}
+
+// RateLimit describes a fixed window: Limit requests per Window. A zero
+// Limit disables it.
+type RateLimit struct {
+	Limit  int
+	Window time.Duration
+}
+
+// Fault is applied to matching requests before they reach the handler.
+// Method and Path (a prefix of the unversioned path, e.g. "/guilds/1")
+// narrow the match; empty values match everything. Count limits how many
+// requests are affected, zero means until ClearFaults. Code is the Discord
+// error code sent along with Status.
+type Fault struct {
+	Method     string
+	Path       string
+	Latency    time.Duration
+	Status     int
+	Code       int
+	RetryAfter time.Duration
+	Malformed  bool
+	Count      int
+
+	hits int
+}
+
+type Request struct {
+	Method string
+	Path   string
+	Query  string
+	Status int
+	Time   time.Time
+}
+
+type bucket struct {
+	hash      string
+	remaining int
+	resetAt   time.Time
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func NewServer(seed Seed) *Server {
+	s := &Server{
+		mux:     http.NewServeMux(),
+		data:    seed.clone(),
+		tokens:  map[string]bool{DefaultToken: true},
+		limit:   RateLimit{Limit: 50, Window: time.Second},
+		buckets: make(map[string]*bucket),
+	}
+	s.routes()
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// ConfigureClient points cfg at the fake server with its default token and
+// short retry delays so failing tests do not hang.
+func (s *Server) ConfigureClient(cfg *config.Config) {
+	cfg.Discord.APIURL = s.URL + "/api"
+	cfg.Discord.APIVersion = "v9"
+	cfg.Discord.Token = DefaultToken
+	cfg.Discord.Tokens = []string{DefaultToken}
+	if cfg.Discord.RetryDelay == 0 || cfg.Discord.RetryDelay > 10*time.Millisecond {
+		cfg.Discord.RetryDelay = 10 * time.Millisecond
+	}
+	if cfg.Discord.MaxRetryDelay == 0 || cfg.Discord.MaxRetryDelay > 100*time.Millisecond {
+		cfg.Discord.MaxRetryDelay = 100 * time.Millisecond
+	}
+}
+
+func (s *Server) AddToken(token string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.tokens[token] = true
+}
+
+func (s *Server) RevokeToken(token string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	delete(s.tokens, token)
+}
+
+// SetRateLimit changes the per-token, per-route bucket size and resets all
+// buckets.
+func (s *Server) SetRateLimit(limit RateLimit) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.limit = limit
+	s.buckets = make(map[string]*bucket)
+}
+
+func (s *Server) SetGlobalRateLimit(limit RateLimit) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.global = limit
+	s.buckets = make(map[string]*bucket)
+}
+
+func (s *Server) InjectFault(fault Fault) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.faults = append(s.faults, &fault)
+}
+
+func (s *Server) ClearFaults() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.faults = nil
+}
+
+func (s *Server) Requests() []Request {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+func (s *Server) RequestCount() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return len(s.requests)
+}
+
+func (s *Server) ResetRequests() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.requests = nil
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	r.URL.Path = versionPrefix.ReplaceAllString(r.URL.Path, "")
+	recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+	defer s.record(r, recorder)
+
+	token := r.Header.Get("Authorization")
+	s.mutex.Lock()
+	authorized := s.tokens[token]
+	fault := s.matchFault(r)
+	s.mutex.Unlock()
+
+	if !authorized {
+		writeError(recorder, http.StatusUnauthorized, 0, "401: Unauthorized")
+		return
+	}
+
+	if fault != nil {
+		if fault.Latency > 0 {
+			select {
+			case <-time.After(fault.Latency):
+			case <-r.Context().Done():
+				return
+			}
+		}
+		if fault.Status == http.StatusTooManyRequests {
+			writeRateLimited(recorder, fault.RetryAfter, false, "shared")
+			return
+		}
+		if fault.Status != 0 {
+			writeError(recorder, fault.Status, fault.Code, http.StatusText(fault.Status))
+			return
+		}
+		if fault.Malformed {
+			recorder.Header().Set("Content-Type", "application/json")
+			recorder.Write([]byte(`{"id": "1", "name": "trunc`))
+			return
+		}
+	}
+
+	_, pattern := s.mux.Handler(r)
+	if !s.takeRateLimit(recorder, token, pattern, r.URL.Path) {
+		return
+	}
+
+	s.mux.ServeHTTP(recorder, r)
+}
+
+// matchFault must be called with the mutex held.
+func (s *Server) matchFault(r *http.Request) *Fault {
+	for i, fault := range s.faults {
+		if fault.Method != "" && fault.Method != r.Method {
+			continue
+		}
+		if fault.Path != "" && !strings.HasPrefix(r.URL.Path, fault.Path) {
+			continue
+		}
+		fault.hits++
+		if fault.Count > 0 && fault.hits >= fault.Count {
+			s.faults = append(s.faults[:i:i], s.faults[i+1:]...)
+		}
+		copied := *fault
+		return &copied
+	}
+	return nil
+}
+
+func (s *Server) takeRateLimit(w http.ResponseWriter, token, pattern, path string) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now()
+
+	if s.global.Limit > 0 {
+		global := s.bucket("global:"+token, "global", s.global, now)
+		if global.remaining <= 0 {
+			writeRateLimited(w, global.resetAt.Sub(now), true, "global")
+			return false
+		}
+		global.remaining--
+	}
+
+	if s.limit.Limit <= 0 {
+		return true
+	}
+
+	route := s.bucket(token+":"+pattern+":"+majorParameter(path), pattern, s.limit, now)
+	resetAfter := route.resetAt.Sub(now).Seconds()
+
+	header := w.Header()
+	header.Set("X-RateLimit-Limit", strconv.Itoa(s.limit.Limit))
+	header.Set("X-RateLimit-Bucket", route.hash)
+	header.Set("X-RateLimit-Reset", strconv.FormatFloat(float64(route.resetAt.UnixMilli())/1000, 'f', 3, 64))
+	header.Set("X-RateLimit-Reset-After", strconv.FormatFloat(resetAfter, 'f', 3, 64))
+
+	if route.remaining <= 0 {
+		header.Set("X-RateLimit-Remaining", "0")
+		writeRateLimited(w, route.resetAt.Sub(now), false, "user")
+		return false
+	}
+
+	route.remaining--
+	header.Set("X-RateLimit-Remaining", strconv.Itoa(route.remaining))
+	return true
+}
+
+// bucket must be called with the mutex held. Like Discord, the bucket hash
+// only identifies the route; tokens and major parameters get separate
+// counters under the same hash.
+func (s *Server) bucket(key, route string, limit RateLimit, now time.Time) *bucket {
+	b, exists := s.buckets[key]
+	if !exists {
+		sum := sha1.Sum([]byte(route))
+		b = &bucket{hash: hex.EncodeToString(sum[:8])}
+		s.buckets[key] = b
+	}
+	if !now.Before(b.resetAt) {
+		b.remaining = limit.Limit
+		b.resetAt = now.Add(limit.Window)
+	}
+	return b
+}
+
+func (s *Server) record(r *http.Request, recorder *statusRecorder) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.requests = append(s.requests, Request{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Query:  r.URL.RawQuery,
+		Status: recorder.status,
+		Time:   time.Now(),
+	})
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func majorParameter(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	if len(segments) >= 2 && (segments[0] == "guilds" || segments[0] == "channels") {
+		return segments[1]
+	}
+	return ""
+}
+
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(value)
+}
+
+func writeError(w http.ResponseWriter, status, code int, message string) {
+	writeJSON(w, status, map[string]interface{}{
+		"message": message,
+		"code":    code,
+	})
+}
+
+func writeRateLimited(w http.ResponseWriter, retryAfter time.Duration, global bool, scope string) {
+	if retryAfter <= 0 {
+		retryAfter = 100 * time.Millisecond
+	}
+
+	header := w.Header()
+	header.Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+	header.Set("X-RateLimit-Scope", scope)
+	if global {
+		header.Set("X-RateLimit-Global", "true")
+	}
+
+	writeJSON(w, http.StatusTooManyRequests, map[string]interface{}{
+		"message":     "You are being rate limited.",
+		"retry_after": retryAfter.Seconds(),
+		"global":      global,
+		"code":        0,
+	})
+}
+
+func unknown(w http.ResponseWriter, code int, resource string) {
+	writeError(w, http.StatusNotFound, code, fmt.Sprintf("Unknown %s", resource))
+}
@@ -0,0 +1,599 @@
+package gateway
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"discord-user-api/discord"
+	"discord-user-api/models"
+	"discord-user-api/snowflake"
+)
+
+type GuildDeleteEvent struct {
+	ID          snowflake.Snowflake `json:"id"`
+	Unavailable bool                `json:"unavailable"`
+}
+
+type GuildRoleEvent struct {
+	GuildID snowflake.Snowflake `json:"guild_id"`
+	Role    models.DiscordRole  `json:"role"`
+}
+
+type GuildRoleDeleteEvent struct {
+	GuildID snowflake.Snowflake `json:"guild_id"`
+	RoleID  snowflake.Snowflake `json:"role_id"`
+}
+
+type GuildEmojisUpdateEvent struct {
+	GuildID snowflake.Snowflake   `json:"guild_id"`
+	Emojis  []models.DiscordEmoji `json:"emojis"`
+}
+
+type GuildStickersUpdateEvent struct {
+	GuildID  snowflake.Snowflake     `json:"guild_id"`
+	Stickers []models.DiscordSticker `json:"stickers"`
+}
+
+type GuildBanEvent struct {
+	GuildID snowflake.Snowflake `json:"guild_id"`
+	User    models.DiscordUser  `json:"user"`
+}
+
+type InviteEvent struct {
+	GuildID   snowflake.Snowflake `json:"guild_id"`
+	ChannelID snowflake.Snowflake `json:"channel_id"`
+	Code      string              `json:"code"`
+}
+
+type GuildMemberEvent struct {
+	GuildID snowflake.Snowflake `json:"guild_id"`
+	models.DiscordGuildMember
+}
+
+type GuildMemberRemoveEvent struct {
+	GuildID snowflake.Snowflake `json:"guild_id"`
+	User    models.DiscordUser  `json:"user"`
+}
+
+func (c *Client) dispatch(eventType string, data json.RawMessage) {
+	c.dispatched.Add(1)
+	c.mutex.Lock()
+	c.events[eventType]++
+	c.mutex.Unlock()
+
+	var err error
+	switch eventType {
+	case "GUILD_CREATE", "GUILD_UPDATE":
+		err = c.handleGuildUpdate(eventType, data)
+	case "GUILD_DELETE":
+		err = c.handleGuildDelete(data)
+	case "GUILD_ROLE_CREATE", "GUILD_ROLE_UPDATE":
+		err = c.handleGuildRole(eventType, data)
+	case "GUILD_ROLE_DELETE":
+		err = c.handleGuildRoleDelete(data)
+	case "GUILD_EMOJIS_UPDATE":
+		err = c.handleGuildEmojis(data)
+	case "GUILD_STICKERS_UPDATE":
+		err = c.handleGuildStickers(data)
+	case "GUILD_BAN_ADD", "GUILD_BAN_REMOVE":
+		err = c.handleGuildBan(eventType, data)
+	case "INVITE_CREATE", "INVITE_DELETE":
+		err = c.handleInvite(eventType, data)
+	case "GUILD_MEMBER_ADD", "GUILD_MEMBER_UPDATE":
+		err = c.handleGuildMember(eventType, data)
+	case "GUILD_MEMBER_REMOVE":
+		err = c.handleGuildMemberRemove(data)
+	case "CHANNEL_CREATE", "CHANNEL_UPDATE":
+		err = c.handleChannel(eventType, data)
+	case "CHANNEL_DELETE":
+		err = c.handleChannelDelete(data)
+	case "USER_UPDATE":
+		err = c.handleUserUpdate(data)
+	}
+
+	if err != nil {
+		log.Printf("❌ Gateway olayı işlenemedi (%s): %v", eventType, err)
+	}
+}
+
+func (c *Client) handleGuildUpdate(eventType string, data json.RawMessage) error {
+	var guild models.DiscordGuild
+	if err := json.Unmarshal(data, &guild); err != nil {
+		return err
+	}
+
+	c.updateCache(fmt.Sprintf("guild_%s", guild.ID), func(value interface{}) (interface{}, bool) {
+		cached, ok := value.(*models.DiscordGuild)
+		if !ok {
+			return nil, false
+		}
+		var merged models.DiscordGuild
+		if err := merge(&merged, cached, data); err != nil {
+			return nil, false
+		}
+		return &merged, true
+	})
+
+	// Every token's guild list may contain the guild, but only the gateway's
+	// own account has just joined it.
+	ownKey := c.accountKey("guilds")
+	for _, key := range c.cacheKeys("guilds_") {
+		c.updateCache(key, func(value interface{}) (interface{}, bool) {
+			cached, ok := value.([]models.DiscordGuild)
+			if !ok {
+				return nil, false
+			}
+			guilds := make([]models.DiscordGuild, 0, len(cached)+1)
+			found := false
+			for _, existing := range cached {
+				if existing.ID == guild.ID {
+					var merged models.DiscordGuild
+					if err := merge(&merged, existing, data); err != nil {
+						return nil, false
+					}
+					existing = merged
+					found = true
+				}
+				guilds = append(guilds, existing)
+			}
+			if !found {
+				if eventType != "GUILD_CREATE" || key != ownKey {
+					return nil, false
+				}
+				guilds = append(guilds, guild)
+			}
+			return guilds, true
+		})
+	}
+
+	c.broadcast(guild.ID, eventType, &guild)
+	return nil
+}
+
+func (c *Client) handleGuildDelete(data json.RawMessage) error {
+	var event GuildDeleteEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		return err
+	}
+
+	if !event.Unavailable && c.config.Cache.Enabled {
+		c.cache.Delete(fmt.Sprintf("guild_%s", event.ID))
+		c.deletePrefix(fmt.Sprintf("guild_members_%s_", event.ID))
+		c.deletePrefix(fmt.Sprintf("guild_member_%s_", event.ID))
+		c.cache.Delete(fmt.Sprintf("guild_members_all_%s", event.ID))
+		c.cache.Delete(fmt.Sprintf("guild_channels_%s", event.ID))
+		c.cache.Delete(fmt.Sprintf("guild_roles_%s", event.ID))
+		c.cache.Delete(fmt.Sprintf("guild_emojis_%s", event.ID))
+		c.cache.Delete(fmt.Sprintf("guild_stickers_%s", event.ID))
+		c.cache.Delete(fmt.Sprintf("guild_invites_%s", event.ID))
+		c.deletePrefix(fmt.Sprintf("guild_bans_%s_", event.ID))
+
+		c.updateCache(c.accountKey("guilds"), func(value interface{}) (interface{}, bool) {
+			cached, ok := value.([]models.DiscordGuild)
+			if !ok {
+				return nil, false
+			}
+			guilds := make([]models.DiscordGuild, 0, len(cached))
+			for _, existing := range cached {
+				if existing.ID != event.ID {
+					guilds = append(guilds, existing)
+				}
+			}
+			return guilds, len(guilds) != len(cached)
+		})
+	}
+
+	c.broadcast(event.ID, "GUILD_DELETE", event)
+	return nil
+}
+
+func (c *Client) handleGuildRole(eventType string, data json.RawMessage) error {
+	var event GuildRoleEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		return err
+	}
+
+	c.updateGuild(event.GuildID, func(guild *models.DiscordGuild) {
+		guild.Roles = upsertRole(guild.Roles, event.Role)
+	})
+
+	c.updateCache(fmt.Sprintf("guild_roles_%s", event.GuildID), func(value interface{}) (interface{}, bool) {
+		cached, ok := value.([]models.DiscordRole)
+		if !ok {
+			return nil, false
+		}
+		return upsertRole(cached, event.Role), true
+	})
+
+	c.broadcast(event.GuildID, eventType, event)
+	return nil
+}
+
+func (c *Client) handleGuildRoleDelete(data json.RawMessage) error {
+	var event GuildRoleDeleteEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		return err
+	}
+
+	c.updateGuild(event.GuildID, func(guild *models.DiscordGuild) {
+		guild.Roles = removeRole(guild.Roles, event.RoleID)
+	})
+
+	c.updateCache(fmt.Sprintf("guild_roles_%s", event.GuildID), func(value interface{}) (interface{}, bool) {
+		cached, ok := value.([]models.DiscordRole)
+		if !ok {
+			return nil, false
+		}
+		roles := removeRole(cached, event.RoleID)
+		return roles, len(roles) != len(cached)
+	})
+
+	c.broadcast(event.GuildID, "GUILD_ROLE_DELETE", event)
+	return nil
+}
+
+func (c *Client) handleGuildEmojis(data json.RawMessage) error {
+	var event GuildEmojisUpdateEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		return err
+	}
+
+	c.updateGuild(event.GuildID, func(guild *models.DiscordGuild) {
+		guild.Emojis = event.Emojis
+	})
+
+	c.updateCache(fmt.Sprintf("guild_emojis_%s", event.GuildID), func(value interface{}) (interface{}, bool) {
+		if _, ok := value.([]models.DiscordEmoji); !ok {
+			return nil, false
+		}
+		return event.Emojis, true
+	})
+
+	c.broadcast(event.GuildID, "GUILD_EMOJIS_UPDATE", event)
+	return nil
+}
+
+func (c *Client) handleGuildStickers(data json.RawMessage) error {
+	var event GuildStickersUpdateEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		return err
+	}
+
+	c.updateGuild(event.GuildID, func(guild *models.DiscordGuild) {
+		guild.Stickers = event.Stickers
+	})
+
+	c.updateCache(fmt.Sprintf("guild_stickers_%s", event.GuildID), func(value interface{}) (interface{}, bool) {
+		if _, ok := value.([]models.DiscordSticker); !ok {
+			return nil, false
+		}
+		return event.Stickers, true
+	})
+
+	c.broadcast(event.GuildID, "GUILD_STICKERS_UPDATE", event)
+	return nil
+}
+
+// Ban and invite lists are paginated or carry usage counters the gateway
+// does not send, so cached pages are dropped instead of patched.
+func (c *Client) handleGuildBan(eventType string, data json.RawMessage) error {
+	var event GuildBanEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		return err
+	}
+
+	if c.config.Cache.Enabled {
+		c.deletePrefix(fmt.Sprintf("guild_bans_%s_", event.GuildID))
+	}
+
+	c.broadcast(event.GuildID, eventType, event)
+	return nil
+}
+
+func (c *Client) handleInvite(eventType string, data json.RawMessage) error {
+	var event InviteEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		return err
+	}
+
+	if c.config.Cache.Enabled && event.GuildID.IsValid() {
+		c.cache.Delete(fmt.Sprintf("guild_invites_%s", event.GuildID))
+	}
+
+	c.broadcast(event.GuildID, eventType, event)
+	return nil
+}
+
+func (c *Client) handleGuildMember(eventType string, data json.RawMessage) error {
+	var event GuildMemberEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		return err
+	}
+
+	userID := event.User.ID
+	mergeMember := func(existing models.DiscordGuildMember) (models.DiscordGuildMember, bool) {
+		var merged models.DiscordGuildMember
+		if err := merge(&merged, existing, data); err != nil {
+			return existing, false
+		}
+		return merged, true
+	}
+
+	c.updateCache(fmt.Sprintf("guild_member_%s_%s", event.GuildID, userID), func(value interface{}) (interface{}, bool) {
+		cached, ok := value.(*models.DiscordGuildMember)
+		if !ok {
+			return nil, false
+		}
+		merged, ok := mergeMember(*cached)
+		return &merged, ok
+	})
+
+	keys := []string{fmt.Sprintf("guild_members_all_%s", event.GuildID)}
+	if c.config.Cache.Enabled {
+		keys = append(keys, c.cache.Keys(fmt.Sprintf("guild_members_%s_", event.GuildID))...)
+	}
+
+	for _, key := range keys {
+		appendMissing := eventType == "GUILD_MEMBER_ADD" && strings.HasPrefix(key, "guild_members_all_")
+		c.updateCache(key, func(value interface{}) (interface{}, bool) {
+			cached, ok := value.([]models.DiscordGuildMember)
+			if !ok {
+				return nil, false
+			}
+			members := make([]models.DiscordGuildMember, 0, len(cached)+1)
+			found := false
+			for _, member := range cached {
+				if member.User.ID == userID {
+					merged, ok := mergeMember(member)
+					if !ok {
+						return nil, false
+					}
+					member = merged
+					found = true
+				}
+				members = append(members, member)
+			}
+			if !found {
+				if !appendMissing {
+					return nil, false
+				}
+				members = append(members, event.DiscordGuildMember)
+			}
+			return members, true
+		})
+	}
+
+	c.broadcast(event.GuildID, eventType, event)
+	return nil
+}
+
+func (c *Client) handleGuildMemberRemove(data json.RawMessage) error {
+	var event GuildMemberRemoveEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		return err
+	}
+
+	if c.config.Cache.Enabled {
+		c.cache.Delete(fmt.Sprintf("guild_member_%s_%s", event.GuildID, event.User.ID))
+
+		keys := append(c.cache.Keys(fmt.Sprintf("guild_members_%s_", event.GuildID)), fmt.Sprintf("guild_members_all_%s", event.GuildID))
+		for _, key := range keys {
+			c.updateCache(key, func(value interface{}) (interface{}, bool) {
+				cached, ok := value.([]models.DiscordGuildMember)
+				if !ok {
+					return nil, false
+				}
+				members := make([]models.DiscordGuildMember, 0, len(cached))
+				for _, member := range cached {
+					if member.User.ID != event.User.ID {
+						members = append(members, member)
+					}
+				}
+				return members, len(members) != len(cached)
+			})
+		}
+	}
+
+	c.broadcast(event.GuildID, "GUILD_MEMBER_REMOVE", event)
+	return nil
+}
+
+func (c *Client) handleChannel(eventType string, data json.RawMessage) error {
+	var channel models.DiscordChannel
+	if err := json.Unmarshal(data, &channel); err != nil {
+		return err
+	}
+
+	c.updateCache(fmt.Sprintf("channel_%s", channel.ID), func(value interface{}) (interface{}, bool) {
+		cached, ok := value.(*models.DiscordChannel)
+		if !ok {
+			return nil, false
+		}
+		var merged models.DiscordChannel
+		if err := merge(&merged, cached, data); err != nil {
+			return nil, false
+		}
+		return &merged, true
+	})
+
+	if channel.GuildID.IsValid() {
+		c.updateCache(fmt.Sprintf("guild_channels_%s", channel.GuildID), func(value interface{}) (interface{}, bool) {
+			cached, ok := value.([]models.DiscordChannel)
+			if !ok {
+				return nil, false
+			}
+			channels := make([]models.DiscordChannel, 0, len(cached)+1)
+			found := false
+			for _, existing := range cached {
+				if existing.ID == channel.ID {
+					existing = channel
+					found = true
+				}
+				channels = append(channels, existing)
+			}
+			if !found {
+				channels = append(channels, channel)
+			}
+			return channels, true
+		})
+	}
+
+	c.broadcast(channel.GuildID, eventType, &channel)
+	return nil
+}
+
+func (c *Client) handleChannelDelete(data json.RawMessage) error {
+	var channel models.DiscordChannel
+	if err := json.Unmarshal(data, &channel); err != nil {
+		return err
+	}
+
+	if c.config.Cache.Enabled {
+		c.cache.Delete(fmt.Sprintf("channel_%s", channel.ID))
+	}
+
+	if channel.GuildID.IsValid() {
+		c.updateCache(fmt.Sprintf("guild_channels_%s", channel.GuildID), func(value interface{}) (interface{}, bool) {
+			cached, ok := value.([]models.DiscordChannel)
+			if !ok {
+				return nil, false
+			}
+			channels := make([]models.DiscordChannel, 0, len(cached))
+			for _, existing := range cached {
+				if existing.ID != channel.ID {
+					channels = append(channels, existing)
+				}
+			}
+			return channels, len(channels) != len(cached)
+		})
+	}
+
+	c.broadcast(channel.GuildID, "CHANNEL_DELETE", &channel)
+	return nil
+}
+
+func (c *Client) handleUserUpdate(data json.RawMessage) error {
+	var user models.DiscordUser
+	if err := json.Unmarshal(data, &user); err != nil {
+		return err
+	}
+
+	update := func(value interface{}) (interface{}, bool) {
+		cached, ok := value.(*models.DiscordProfile)
+		if !ok {
+			return nil, false
+		}
+		var merged models.DiscordProfile
+		if err := merge(&merged, cached, nil); err != nil {
+			return nil, false
+		}
+		if err := merge(&merged.User, cached.User, data); err != nil {
+			return nil, false
+		}
+		return &merged, true
+	}
+
+	// USER_UPDATE is about the gateway's own account, which is also @me for
+	// its token.
+	for _, key := range c.cacheKeys(fmt.Sprintf("user_%s_", user.ID)) {
+		c.updateCache(key, update)
+	}
+	c.updateCache(c.accountKey("user_@me"), update)
+
+	if c.wsManager != nil {
+		c.wsManager.BroadcastToUser(user.ID.String(), "user_update", &user)
+	}
+	return nil
+}
+
+func (c *Client) updateGuild(guildID snowflake.Snowflake, fn func(guild *models.DiscordGuild)) {
+	c.updateCache(fmt.Sprintf("guild_%s", guildID), func(value interface{}) (interface{}, bool) {
+		cached, ok := value.(*models.DiscordGuild)
+		if !ok {
+			return nil, false
+		}
+		guild := *cached
+		fn(&guild)
+		return &guild, true
+	})
+}
+
+func upsertRole(roles []models.DiscordRole, updated models.DiscordRole) []models.DiscordRole {
+	result := make([]models.DiscordRole, 0, len(roles)+1)
+	found := false
+	for _, role := range roles {
+		if role.ID == updated.ID {
+			role = updated
+			found = true
+		}
+		result = append(result, role)
+	}
+	if !found {
+		result = append(result, updated)
+	}
+	return result
+}
+
+func removeRole(roles []models.DiscordRole, roleID snowflake.Snowflake) []models.DiscordRole {
+	result := make([]models.DiscordRole, 0, len(roles))
+	for _, role := range roles {
+		if role.ID != roleID {
+			result = append(result, role)
+		}
+	}
+	return result
+}
+
+func (c *Client) updateCache(key string, fn func(value interface{}) (interface{}, bool)) {
+	if !c.config.Cache.Enabled || c.cache == nil {
+		return
+	}
+	c.cache.Update(key, fn)
+}
+
+func (c *Client) cacheKeys(prefix string) []string {
+	if !c.config.Cache.Enabled || c.cache == nil {
+		return nil
+	}
+	return c.cache.Keys(prefix)
+}
+
+// accountKey is the cache key the REST client uses for data seen by the
+// gateway's own token.
+func (c *Client) accountKey(base string) string {
+	return discord.AccountCacheKey(base, discord.TokenName(0))
+}
+
+func (c *Client) deletePrefix(prefix string) {
+	for _, key := range c.cache.Keys(prefix) {
+		c.cache.Delete(key)
+	}
+}
+
+// broadcast skips events without a guild, such as DM channel updates, since
+// there is no guild room for them.
+func (c *Client) broadcast(guildID snowflake.Snowflake, eventType string, data interface{}) {
+	if c.wsManager == nil || !guildID.IsValid() {
+		return
+	}
+	c.wsManager.BroadcastToGuild(guildID.String(), strings.ToLower(eventType), data)
+}
+
+// merge deep-copies base into dst through JSON and then applies the partial
+// gateway payload on top, so fields missing from the event keep their cached
+// values and the cached value itself is never mutated.
+func merge(dst interface{}, base interface{}, patch json.RawMessage) error {
+	raw, err := json.Marshal(base)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(raw, dst); err != nil {
+		return err
+	}
+	if len(patch) == 0 {
+		return nil
+	}
+	return json.Unmarshal(patch, dst)
+}
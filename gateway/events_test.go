@@ -0,0 +1,285 @@
+package gateway
+
+import (
+	"testing"
+	"time"
+
+	"discord-user-api/cache"
+	"discord-user-api/config"
+	"discord-user-api/models"
+)
+
+func TestDispatchUpdatesCache(t *testing.T) {
+	tests := []struct {
+		name   string
+		cached map[string]interface{}
+		event  string
+		data   string
+		check  func(t *testing.T, store *cache.Cache)
+	}{
+		{
+			name: "GUILD_UPDATE merges guild and every token's list",
+			cached: map[string]interface{}{
+				"guild_1":        &models.DiscordGuild{ID: 1, Name: "eski", Icon: "ikon"},
+				"guilds_token_1": []models.DiscordGuild{{ID: 1, Name: "eski"}},
+				"guilds_token_2": []models.DiscordGuild{{ID: 2, Name: "diğer"}, {ID: 1, Name: "eski"}},
+			},
+			event: "GUILD_UPDATE",
+			data:  `{"id":"1","name":"yeni"}`,
+			check: func(t *testing.T, store *cache.Cache) {
+				guild := get(t, store, "guild_1").(*models.DiscordGuild)
+				if guild.Name != "yeni" || guild.Icon != "ikon" {
+					t.Errorf("guild = %+v", guild)
+				}
+				for _, key := range []string{"guilds_token_1", "guilds_token_2"} {
+					for _, g := range get(t, store, key).([]models.DiscordGuild) {
+						if g.ID == 1 && g.Name != "yeni" {
+							t.Errorf("%s: %+v", key, g)
+						}
+					}
+				}
+			},
+		},
+		{
+			name: "GUILD_CREATE joins only the gateway account's list",
+			cached: map[string]interface{}{
+				"guilds_token_1": []models.DiscordGuild{{ID: 1}},
+				"guilds_token_2": []models.DiscordGuild{{ID: 1}},
+			},
+			event: "GUILD_CREATE",
+			data:  `{"id":"3","name":"katıldı"}`,
+			check: func(t *testing.T, store *cache.Cache) {
+				if n := len(get(t, store, "guilds_token_1").([]models.DiscordGuild)); n != 2 {
+					t.Errorf("guilds_token_1 = %d guild, want 2", n)
+				}
+				if n := len(get(t, store, "guilds_token_2").([]models.DiscordGuild)); n != 1 {
+					t.Errorf("guilds_token_2 = %d guild, want 1", n)
+				}
+			},
+		},
+		{
+			name: "GUILD_DELETE drops guild caches",
+			cached: map[string]interface{}{
+				"guild_1":             &models.DiscordGuild{ID: 1},
+				"guild_roles_1":       []models.DiscordRole{{ID: 10}},
+				"guild_members_1_100": []models.DiscordGuildMember{{User: models.DiscordUser{ID: 5}}},
+				"guilds_token_1":      []models.DiscordGuild{{ID: 1}, {ID: 2}},
+			},
+			event: "GUILD_DELETE",
+			data:  `{"id":"1"}`,
+			check: func(t *testing.T, store *cache.Cache) {
+				missing(t, store, "guild_1", "guild_roles_1", "guild_members_1_100")
+				if guilds := get(t, store, "guilds_token_1").([]models.DiscordGuild); len(guilds) != 1 || guilds[0].ID != 2 {
+					t.Errorf("guilds_token_1 = %+v", guilds)
+				}
+			},
+		},
+		{
+			name:   "GUILD_DELETE for an outage keeps the cache",
+			cached: map[string]interface{}{"guild_1": &models.DiscordGuild{ID: 1}},
+			event:  "GUILD_DELETE",
+			data:   `{"id":"1","unavailable":true}`,
+			check: func(t *testing.T, store *cache.Cache) {
+				get(t, store, "guild_1")
+			},
+		},
+		{
+			name: "GUILD_ROLE_CREATE upserts into guild and role list",
+			cached: map[string]interface{}{
+				"guild_1":       &models.DiscordGuild{ID: 1, Roles: []models.DiscordRole{{ID: 10, Name: "a"}}},
+				"guild_roles_1": []models.DiscordRole{{ID: 10, Name: "a"}},
+			},
+			event: "GUILD_ROLE_CREATE",
+			data:  `{"guild_id":"1","role":{"id":"11","name":"b"}}`,
+			check: func(t *testing.T, store *cache.Cache) {
+				if n := len(get(t, store, "guild_1").(*models.DiscordGuild).Roles); n != 2 {
+					t.Errorf("guild roles = %d, want 2", n)
+				}
+				if n := len(get(t, store, "guild_roles_1").([]models.DiscordRole)); n != 2 {
+					t.Errorf("guild_roles_1 = %d, want 2", n)
+				}
+			},
+		},
+		{
+			name: "GUILD_ROLE_DELETE removes the role",
+			cached: map[string]interface{}{
+				"guild_1":       &models.DiscordGuild{ID: 1, Roles: []models.DiscordRole{{ID: 10}}},
+				"guild_roles_1": []models.DiscordRole{{ID: 10}},
+			},
+			event: "GUILD_ROLE_DELETE",
+			data:  `{"guild_id":"1","role_id":"10"}`,
+			check: func(t *testing.T, store *cache.Cache) {
+				if n := len(get(t, store, "guild_1").(*models.DiscordGuild).Roles); n != 0 {
+					t.Errorf("guild roles = %d, want 0", n)
+				}
+				if n := len(get(t, store, "guild_roles_1").([]models.DiscordRole)); n != 0 {
+					t.Errorf("guild_roles_1 = %d, want 0", n)
+				}
+			},
+		},
+		{
+			name: "GUILD_EMOJIS_UPDATE replaces emojis",
+			cached: map[string]interface{}{
+				"guild_emojis_1": []models.DiscordEmoji{{ID: 20, Name: "eski"}},
+			},
+			event: "GUILD_EMOJIS_UPDATE",
+			data:  `{"guild_id":"1","emojis":[{"id":"21","name":"yeni"},{"id":"22","name":"diğer"}]}`,
+			check: func(t *testing.T, store *cache.Cache) {
+				if emojis := get(t, store, "guild_emojis_1").([]models.DiscordEmoji); len(emojis) != 2 || emojis[0].Name != "yeni" {
+					t.Errorf("emojis = %+v", emojis)
+				}
+			},
+		},
+		{
+			name: "GUILD_MEMBER_ADD appends to the full list, not to pages",
+			cached: map[string]interface{}{
+				"guild_members_all_1": []models.DiscordGuildMember{{User: models.DiscordUser{ID: 5}}},
+				"guild_members_1_100": []models.DiscordGuildMember{{User: models.DiscordUser{ID: 5}}},
+			},
+			event: "GUILD_MEMBER_ADD",
+			data:  `{"guild_id":"1","user":{"id":"6","username":"yeni"}}`,
+			check: func(t *testing.T, store *cache.Cache) {
+				if n := len(get(t, store, "guild_members_all_1").([]models.DiscordGuildMember)); n != 2 {
+					t.Errorf("guild_members_all_1 = %d, want 2", n)
+				}
+				if n := len(get(t, store, "guild_members_1_100").([]models.DiscordGuildMember)); n != 1 {
+					t.Errorf("guild_members_1_100 = %d, want 1", n)
+				}
+			},
+		},
+		{
+			name: "GUILD_MEMBER_UPDATE merges partial fields",
+			cached: map[string]interface{}{
+				"guild_member_1_5":    &models.DiscordGuildMember{User: models.DiscordUser{ID: 5}, Nick: "eski", JoinedAt: "2024-01-01"},
+				"guild_members_1_100": []models.DiscordGuildMember{{User: models.DiscordUser{ID: 5}, Nick: "eski"}},
+			},
+			event: "GUILD_MEMBER_UPDATE",
+			data:  `{"guild_id":"1","user":{"id":"5"},"nick":"yeni"}`,
+			check: func(t *testing.T, store *cache.Cache) {
+				member := get(t, store, "guild_member_1_5").(*models.DiscordGuildMember)
+				if member.Nick != "yeni" || member.JoinedAt != "2024-01-01" {
+					t.Errorf("member = %+v", member)
+				}
+				if page := get(t, store, "guild_members_1_100").([]models.DiscordGuildMember); page[0].Nick != "yeni" {
+					t.Errorf("page = %+v", page)
+				}
+			},
+		},
+		{
+			name: "GUILD_MEMBER_REMOVE drops the member",
+			cached: map[string]interface{}{
+				"guild_member_1_5":    &models.DiscordGuildMember{User: models.DiscordUser{ID: 5}},
+				"guild_members_all_1": []models.DiscordGuildMember{{User: models.DiscordUser{ID: 5}}, {User: models.DiscordUser{ID: 6}}},
+			},
+			event: "GUILD_MEMBER_REMOVE",
+			data:  `{"guild_id":"1","user":{"id":"5"}}`,
+			check: func(t *testing.T, store *cache.Cache) {
+				missing(t, store, "guild_member_1_5")
+				if n := len(get(t, store, "guild_members_all_1").([]models.DiscordGuildMember)); n != 1 {
+					t.Errorf("guild_members_all_1 = %d, want 1", n)
+				}
+			},
+		},
+		{
+			name: "GUILD_BAN_ADD drops cached ban pages",
+			cached: map[string]interface{}{
+				"guild_bans_1_100": []models.DiscordBan{},
+			},
+			event: "GUILD_BAN_ADD",
+			data:  `{"guild_id":"1","user":{"id":"5"}}`,
+			check: func(t *testing.T, store *cache.Cache) {
+				missing(t, store, "guild_bans_1_100")
+			},
+		},
+		{
+			name: "CHANNEL_UPDATE merges channel and guild list",
+			cached: map[string]interface{}{
+				"channel_10":       &models.DiscordChannel{ID: 10, GuildID: 1, Name: "eski", Topic: "konu"},
+				"guild_channels_1": []models.DiscordChannel{{ID: 10, GuildID: 1, Name: "eski"}},
+			},
+			event: "CHANNEL_UPDATE",
+			data:  `{"id":"10","guild_id":"1","name":"yeni"}`,
+			check: func(t *testing.T, store *cache.Cache) {
+				channel := get(t, store, "channel_10").(*models.DiscordChannel)
+				if channel.Name != "yeni" || channel.Topic != "konu" {
+					t.Errorf("channel = %+v", channel)
+				}
+				if channels := get(t, store, "guild_channels_1").([]models.DiscordChannel); len(channels) != 1 || channels[0].Name != "yeni" {
+					t.Errorf("guild_channels_1 = %+v", channels)
+				}
+			},
+		},
+		{
+			name: "CHANNEL_DELETE drops the channel",
+			cached: map[string]interface{}{
+				"channel_10":       &models.DiscordChannel{ID: 10, GuildID: 1},
+				"guild_channels_1": []models.DiscordChannel{{ID: 10, GuildID: 1}, {ID: 11, GuildID: 1}},
+			},
+			event: "CHANNEL_DELETE",
+			data:  `{"id":"10","guild_id":"1"}`,
+			check: func(t *testing.T, store *cache.Cache) {
+				missing(t, store, "channel_10")
+				if n := len(get(t, store, "guild_channels_1").([]models.DiscordChannel)); n != 1 {
+					t.Errorf("guild_channels_1 = %d, want 1", n)
+				}
+			},
+		},
+		{
+			name: "USER_UPDATE patches profiles and @me",
+			cached: map[string]interface{}{
+				"user_5_token_1":   &models.DiscordProfile{User: models.DiscordUser{ID: 5, Username: "eski", Avatar: "a"}},
+				"user_@me_token_1": &models.DiscordProfile{User: models.DiscordUser{ID: 5, Username: "eski"}},
+			},
+			event: "USER_UPDATE",
+			data:  `{"id":"5","username":"yeni"}`,
+			check: func(t *testing.T, store *cache.Cache) {
+				profile := get(t, store, "user_5_token_1").(*models.DiscordProfile)
+				if profile.User.Username != "yeni" || profile.User.Avatar != "a" {
+					t.Errorf("profile = %+v", profile.User)
+				}
+				if me := get(t, store, "user_@me_token_1").(*models.DiscordProfile); me.User.Username != "yeni" {
+					t.Errorf("@me = %+v", me.User)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{}
+			cfg.Cache.Enabled = true
+			store := cache.NewCache(100, time.Minute, time.Minute)
+			defer store.Stop()
+
+			for key, value := range tt.cached {
+				store.Set(key, value)
+			}
+
+			client := NewClient(cfg, store, nil)
+			client.dispatch(tt.event, []byte(tt.data))
+
+			tt.check(t, store)
+			if got := client.Stats().Events[tt.event]; got != 1 {
+				t.Errorf("events[%s] = %d, want 1", tt.event, got)
+			}
+		})
+	}
+}
+
+func get(t *testing.T, store *cache.Cache, key string) interface{} {
+	t.Helper()
+	value, ok := store.Get(key)
+	if !ok {
+		t.Fatalf("%s cache'de yok", key)
+	}
+	return value
+}
+
+func missing(t *testing.T, store *cache.Cache, keys ...string) {
+	t.Helper()
+	for _, key := range keys {
+		if _, ok := store.Get(key); ok {
+			t.Errorf("%s cache'den silinmeliydi", key)
+		}
+	}
+}
@@ -0,0 +1,635 @@
+package gateway
+
+import (
+	"compress/zlib"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	ws "github.com/gorilla/websocket"
+
+	"discord-user-api/cache"
+	"discord-user-api/config"
+	"discord-user-api/websocket"
+)
+
+const (
+	OpDispatch            = 0
+	OpHeartbeat           = 1
+	OpIdentify            = 2
+	OpPresenceUpdate      = 3
+	OpVoiceStateUpdate    = 4
+	OpResume              = 6
+	OpReconnect           = 7
+	OpRequestGuildMembers = 8
+	OpInvalidSession      = 9
+	OpHello               = 10
+	OpHeartbeatACK        = 11
+)
+
+const (
+	StateDisconnected = "disconnected"
+	StateConnecting   = "connecting"
+	StateIdentifying  = "identifying"
+	StateResuming     = "resuming"
+	StateConnected    = "connected"
+	StateStopped      = "stopped"
+)
+
+var (
+	ErrNoToken          = errors.New("gateway için Discord token'ı yok")
+	errReconnect        = errors.New("gateway yeniden bağlanma istedi")
+	errInvalidSession   = errors.New("gateway oturumu geçersiz")
+	errHeartbeatTimeout = errors.New("heartbeat ACK alınamadı")
+)
+
+// Reconnect pacing, shortened by tests.
+var (
+	reconnectDelay      = time.Second
+	invalidSessionDelay = func() time.Duration {
+		return time.Second + time.Duration(rand.Int63n(int64(4*time.Second)))
+	}
+)
+
+type CloseError struct {
+	Code   int
+	Reason string
+}
+
+func (e *CloseError) Error() string {
+	return fmt.Sprintf("gateway bağlantısı kapandı (%d): %s", e.Code, e.Reason)
+}
+
+func (e *CloseError) Fatal() bool {
+	switch e.Code {
+	case 4004, 4010, 4011, 4012, 4013, 4014:
+		return true
+	}
+	return false
+}
+
+func (e *CloseError) Resumable() bool {
+	return e.Code != 4007 && e.Code != 4009 && !e.Fatal()
+}
+
+type Payload struct {
+	Op       int             `json:"op"`
+	Data     json.RawMessage `json:"d"`
+	Sequence *int64          `json:"s,omitempty"`
+	Type     string          `json:"t,omitempty"`
+}
+
+type helloData struct {
+	HeartbeatInterval int64 `json:"heartbeat_interval"`
+}
+
+type identifyData struct {
+	Token          string             `json:"token"`
+	Properties     identifyProperties `json:"properties"`
+	LargeThreshold int                `json:"large_threshold,omitempty"`
+	Intents        int                `json:"intents"`
+}
+
+type identifyProperties struct {
+	OS      string `json:"os"`
+	Browser string `json:"browser"`
+	Device  string `json:"device"`
+}
+
+type resumeData struct {
+	Token     string `json:"token"`
+	SessionID string `json:"session_id"`
+	Sequence  int64  `json:"seq"`
+}
+
+type readyData struct {
+	SessionID        string `json:"session_id"`
+	ResumeGatewayURL string `json:"resume_gateway_url"`
+}
+
+type Client struct {
+	config    *config.Config
+	cache     *cache.Cache
+	wsManager *websocket.WebSocketManager
+	token     string
+	dialer    *ws.Dialer
+
+	mutex         sync.RWMutex
+	state         string
+	sessionID     string
+	resumeURL     string
+	lastHeartbeat time.Time
+	lastAck       time.Time
+	latency       time.Duration
+	lastError     string
+	connectedAt   time.Time
+	events        map[string]int64
+
+	sequence   atomic.Int64
+	reconnects atomic.Int64
+	resumes    atomic.Int64
+	identifies atomic.Int64
+	dispatched atomic.Int64
+}
+
+type Stats struct {
+	State      string           `json:"state"`
+	SessionID  string           `json:"session_id,omitempty"`
+	Sequence   int64            `json:"sequence"`
+	LatencyMs  int64            `json:"latency_ms"`
+	LastAck    string           `json:"last_ack,omitempty"`
+	Connected  string           `json:"connected_at,omitempty"`
+	Reconnects int64            `json:"reconnects"`
+	Resumes    int64            `json:"resumes"`
+	Identifies int64            `json:"identifies"`
+	Dispatched int64            `json:"dispatched"`
+	LastError  string           `json:"last_error,omitempty"`
+	Events     map[string]int64 `json:"events"`
+}
+
+func NewClient(cfg *config.Config, cache *cache.Cache, wsManager *websocket.WebSocketManager) *Client {
+	var token string
+	if len(cfg.Discord.Tokens) > 0 {
+		token = cfg.Discord.Tokens[0]
+	}
+
+	return &Client{
+		config:    cfg,
+		cache:     cache,
+		wsManager: wsManager,
+		token:     token,
+		dialer: &ws.Dialer{
+			Proxy:            ws.DefaultDialer.Proxy,
+			HandshakeTimeout: cfg.Discord.Gateway.HandshakeTimeout,
+		},
+		state:  StateDisconnected,
+		events: make(map[string]int64),
+	}
+}
+
+func (c *Client) SetTransport(transport *http.Transport) {
+	if transport == nil {
+		return
+	}
+	c.dialer.Proxy = transport.Proxy
+	c.dialer.NetDialContext = transport.DialContext
+	if transport.TLSClientConfig != nil {
+		c.dialer.TLSClientConfig = transport.TLSClientConfig.Clone()
+		c.dialer.TLSClientConfig.NextProtos = nil
+	}
+}
+
+func (c *Client) Run(ctx context.Context) error {
+	if c.token == "" {
+		return ErrNoToken
+	}
+
+	log.Printf("🛰️  Discord Gateway başlatılıyor: %s", c.config.Discord.Gateway.URL)
+
+	delay := reconnectDelay
+	for {
+		established, err := c.connect(ctx)
+		if ctx.Err() != nil {
+			c.setState(StateStopped)
+			log.Printf("🛑 Discord Gateway durduruldu")
+			return ctx.Err()
+		}
+
+		c.recordError(err)
+
+		var closeErr *CloseError
+		if errors.As(err, &closeErr) {
+			if closeErr.Fatal() {
+				c.setState(StateStopped)
+				log.Printf("❌ Discord Gateway kalıcı hata ile kapandı: %v", err)
+				return err
+			}
+			if !closeErr.Resumable() {
+				c.clearSession()
+			}
+		}
+
+		if established {
+			delay = reconnectDelay
+		}
+
+		c.reconnects.Add(1)
+
+		if errors.Is(err, errReconnect) || errors.Is(err, errInvalidSession) {
+			continue
+		}
+
+		log.Printf("🔁 Discord Gateway yeniden bağlanıyor (%v sonra): %v", delay, err)
+
+		if err := sleepContext(ctx, delay); err != nil {
+			c.setState(StateStopped)
+			return err
+		}
+
+		delay *= 2
+		if maxDelay := c.config.Discord.Gateway.MaxReconnectDelay; maxDelay > 0 && delay > maxDelay {
+			delay = maxDelay
+		}
+	}
+}
+
+func (c *Client) connect(ctx context.Context) (bool, error) {
+	c.setState(StateConnecting)
+
+	conn, _, err := c.dialer.DialContext(ctx, c.gatewayURL(), nil)
+	if err != nil {
+		return false, fmt.Errorf("gateway bağlantısı kurulamadı: %w", err)
+	}
+	defer conn.Close()
+
+	stop := context.AfterFunc(ctx, func() {
+		conn.Close()
+	})
+	defer stop()
+
+	s := newSession(conn, c.config.Discord.Gateway.Compress)
+	defer s.close()
+
+	c.mutex.Lock()
+	c.lastHeartbeat = time.Time{}
+	c.mutex.Unlock()
+
+	hello, err := s.read()
+	if err != nil {
+		return false, closeError(err)
+	}
+	if hello.Op != OpHello {
+		return false, fmt.Errorf("gateway hello yerine op %d gönderdi", hello.Op)
+	}
+
+	var helloPayload helloData
+	if err := json.Unmarshal(hello.Data, &helloPayload); err != nil {
+		return false, fmt.Errorf("gateway hello parse edilemedi: %w", err)
+	}
+	interval := time.Duration(helloPayload.HeartbeatInterval) * time.Millisecond
+	if interval <= 0 {
+		return false, fmt.Errorf("geçersiz heartbeat aralığı: %d", helloPayload.HeartbeatInterval)
+	}
+
+	if err := c.handshake(s); err != nil {
+		return false, err
+	}
+
+	heartbeatCtx, cancelHeartbeat := context.WithCancel(ctx)
+	defer cancelHeartbeat()
+
+	heartbeatErr := make(chan error, 1)
+	go func() {
+		err := c.heartbeat(heartbeatCtx, s, interval)
+		heartbeatErr <- err
+		if err != nil {
+			conn.Close()
+		}
+	}()
+
+	established := false
+	for {
+		payload, err := s.read()
+		if err != nil {
+			select {
+			case hbErr := <-heartbeatErr:
+				if hbErr != nil {
+					return established, hbErr
+				}
+			default:
+			}
+			return established, closeError(err)
+		}
+
+		if payload.Sequence != nil {
+			c.sequence.Store(*payload.Sequence)
+		}
+
+		switch payload.Op {
+		case OpDispatch:
+			switch payload.Type {
+			case "READY":
+				var ready readyData
+				if err := json.Unmarshal(payload.Data, &ready); err != nil {
+					return established, fmt.Errorf("READY parse edilemedi: %w", err)
+				}
+				c.mutex.Lock()
+				c.sessionID = ready.SessionID
+				c.resumeURL = ready.ResumeGatewayURL
+				c.mutex.Unlock()
+				established = true
+				c.markConnected()
+				log.Printf("✅ Discord Gateway hazır (session: %s)", ready.SessionID)
+			case "RESUMED":
+				established = true
+				c.resumes.Add(1)
+				c.markConnected()
+				log.Printf("✅ Discord Gateway oturumu devam ettirildi")
+			}
+			c.dispatch(payload.Type, payload.Data)
+
+		case OpHeartbeat:
+			if err := c.sendHeartbeat(s); err != nil {
+				return established, err
+			}
+
+		case OpReconnect:
+			log.Printf("🔁 Discord Gateway yeniden bağlanma istedi")
+			return established, errReconnect
+
+		case OpInvalidSession:
+			var resumable bool
+			json.Unmarshal(payload.Data, &resumable)
+			if !resumable {
+				c.clearSession()
+			}
+			log.Printf("⚠️ Discord Gateway oturumu geçersiz (resumable: %t)", resumable)
+			if err := sleepContext(ctx, invalidSessionDelay()); err != nil {
+				return established, err
+			}
+			return established, errInvalidSession
+
+		case OpHeartbeatACK:
+			c.mutex.Lock()
+			c.lastAck = time.Now()
+			c.latency = c.lastAck.Sub(c.lastHeartbeat)
+			c.mutex.Unlock()
+		}
+	}
+}
+
+func (c *Client) handshake(s *session) error {
+	c.mutex.RLock()
+	sessionID := c.sessionID
+	c.mutex.RUnlock()
+
+	if sessionID != "" {
+		c.setState(StateResuming)
+		return s.write(OpResume, resumeData{
+			Token:     c.token,
+			SessionID: sessionID,
+			Sequence:  c.sequence.Load(),
+		})
+	}
+
+	c.setState(StateIdentifying)
+	c.identifies.Add(1)
+	return s.write(OpIdentify, identifyData{
+		Token: c.token,
+		Properties: identifyProperties{
+			OS:      runtime.GOOS,
+			Browser: "discord-user-api",
+			Device:  "discord-user-api",
+		},
+		LargeThreshold: c.config.Discord.Gateway.LargeThreshold,
+		Intents:        c.config.Discord.Gateway.Intents,
+	})
+}
+
+func (c *Client) heartbeat(ctx context.Context, s *session, interval time.Duration) error {
+	jitter := time.Duration(rand.Float64() * float64(interval))
+	if err := sleepContext(ctx, jitter); err != nil {
+		return nil
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		c.mutex.RLock()
+		awaitingAck := !c.lastHeartbeat.IsZero() && c.lastAck.Before(c.lastHeartbeat)
+		c.mutex.RUnlock()
+
+		if awaitingAck {
+			log.Printf("⚠️ Discord Gateway heartbeat ACK gelmedi, bağlantı yeniden kurulacak")
+			return errHeartbeatTimeout
+		}
+
+		if err := c.sendHeartbeat(s); err != nil {
+			return err
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+func (c *Client) sendHeartbeat(s *session) error {
+	c.mutex.Lock()
+	c.lastHeartbeat = time.Now()
+	c.mutex.Unlock()
+
+	var sequence interface{}
+	if seq := c.sequence.Load(); seq > 0 {
+		sequence = seq
+	}
+	return s.write(OpHeartbeat, sequence)
+}
+
+func (c *Client) gatewayURL() string {
+	c.mutex.RLock()
+	base := c.config.Discord.Gateway.URL
+	if c.sessionID != "" && c.resumeURL != "" {
+		base = c.resumeURL
+	}
+	c.mutex.RUnlock()
+
+	u, err := url.Parse(base)
+	if err != nil {
+		return base
+	}
+
+	query := u.Query()
+	query.Set("v", strings.TrimPrefix(c.config.Discord.APIVersion, "v"))
+	query.Set("encoding", "json")
+	if c.config.Discord.Gateway.Compress {
+		query.Set("compress", "zlib-stream")
+	}
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+func (c *Client) setState(state string) {
+	c.mutex.Lock()
+	c.state = state
+	c.mutex.Unlock()
+}
+
+func (c *Client) markConnected() {
+	c.mutex.Lock()
+	c.state = StateConnected
+	c.connectedAt = time.Now()
+	c.lastError = ""
+	c.mutex.Unlock()
+}
+
+func (c *Client) clearSession() {
+	c.mutex.Lock()
+	c.sessionID = ""
+	c.resumeURL = ""
+	c.mutex.Unlock()
+	c.sequence.Store(0)
+}
+
+func (c *Client) recordError(err error) {
+	c.mutex.Lock()
+	c.state = StateDisconnected
+	if err != nil {
+		c.lastError = err.Error()
+	}
+	c.mutex.Unlock()
+}
+
+func (c *Client) Stats() Stats {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	stats := Stats{
+		State:      c.state,
+		SessionID:  c.sessionID,
+		Sequence:   c.sequence.Load(),
+		LatencyMs:  c.latency.Milliseconds(),
+		Reconnects: c.reconnects.Load(),
+		Resumes:    c.resumes.Load(),
+		Identifies: c.identifies.Load(),
+		Dispatched: c.dispatched.Load(),
+		LastError:  c.lastError,
+		Events:     make(map[string]int64, len(c.events)),
+	}
+	if !c.lastAck.IsZero() {
+		stats.LastAck = c.lastAck.UTC().Format(time.RFC3339)
+	}
+	if !c.connectedAt.IsZero() {
+		stats.Connected = c.connectedAt.UTC().Format(time.RFC3339)
+	}
+	for eventType, count := range c.events {
+		stats.Events[eventType] = count
+	}
+	return stats
+}
+
+type session struct {
+	conn       *ws.Conn
+	writeMutex sync.Mutex
+	decoder    *json.Decoder
+	pipe       *io.PipeReader
+}
+
+func newSession(conn *ws.Conn, compress bool) *session {
+	s := &session{conn: conn}
+
+	if compress {
+		reader, writer := io.Pipe()
+		go func() {
+			for {
+				_, message, err := conn.ReadMessage()
+				if err != nil {
+					writer.CloseWithError(err)
+					return
+				}
+				if _, err := writer.Write(message); err != nil {
+					return
+				}
+			}
+		}()
+		s.pipe = reader
+		s.decoder = json.NewDecoder(&inflater{source: reader})
+	}
+
+	return s
+}
+
+func (s *session) close() {
+	if s.pipe != nil {
+		s.pipe.Close()
+	}
+}
+
+func (s *session) read() (*Payload, error) {
+	var payload Payload
+
+	if s.decoder != nil {
+		if err := s.decoder.Decode(&payload); err != nil {
+			return nil, err
+		}
+		return &payload, nil
+	}
+
+	_, message, err := s.conn.ReadMessage()
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(message, &payload); err != nil {
+		return nil, fmt.Errorf("gateway mesajı parse edilemedi: %w", err)
+	}
+	return &payload, nil
+}
+
+func (s *session) write(op int, data interface{}) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	s.writeMutex.Lock()
+	defer s.writeMutex.Unlock()
+
+	s.conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+	return s.conn.WriteJSON(Payload{Op: op, Data: raw})
+}
+
+// inflater defers reading the zlib header until the first frame arrives, so
+// a single zlib context spans every message of the connection.
+type inflater struct {
+	source io.Reader
+	reader io.ReadCloser
+}
+
+func (i *inflater) Read(p []byte) (int, error) {
+	if i.reader == nil {
+		reader, err := zlib.NewReader(i.source)
+		if err != nil {
+			return 0, err
+		}
+		i.reader = reader
+	}
+	return i.reader.Read(p)
+}
+
+func closeError(err error) error {
+	var wsClose *ws.CloseError
+	if errors.As(err, &wsClose) {
+		return &CloseError{Code: wsClose.Code, Reason: wsClose.Text}
+	}
+	return err
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
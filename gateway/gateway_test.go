@@ -0,0 +1,286 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	"discord-user-api/cache"
+	"discord-user-api/config"
+	"discord-user-api/discordtest"
+	"discord-user-api/models"
+)
+
+const waitTimeout = 2 * time.Second
+
+func TestMain(m *testing.M) {
+	log.SetOutput(io.Discard)
+	reconnectDelay = 10 * time.Millisecond
+	invalidSessionDelay = func() time.Duration { return 10 * time.Millisecond }
+	os.Exit(m.Run())
+}
+
+type gatewayHarness struct {
+	fake   *discordtest.Gateway
+	client *Client
+	cache  *cache.Cache
+	done   chan error
+}
+
+func startGateway(t *testing.T, compress bool) *gatewayHarness {
+	t.Helper()
+
+	fake := discordtest.NewGateway()
+	t.Cleanup(fake.Close)
+
+	cfg := &config.Config{}
+	cfg.Cache.Enabled = true
+	fake.ConfigureClient(cfg)
+	cfg.Discord.Gateway.Compress = compress
+	cfg.Discord.Gateway.Intents = 1 | 2
+
+	store := cache.NewCache(100, time.Minute, time.Minute)
+	t.Cleanup(store.Stop)
+
+	h := &gatewayHarness{
+		fake:   fake,
+		client: NewClient(cfg, store, nil),
+		cache:  store,
+		done:   make(chan error, 1),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() { h.done <- h.client.Run(ctx) }()
+	t.Cleanup(func() {
+		cancel()
+		<-h.done
+	})
+	return h
+}
+
+func (h *gatewayHarness) nextConn(t *testing.T) *discordtest.GatewayConn {
+	t.Helper()
+	conn, err := h.fake.NextConn(waitTimeout)
+	if err != nil {
+		t.Fatalf("gateway bağlantısı gelmedi: %v", err)
+	}
+	return conn
+}
+
+// connectReady accepts a connection, expects IDENTIFY and answers READY.
+func (h *gatewayHarness) connectReady(t *testing.T, sessionID string) *discordtest.GatewayConn {
+	t.Helper()
+	conn := h.nextConn(t)
+	expectOp(t, conn, OpIdentify)
+	if err := conn.Ready(1, sessionID); err != nil {
+		t.Fatal(err)
+	}
+	eventually(t, func() bool { return h.client.Stats().State == StateConnected })
+	return conn
+}
+
+func expectOp(t *testing.T, conn *discordtest.GatewayConn, op int) discordtest.GatewayPayload {
+	t.Helper()
+	payload, err := conn.Read(waitTimeout)
+	if err != nil {
+		t.Fatalf("op %d beklenirken: %v", op, err)
+	}
+	if payload.Op != op {
+		t.Fatalf("op %d beklendi, %d geldi (%s)", op, payload.Op, payload.Data)
+	}
+	return payload
+}
+
+func eventually(t *testing.T, condition func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(waitTimeout)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal("koşul zamanında sağlanmadı")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestHelloIdentifyReady(t *testing.T) {
+	h := startGateway(t, false)
+
+	conn := h.nextConn(t)
+	if got := conn.Query.Get("encoding"); got != "json" {
+		t.Errorf("encoding = %q, want json", got)
+	}
+	if conn.Query.Has("compress") {
+		t.Errorf("compress parametresi gönderilmemeliydi: %v", conn.Query)
+	}
+
+	payload := expectOp(t, conn, OpIdentify)
+	var identify identifyData
+	if err := json.Unmarshal(payload.Data, &identify); err != nil {
+		t.Fatal(err)
+	}
+	if identify.Token != discordtest.DefaultToken || identify.Intents != 1|2 {
+		t.Errorf("IDENTIFY = %+v", identify)
+	}
+
+	if err := conn.Ready(1, "session-1"); err != nil {
+		t.Fatal(err)
+	}
+	eventually(t, func() bool { return h.client.Stats().State == StateConnected })
+
+	if _, err := conn.Heartbeat(waitTimeout); err != nil {
+		t.Fatalf("heartbeat gelmedi: %v", err)
+	}
+	eventually(t, func() bool { return h.client.Stats().LastAck != "" })
+
+	stats := h.client.Stats()
+	if stats.SessionID != "session-1" || stats.Identifies != 1 || stats.Events["READY"] != 1 {
+		t.Errorf("stats = %+v", stats)
+	}
+}
+
+func TestHeartbeatTimeoutReconnectsAndResumes(t *testing.T) {
+	h := startGateway(t, false)
+	h.fake.SetAckHeartbeats(false)
+
+	conn := h.connectReady(t, "session-1")
+	if err := conn.Closed(waitTimeout); err != nil {
+		t.Fatalf("ACK gelmeyince bağlantı kapanmadı: %v", err)
+	}
+
+	next := h.nextConn(t)
+	payload := expectOp(t, next, OpResume)
+	var resume resumeData
+	if err := json.Unmarshal(payload.Data, &resume); err != nil {
+		t.Fatal(err)
+	}
+	if resume.SessionID != "session-1" {
+		t.Errorf("RESUME session = %q", resume.SessionID)
+	}
+
+	stats := h.client.Stats()
+	if stats.Reconnects < 1 || stats.LastError != errHeartbeatTimeout.Error() {
+		t.Errorf("stats = %+v", stats)
+	}
+}
+
+func TestResumeAfterDrop(t *testing.T) {
+	h := startGateway(t, false)
+
+	conn := h.connectReady(t, "session-1")
+	if err := conn.Dispatch("CHANNEL_CREATE", 2, models.DiscordChannel{ID: 10, GuildID: 1}); err != nil {
+		t.Fatal(err)
+	}
+	eventually(t, func() bool { return h.client.Stats().Sequence == 2 })
+	conn.Drop()
+
+	next := h.nextConn(t)
+	payload := expectOp(t, next, OpResume)
+	var resume resumeData
+	if err := json.Unmarshal(payload.Data, &resume); err != nil {
+		t.Fatal(err)
+	}
+	if resume.SessionID != "session-1" || resume.Sequence != 2 || resume.Token != discordtest.DefaultToken {
+		t.Errorf("RESUME = %+v", resume)
+	}
+
+	if err := next.Dispatch("RESUMED", 3, nil); err != nil {
+		t.Fatal(err)
+	}
+	eventually(t, func() bool {
+		stats := h.client.Stats()
+		return stats.State == StateConnected && stats.Resumes == 1
+	})
+	if identifies := h.client.Stats().Identifies; identifies != 1 {
+		t.Errorf("identifies = %d, want 1", identifies)
+	}
+}
+
+func TestInvalidSession(t *testing.T) {
+	tests := []struct {
+		name      string
+		resumable bool
+		wantOp    int
+	}{
+		{"not resumable identifies again", false, OpIdentify},
+		{"resumable resumes", true, OpResume},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := startGateway(t, false)
+
+			conn := h.connectReady(t, "session-1")
+			if err := conn.Send(OpInvalidSession, tt.resumable); err != nil {
+				t.Fatal(err)
+			}
+
+			next := h.nextConn(t)
+			expectOp(t, next, tt.wantOp)
+
+			stats := h.client.Stats()
+			if tt.resumable && stats.SessionID != "session-1" {
+				t.Errorf("session silinmemeliydi: %+v", stats)
+			}
+			if !tt.resumable && (stats.SessionID != "" || stats.Identifies != 2) {
+				t.Errorf("session sıfırlanmalıydı: %+v", stats)
+			}
+		})
+	}
+}
+
+func TestFatalCloseCodeStops(t *testing.T) {
+	h := startGateway(t, false)
+
+	conn := h.connectReady(t, "session-1")
+	conn.CloseWithCode(4004, "Authentication failed")
+
+	select {
+	case err := <-h.done:
+		var closeErr *CloseError
+		if !errors.As(err, &closeErr) || closeErr.Code != 4004 {
+			t.Fatalf("Run = %v, want CloseError 4004", err)
+		}
+		h.done <- err
+	case <-time.After(waitTimeout):
+		t.Fatal("4004 sonrası Run dönmedi")
+	}
+
+	if state := h.client.Stats().State; state != StateStopped {
+		t.Errorf("state = %q, want %q", state, StateStopped)
+	}
+}
+
+func TestZlibStream(t *testing.T) {
+	h := startGateway(t, true)
+	h.cache.Set("guild_1", &models.DiscordGuild{ID: 1, Name: "eski"})
+
+	conn := h.nextConn(t)
+	if got := conn.Query.Get("compress"); got != "zlib-stream" {
+		t.Fatalf("compress = %q, want zlib-stream", got)
+	}
+	expectOp(t, conn, OpIdentify)
+
+	if err := conn.Ready(1, "session-z"); err != nil {
+		t.Fatal(err)
+	}
+	for i, name := range []string{"ara", "yeni"} {
+		if err := conn.Dispatch("GUILD_UPDATE", int64(i+2), map[string]interface{}{"id": "1", "name": name}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	eventually(t, func() bool {
+		value, ok := h.cache.Get("guild_1")
+		return ok && value.(*models.DiscordGuild).Name == "yeni"
+	})
+
+	stats := h.client.Stats()
+	if stats.SessionID != "session-z" || stats.Sequence != 3 || stats.Events["GUILD_UPDATE"] != 2 {
+		t.Errorf("stats = %+v", stats)
+	}
+}
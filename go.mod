@@ -3,6 +3,9 @@ module discord-user-api
 go 1.24.4
 
 require (
+	github.com/alicebob/miniredis/v2 v2.37.0
 	github.com/gorilla/websocket v1.5.3
 	github.com/joho/godotenv v1.5.1
 )
+
+require github.com/yuin/gopher-lua v1.1.1 // indirect
@@ -0,0 +1,286 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"discord-user-api/snowflake"
+)
+
+type AuditLogEvent int
+
+const (
+	AuditLogGuildUpdate                             AuditLogEvent = 1
+	AuditLogChannelCreate                           AuditLogEvent = 10
+	AuditLogChannelUpdate                           AuditLogEvent = 11
+	AuditLogChannelDelete                           AuditLogEvent = 12
+	AuditLogChannelOverwriteCreate                  AuditLogEvent = 13
+	AuditLogChannelOverwriteUpdate                  AuditLogEvent = 14
+	AuditLogChannelOverwriteDelete                  AuditLogEvent = 15
+	AuditLogMemberKick                              AuditLogEvent = 20
+	AuditLogMemberPrune                             AuditLogEvent = 21
+	AuditLogMemberBanAdd                            AuditLogEvent = 22
+	AuditLogMemberBanRemove                         AuditLogEvent = 23
+	AuditLogMemberUpdate                            AuditLogEvent = 24
+	AuditLogMemberRoleUpdate                        AuditLogEvent = 25
+	AuditLogMemberMove                              AuditLogEvent = 26
+	AuditLogMemberDisconnect                        AuditLogEvent = 27
+	AuditLogBotAdd                                  AuditLogEvent = 28
+	AuditLogRoleCreate                              AuditLogEvent = 30
+	AuditLogRoleUpdate                              AuditLogEvent = 31
+	AuditLogRoleDelete                              AuditLogEvent = 32
+	AuditLogInviteCreate                            AuditLogEvent = 40
+	AuditLogInviteUpdate                            AuditLogEvent = 41
+	AuditLogInviteDelete                            AuditLogEvent = 42
+	AuditLogWebhookCreate                           AuditLogEvent = 50
+	AuditLogWebhookUpdate                           AuditLogEvent = 51
+	AuditLogWebhookDelete                           AuditLogEvent = 52
+	AuditLogEmojiCreate                             AuditLogEvent = 60
+	AuditLogEmojiUpdate                             AuditLogEvent = 61
+	AuditLogEmojiDelete                             AuditLogEvent = 62
+	AuditLogMessageDelete                           AuditLogEvent = 72
+	AuditLogMessageBulkDelete                       AuditLogEvent = 73
+	AuditLogMessagePin                              AuditLogEvent = 74
+	AuditLogMessageUnpin                            AuditLogEvent = 75
+	AuditLogIntegrationCreate                       AuditLogEvent = 80
+	AuditLogIntegrationUpdate                       AuditLogEvent = 81
+	AuditLogIntegrationDelete                       AuditLogEvent = 82
+	AuditLogStageInstanceCreate                     AuditLogEvent = 83
+	AuditLogStageInstanceUpdate                     AuditLogEvent = 84
+	AuditLogStageInstanceDelete                     AuditLogEvent = 85
+	AuditLogStickerCreate                           AuditLogEvent = 90
+	AuditLogStickerUpdate                           AuditLogEvent = 91
+	AuditLogStickerDelete                           AuditLogEvent = 92
+	AuditLogGuildScheduledEventCreate               AuditLogEvent = 100
+	AuditLogGuildScheduledEventUpdate               AuditLogEvent = 101
+	AuditLogGuildScheduledEventDelete               AuditLogEvent = 102
+	AuditLogThreadCreate                            AuditLogEvent = 110
+	AuditLogThreadUpdate                            AuditLogEvent = 111
+	AuditLogThreadDelete                            AuditLogEvent = 112
+	AuditLogApplicationCommandPermissionUpdate      AuditLogEvent = 121
+	AuditLogSoundboardSoundCreate                   AuditLogEvent = 130
+	AuditLogSoundboardSoundUpdate                   AuditLogEvent = 131
+	AuditLogSoundboardSoundDelete                   AuditLogEvent = 132
+	AuditLogAutoModerationRuleCreate                AuditLogEvent = 140
+	AuditLogAutoModerationRuleUpdate                AuditLogEvent = 141
+	AuditLogAutoModerationRuleDelete                AuditLogEvent = 142
+	AuditLogAutoModerationBlockMessage              AuditLogEvent = 143
+	AuditLogAutoModerationFlagToChannel             AuditLogEvent = 144
+	AuditLogAutoModerationUserCommunicationDisabled AuditLogEvent = 145
+	AuditLogAutoModerationQuarantineUser            AuditLogEvent = 146
+	AuditLogCreatorMonetizationRequestCreated       AuditLogEvent = 150
+	AuditLogCreatorMonetizationTermsAccepted        AuditLogEvent = 151
+	AuditLogOnboardingPromptCreate                  AuditLogEvent = 163
+	AuditLogOnboardingPromptUpdate                  AuditLogEvent = 164
+	AuditLogOnboardingPromptDelete                  AuditLogEvent = 165
+	AuditLogOnboardingCreate                        AuditLogEvent = 166
+	AuditLogOnboardingUpdate                        AuditLogEvent = 167
+	AuditLogHomeSettingsCreate                      AuditLogEvent = 190
+	AuditLogHomeSettingsUpdate                      AuditLogEvent = 191
+)
+
+var auditLogEventNames = map[AuditLogEvent]string{
+	AuditLogGuildUpdate:                             "guild_update",
+	AuditLogChannelCreate:                           "channel_create",
+	AuditLogChannelUpdate:                           "channel_update",
+	AuditLogChannelDelete:                           "channel_delete",
+	AuditLogChannelOverwriteCreate:                  "channel_overwrite_create",
+	AuditLogChannelOverwriteUpdate:                  "channel_overwrite_update",
+	AuditLogChannelOverwriteDelete:                  "channel_overwrite_delete",
+	AuditLogMemberKick:                              "member_kick",
+	AuditLogMemberPrune:                             "member_prune",
+	AuditLogMemberBanAdd:                            "member_ban_add",
+	AuditLogMemberBanRemove:                         "member_ban_remove",
+	AuditLogMemberUpdate:                            "member_update",
+	AuditLogMemberRoleUpdate:                        "member_role_update",
+	AuditLogMemberMove:                              "member_move",
+	AuditLogMemberDisconnect:                        "member_disconnect",
+	AuditLogBotAdd:                                  "bot_add",
+	AuditLogRoleCreate:                              "role_create",
+	AuditLogRoleUpdate:                              "role_update",
+	AuditLogRoleDelete:                              "role_delete",
+	AuditLogInviteCreate:                            "invite_create",
+	AuditLogInviteUpdate:                            "invite_update",
+	AuditLogInviteDelete:                            "invite_delete",
+	AuditLogWebhookCreate:                           "webhook_create",
+	AuditLogWebhookUpdate:                           "webhook_update",
+	AuditLogWebhookDelete:                           "webhook_delete",
+	AuditLogEmojiCreate:                             "emoji_create",
+	AuditLogEmojiUpdate:                             "emoji_update",
+	AuditLogEmojiDelete:                             "emoji_delete",
+	AuditLogMessageDelete:                           "message_delete",
+	AuditLogMessageBulkDelete:                       "message_bulk_delete",
+	AuditLogMessagePin:                              "message_pin",
+	AuditLogMessageUnpin:                            "message_unpin",
+	AuditLogIntegrationCreate:                       "integration_create",
+	AuditLogIntegrationUpdate:                       "integration_update",
+	AuditLogIntegrationDelete:                       "integration_delete",
+	AuditLogStageInstanceCreate:                     "stage_instance_create",
+	AuditLogStageInstanceUpdate:                     "stage_instance_update",
+	AuditLogStageInstanceDelete:                     "stage_instance_delete",
+	AuditLogStickerCreate:                           "sticker_create",
+	AuditLogStickerUpdate:                           "sticker_update",
+	AuditLogStickerDelete:                           "sticker_delete",
+	AuditLogGuildScheduledEventCreate:               "guild_scheduled_event_create",
+	AuditLogGuildScheduledEventUpdate:               "guild_scheduled_event_update",
+	AuditLogGuildScheduledEventDelete:               "guild_scheduled_event_delete",
+	AuditLogThreadCreate:                            "thread_create",
+	AuditLogThreadUpdate:                            "thread_update",
+	AuditLogThreadDelete:                            "thread_delete",
+	AuditLogApplicationCommandPermissionUpdate:      "application_command_permission_update",
+	AuditLogSoundboardSoundCreate:                   "soundboard_sound_create",
+	AuditLogSoundboardSoundUpdate:                   "soundboard_sound_update",
+	AuditLogSoundboardSoundDelete:                   "soundboard_sound_delete",
+	AuditLogAutoModerationRuleCreate:                "auto_moderation_rule_create",
+	AuditLogAutoModerationRuleUpdate:                "auto_moderation_rule_update",
+	AuditLogAutoModerationRuleDelete:                "auto_moderation_rule_delete",
+	AuditLogAutoModerationBlockMessage:              "auto_moderation_block_message",
+	AuditLogAutoModerationFlagToChannel:             "auto_moderation_flag_to_channel",
+	AuditLogAutoModerationUserCommunicationDisabled: "auto_moderation_user_communication_disabled",
+	AuditLogAutoModerationQuarantineUser:            "auto_moderation_quarantine_user",
+	AuditLogCreatorMonetizationRequestCreated:       "creator_monetization_request_created",
+	AuditLogCreatorMonetizationTermsAccepted:        "creator_monetization_terms_accepted",
+	AuditLogOnboardingPromptCreate:                  "onboarding_prompt_create",
+	AuditLogOnboardingPromptUpdate:                  "onboarding_prompt_update",
+	AuditLogOnboardingPromptDelete:                  "onboarding_prompt_delete",
+	AuditLogOnboardingCreate:                        "onboarding_create",
+	AuditLogOnboardingUpdate:                        "onboarding_update",
+	AuditLogHomeSettingsCreate:                      "home_settings_create",
+	AuditLogHomeSettingsUpdate:                      "home_settings_update",
+}
+
+type AuditLog struct {
+	AuditLogEntries []AuditLogEntry  `json:"audit_log_entries"`
+	Users           []DiscordUser    `json:"users"`
+	Webhooks        []Webhook        `json:"webhooks"`
+	Integrations    []Integration    `json:"integrations"`
+	Threads         []DiscordChannel `json:"threads,omitempty"`
+}
+
+type AuditLogEntry struct {
+	ID         snowflake.Snowflake `json:"id"`
+	TargetID   snowflake.Snowflake `json:"target_id,omitempty"`
+	UserID     snowflake.Snowflake `json:"user_id,omitempty"`
+	ActionType AuditLogEvent       `json:"action_type"`
+	Changes    []AuditLogChange    `json:"changes,omitempty"`
+	Options    *AuditLogEntryInfo  `json:"options,omitempty"`
+	Reason     string              `json:"reason,omitempty"`
+}
+
+// AuditLogChange values keep Discord's raw JSON: depending on Key they are
+// strings, numbers, booleans or arrays of partial roles/overwrites.
+type AuditLogChange struct {
+	Key      string          `json:"key"`
+	NewValue json.RawMessage `json:"new_value,omitempty"`
+	OldValue json.RawMessage `json:"old_value,omitempty"`
+}
+
+type AuditLogEntryInfo struct {
+	ApplicationID                 snowflake.Snowflake `json:"application_id,omitempty"`
+	AutoModerationRuleName        string              `json:"auto_moderation_rule_name,omitempty"`
+	AutoModerationRuleTriggerType string              `json:"auto_moderation_rule_trigger_type,omitempty"`
+	ChannelID                     snowflake.Snowflake `json:"channel_id,omitempty"`
+	Count                         string              `json:"count,omitempty"`
+	DeleteMemberDays              string              `json:"delete_member_days,omitempty"`
+	ID                            snowflake.Snowflake `json:"id,omitempty"`
+	MembersRemoved                string              `json:"members_removed,omitempty"`
+	MessageID                     snowflake.Snowflake `json:"message_id,omitempty"`
+	RoleName                      string              `json:"role_name,omitempty"`
+	Type                          string              `json:"type,omitempty"`
+	IntegrationType               string              `json:"integration_type,omitempty"`
+}
+
+type Webhook struct {
+	ID            snowflake.Snowflake `json:"id"`
+	Type          int                 `json:"type"`
+	GuildID       snowflake.Snowflake `json:"guild_id,omitempty"`
+	ChannelID     snowflake.Snowflake `json:"channel_id,omitempty"`
+	User          *DiscordUser        `json:"user,omitempty"`
+	Name          string              `json:"name,omitempty"`
+	Avatar        string              `json:"avatar,omitempty"`
+	ApplicationID snowflake.Snowflake `json:"application_id,omitempty"`
+	URL           string              `json:"url,omitempty"`
+}
+
+type Integration struct {
+	ID                snowflake.Snowflake     `json:"id"`
+	Name              string                  `json:"name"`
+	Type              string                  `json:"type"`
+	Enabled           bool                    `json:"enabled"`
+	Syncing           bool                    `json:"syncing,omitempty"`
+	RoleID            snowflake.Snowflake     `json:"role_id,omitempty"`
+	EnableEmoticons   bool                    `json:"enable_emoticons,omitempty"`
+	ExpireBehavior    int                     `json:"expire_behavior,omitempty"`
+	ExpireGracePeriod int                     `json:"expire_grace_period,omitempty"`
+	User              *DiscordUser            `json:"user,omitempty"`
+	Account           IntegrationAccount      `json:"account"`
+	SyncedAt          string                  `json:"synced_at,omitempty"`
+	SubscriberCount   int                     `json:"subscriber_count,omitempty"`
+	Revoked           bool                    `json:"revoked,omitempty"`
+	Application       *IntegrationApplication `json:"application,omitempty"`
+	Scopes            []string                `json:"scopes,omitempty"`
+}
+
+type IntegrationAccount struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type IntegrationApplication struct {
+	ID          snowflake.Snowflake `json:"id"`
+	Name        string              `json:"name"`
+	Icon        string              `json:"icon,omitempty"`
+	Description string              `json:"description"`
+	Bot         *DiscordUser        `json:"bot,omitempty"`
+}
+
+// ParseAuditLogEvent accepts either a named action type ("member_ban_add",
+// case-insensitive) or Discord's numeric value.
+func ParseAuditLogEvent(value string) (AuditLogEvent, error) {
+	if number, err := strconv.Atoi(value); err == nil && number > 0 {
+		return AuditLogEvent(number), nil
+	}
+
+	name := strings.ToLower(strings.TrimSpace(value))
+	for event, eventName := range auditLogEventNames {
+		if eventName == name {
+			return event, nil
+		}
+	}
+	return 0, fmt.Errorf("bilinmeyen audit log action type: %q", value)
+}
+
+func (e AuditLogEvent) String() string {
+	if name, ok := auditLogEventNames[e]; ok {
+		return name
+	}
+	return strconv.Itoa(int(e))
+}
+
+func (e AuditLogEvent) MarshalJSON() ([]byte, error) {
+	if name, ok := auditLogEventNames[e]; ok {
+		return json.Marshal(name)
+	}
+	return []byte(strconv.Itoa(int(e))), nil
+}
+
+func (e *AuditLogEvent) UnmarshalJSON(data []byte) error {
+	var number int
+	if err := json.Unmarshal(data, &number); err == nil {
+		*e = AuditLogEvent(number)
+		return nil
+	}
+
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return err
+	}
+	event, err := ParseAuditLogEvent(name)
+	if err != nil {
+		return err
+	}
+	*e = event
+	return nil
+}
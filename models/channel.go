@@ -0,0 +1,100 @@
+package models
+
+import "discord-user-api/snowflake"
+
+const (
+	ChannelTypeGuildText          = 0
+	ChannelTypeDM                 = 1
+	ChannelTypeGuildVoice         = 2
+	ChannelTypeGroupDM            = 3
+	ChannelTypeGuildCategory      = 4
+	ChannelTypeGuildAnnouncement  = 5
+	ChannelTypeAnnouncementThread = 10
+	ChannelTypePublicThread       = 11
+	ChannelTypePrivateThread      = 12
+	ChannelTypeGuildStageVoice    = 13
+	ChannelTypeGuildDirectory     = 14
+	ChannelTypeGuildForum         = 15
+	ChannelTypeGuildMedia         = 16
+)
+
+const (
+	PermissionOverwriteTypeRole   = 0
+	PermissionOverwriteTypeMember = 1
+)
+
+type DiscordChannel struct {
+	ID                            snowflake.Snowflake   `json:"id"`
+	Type                          int                   `json:"type"`
+	GuildID                       snowflake.Snowflake   `json:"guild_id,omitempty"`
+	Position                      int                   `json:"position"`
+	PermissionOverwrites          []PermissionOverwrite `json:"permission_overwrites,omitempty"`
+	Name                          string                `json:"name"`
+	Topic                         string                `json:"topic,omitempty"`
+	NSFW                          bool                  `json:"nsfw"`
+	LastMessageID                 snowflake.Snowflake   `json:"last_message_id,omitempty"`
+	Bitrate                       int                   `json:"bitrate,omitempty"`
+	UserLimit                     int                   `json:"user_limit,omitempty"`
+	RateLimitPerUser              int                   `json:"rate_limit_per_user,omitempty"`
+	Recipients                    []DiscordUser         `json:"recipients,omitempty"`
+	Icon                          string                `json:"icon,omitempty"`
+	OwnerID                       snowflake.Snowflake   `json:"owner_id,omitempty"`
+	ParentID                      snowflake.Snowflake   `json:"parent_id,omitempty"`
+	LastPinTimestamp              string                `json:"last_pin_timestamp,omitempty"`
+	RTCRegion                     string                `json:"rtc_region,omitempty"`
+	VideoQualityMode              int                   `json:"video_quality_mode,omitempty"`
+	MessageCount                  int                   `json:"message_count,omitempty"`
+	MemberCount                   int                   `json:"member_count,omitempty"`
+	ThreadMetadata                *ThreadMetadata       `json:"thread_metadata,omitempty"`
+	DefaultAutoArchiveDuration    int                   `json:"default_auto_archive_duration,omitempty"`
+	Permissions                   string                `json:"permissions,omitempty"`
+	Flags                         int                   `json:"flags"`
+	TotalMessageSent              int                   `json:"total_message_sent,omitempty"`
+	AvailableTags                 []ForumTag            `json:"available_tags,omitempty"`
+	AppliedTags                   []snowflake.Snowflake `json:"applied_tags,omitempty"`
+	DefaultReactionEmoji          *DefaultReaction      `json:"default_reaction_emoji,omitempty"`
+	DefaultThreadRateLimitPerUser int                   `json:"default_thread_rate_limit_per_user,omitempty"`
+	DefaultSortOrder              *int                  `json:"default_sort_order,omitempty"`
+	DefaultForumLayout            int                   `json:"default_forum_layout,omitempty"`
+}
+
+type PermissionOverwrite struct {
+	ID    snowflake.Snowflake `json:"id"`
+	Type  int                 `json:"type"`
+	Allow string              `json:"allow"`
+	Deny  string              `json:"deny"`
+}
+
+type ThreadMetadata struct {
+	Archived            bool   `json:"archived"`
+	AutoArchiveDuration int    `json:"auto_archive_duration"`
+	ArchiveTimestamp    string `json:"archive_timestamp"`
+	Locked              bool   `json:"locked"`
+	Invitable           bool   `json:"invitable,omitempty"`
+	CreateTimestamp     string `json:"create_timestamp,omitempty"`
+}
+
+type ForumTag struct {
+	ID        snowflake.Snowflake `json:"id"`
+	Name      string              `json:"name"`
+	Moderated bool                `json:"moderated"`
+	EmojiID   snowflake.Snowflake `json:"emoji_id,omitempty"`
+	EmojiName string              `json:"emoji_name,omitempty"`
+}
+
+type DefaultReaction struct {
+	EmojiID   snowflake.Snowflake `json:"emoji_id,omitempty"`
+	EmojiName string              `json:"emoji_name,omitempty"`
+}
+
+func (c *DiscordChannel) IsThread() bool {
+	switch c.Type {
+	case ChannelTypeAnnouncementThread, ChannelTypePublicThread, ChannelTypePrivateThread:
+		return true
+	}
+	return false
+}
+
+func (c *DiscordChannel) IsVoice() bool {
+	return c.Type == ChannelTypeGuildVoice || c.Type == ChannelTypeGuildStageVoice
+}
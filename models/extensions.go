@@ -0,0 +1,90 @@
+package models
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Extensions keeps the fields Discord sent that a typed model does not know
+// about yet. They are written back out on marshal, so newly added Discord
+// fields survive the cache and reach API consumers unchanged.
+type Extensions map[string]json.RawMessage
+
+var knownFieldsCache sync.Map
+
+// unmarshalExtended decodes data into v, a pointer to an alias of the model
+// without custom JSON methods, and stores every key the model has no field
+// for in extensions.
+func unmarshalExtended[T any](data []byte, v *T, extensions *Extensions) error {
+	var decoded T
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	*v = decoded
+	*extensions = nil
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil || len(raw) == 0 {
+		return nil
+	}
+
+	known := knownFields(reflect.TypeOf(decoded))
+	for key, value := range raw {
+		if known[strings.ToLower(key)] {
+			continue
+		}
+		if *extensions == nil {
+			*extensions = make(Extensions)
+		}
+		(*extensions)[key] = value
+	}
+	return nil
+}
+
+// marshalWithExtensions encodes v and adds the extension keys that are not
+// typed fields, so a typed field always wins even when it is omitted.
+func marshalWithExtensions(v interface{}, extensions Extensions) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil || len(extensions) == 0 {
+		return data, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	known := knownFields(reflect.TypeOf(v))
+	for key, value := range extensions {
+		if !known[strings.ToLower(key)] {
+			fields[key] = value
+		}
+	}
+	return json.Marshal(fields)
+}
+
+// knownFields returns the lowercased JSON names of t's fields, since
+// encoding/json matches keys to fields case-insensitively.
+func knownFields(t reflect.Type) map[string]bool {
+	if cached, ok := knownFieldsCache.Load(t); ok {
+		return cached.(map[string]bool)
+	}
+
+	known := make(map[string]bool, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" || !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = field.Name
+		}
+		known[strings.ToLower(name)] = true
+	}
+
+	knownFieldsCache.Store(t, known)
+	return known
+}
@@ -0,0 +1,98 @@
+package models
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func assertSameJSON(t *testing.T, got []byte, want string) {
+	t.Helper()
+
+	var gotValue, wantValue interface{}
+	if err := json.Unmarshal(got, &gotValue); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(want), &wantValue); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(gotValue, wantValue) {
+		t.Errorf("json = %s, want %s", got, want)
+	}
+}
+
+func TestExtensionsRoundTripUnknownKeys(t *testing.T) {
+	input := `{"asset":"a_1","sku_id":"175928847299117063","expires_at":null,"new_field":{"nested":[1,2]},"flag":true}`
+
+	var decoration AvatarDecorationData
+	if err := json.Unmarshal([]byte(input), &decoration); err != nil {
+		t.Fatal(err)
+	}
+	if decoration.Asset != "a_1" || decoration.SKUID != 175928847299117063 {
+		t.Errorf("decoration = %+v", decoration)
+	}
+	if len(decoration.Extensions) != 2 || string(decoration.Extensions["new_field"]) != `{"nested":[1,2]}` {
+		t.Errorf("extensions = %v", decoration.Extensions)
+	}
+
+	encoded, err := json.Marshal(decoration)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertSameJSON(t, encoded, input)
+}
+
+func TestExtensionsNestedTypedStruct(t *testing.T) {
+	input := `{"nameplate":{"sku_id":"1","asset":"plate","label":"","palette":"crimson","expires_at":null,"glow":"soft"},"avatar_frame":{"id":"2"}}`
+
+	var user DiscordUser
+	if err := json.Unmarshal([]byte(`{"id":"1","username":"test","collectibles":`+input+`}`), &user); err != nil {
+		t.Fatal(err)
+	}
+
+	collectibles := user.Collectibles
+	if collectibles == nil || collectibles.Nameplate == nil {
+		t.Fatalf("collectibles = %+v", collectibles)
+	}
+	if collectibles.Nameplate.Palette != "crimson" || string(collectibles.Nameplate.Extensions["glow"]) != `"soft"` {
+		t.Errorf("nameplate = %+v", collectibles.Nameplate)
+	}
+	if len(collectibles.Extensions) != 1 || collectibles.Extensions["avatar_frame"] == nil {
+		t.Errorf("collectibles extensions = %v", collectibles.Extensions)
+	}
+
+	encoded, err := json.Marshal(collectibles)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertSameJSON(t, encoded, input)
+}
+
+func TestExtensionsTypedFieldWins(t *testing.T) {
+	// encoding/json fills typed fields case-insensitively, so such keys are
+	// not extensions.
+	var badge ProfileBadge
+	if err := json.Unmarshal([]byte(`{"ID":"staff","Description":"Staff","icon":"i","LINK":"https://discord.com"}`), &badge); err != nil {
+		t.Fatal(err)
+	}
+	if badge.ID != "staff" || badge.Link != "https://discord.com" || badge.Extensions != nil {
+		t.Errorf("badge = %+v", badge)
+	}
+
+	badge = ProfileBadge{
+		ID:          "staff",
+		Description: "Staff",
+		Icon:        "i",
+		Extensions: Extensions{
+			"id":    json.RawMessage(`"from-extension"`),
+			"Icon":  json.RawMessage(`"from-extension"`),
+			"link":  json.RawMessage(`"https://example.com"`),
+			"quest": json.RawMessage(`1`),
+		},
+	}
+	encoded, err := json.Marshal(badge)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertSameJSON(t, encoded, `{"id":"staff","description":"Staff","icon":"i","quest":1}`)
+}
@@ -0,0 +1,128 @@
+package models
+
+import "discord-user-api/snowflake"
+
+const (
+	StickerTypeStandard = 1
+	StickerTypeGuild    = 2
+)
+
+const (
+	StickerFormatPNG    = 1
+	StickerFormatAPNG   = 2
+	StickerFormatLottie = 3
+	StickerFormatGIF    = 4
+)
+
+const (
+	InviteTargetTypeStream              = 1
+	InviteTargetTypeEmbeddedApplication = 2
+)
+
+type RoleColors struct {
+	PrimaryColor   int        `json:"primary_color"`
+	SecondaryColor *int       `json:"secondary_color"`
+	TertiaryColor  *int       `json:"tertiary_color"`
+	Extensions     Extensions `json:"-"`
+}
+
+type IncidentsData struct {
+	InvitesDisabledUntil string     `json:"invites_disabled_until"`
+	DMsDisabledUntil     string     `json:"dms_disabled_until"`
+	DMSpamDetectedAt     string     `json:"dm_spam_detected_at,omitempty"`
+	RaidDetectedAt       string     `json:"raid_detected_at,omitempty"`
+	Extensions           Extensions `json:"-"`
+}
+
+type InventorySettings struct {
+	IsEmojiPackCollectible bool       `json:"is_emoji_pack_collectible"`
+	Extensions             Extensions `json:"-"`
+}
+
+type DiscordSticker struct {
+	ID          snowflake.Snowflake `json:"id"`
+	PackID      snowflake.Snowflake `json:"pack_id,omitempty"`
+	Name        string              `json:"name"`
+	Description string              `json:"description"`
+	Tags        string              `json:"tags"`
+	Type        int                 `json:"type"`
+	FormatType  int                 `json:"format_type"`
+	Available   bool                `json:"available"`
+	GuildID     snowflake.Snowflake `json:"guild_id,omitempty"`
+	User        *DiscordUser        `json:"user,omitempty"`
+	SortValue   int                 `json:"sort_value,omitempty"`
+	URL         string              `json:"url,omitempty"`
+}
+
+type DiscordBan struct {
+	Reason string      `json:"reason"`
+	User   DiscordUser `json:"user"`
+}
+
+type DiscordInvite struct {
+	Type                     int            `json:"type"`
+	Code                     string         `json:"code"`
+	Guild                    *InviteGuild   `json:"guild,omitempty"`
+	Channel                  *InviteChannel `json:"channel"`
+	Inviter                  *DiscordUser   `json:"inviter,omitempty"`
+	TargetType               int            `json:"target_type,omitempty"`
+	TargetUser               *DiscordUser   `json:"target_user,omitempty"`
+	ApproximatePresenceCount int            `json:"approximate_presence_count,omitempty"`
+	ApproximateMemberCount   int            `json:"approximate_member_count,omitempty"`
+	ExpiresAt                string         `json:"expires_at,omitempty"`
+	Uses                     int            `json:"uses"`
+	MaxUses                  int            `json:"max_uses"`
+	MaxAge                   int            `json:"max_age"`
+	Temporary                bool           `json:"temporary"`
+	CreatedAt                string         `json:"created_at"`
+}
+
+type InviteGuild struct {
+	ID                       snowflake.Snowflake `json:"id"`
+	Name                     string              `json:"name"`
+	Icon                     string              `json:"icon"`
+	Splash                   string              `json:"splash"`
+	Banner                   string              `json:"banner"`
+	Description              string              `json:"description"`
+	Features                 []string            `json:"features"`
+	VerificationLevel        int                 `json:"verification_level"`
+	VanityURLCode            string              `json:"vanity_url_code"`
+	NSFWLevel                int                 `json:"nsfw_level"`
+	PremiumSubscriptionCount int                 `json:"premium_subscription_count"`
+}
+
+type InviteChannel struct {
+	ID   snowflake.Snowflake `json:"id"`
+	Name string              `json:"name"`
+	Type int                 `json:"type"`
+}
+
+func (c *RoleColors) UnmarshalJSON(data []byte) error {
+	type alias RoleColors
+	return unmarshalExtended(data, (*alias)(c), &c.Extensions)
+}
+
+func (c RoleColors) MarshalJSON() ([]byte, error) {
+	type alias RoleColors
+	return marshalWithExtensions(alias(c), c.Extensions)
+}
+
+func (d *IncidentsData) UnmarshalJSON(data []byte) error {
+	type alias IncidentsData
+	return unmarshalExtended(data, (*alias)(d), &d.Extensions)
+}
+
+func (d IncidentsData) MarshalJSON() ([]byte, error) {
+	type alias IncidentsData
+	return marshalWithExtensions(alias(d), d.Extensions)
+}
+
+func (s *InventorySettings) UnmarshalJSON(data []byte) error {
+	type alias InventorySettings
+	return unmarshalExtended(data, (*alias)(s), &s.Extensions)
+}
+
+func (s InventorySettings) MarshalJSON() ([]byte, error) {
+	type alias InventorySettings
+	return marshalWithExtensions(alias(s), s.Extensions)
+}
@@ -0,0 +1,155 @@
+package models
+
+import (
+	"encoding/json"
+
+	"discord-user-api/snowflake"
+)
+
+const (
+	MessageTypeDefault       = 0
+	MessageTypeChannelPinned = 6
+	MessageTypeUserJoin      = 7
+	MessageTypeReply         = 19
+	MessageTypeThreadStarter = 21
+)
+
+const (
+	MessageReferenceTypeDefault = 0
+	MessageReferenceTypeForward = 1
+)
+
+type DiscordMessage struct {
+	ID                snowflake.Snowflake   `json:"id"`
+	ChannelID         snowflake.Snowflake   `json:"channel_id"`
+	GuildID           snowflake.Snowflake   `json:"guild_id,omitempty"`
+	Author            DiscordUser           `json:"author"`
+	Member            *DiscordGuildMember   `json:"member,omitempty"`
+	Content           string                `json:"content"`
+	Timestamp         string                `json:"timestamp"`
+	EditedTimestamp   string                `json:"edited_timestamp,omitempty"`
+	TTS               bool                  `json:"tts"`
+	MentionEveryone   bool                  `json:"mention_everyone"`
+	Mentions          []DiscordUser         `json:"mentions"`
+	MentionRoles      []snowflake.Snowflake `json:"mention_roles"`
+	MentionChannels   []ChannelMention      `json:"mention_channels,omitempty"`
+	Attachments       []Attachment          `json:"attachments"`
+	Embeds            []Embed               `json:"embeds"`
+	Reactions         []Reaction            `json:"reactions,omitempty"`
+	Nonce             json.RawMessage       `json:"nonce,omitempty"`
+	Pinned            bool                  `json:"pinned"`
+	WebhookID         snowflake.Snowflake   `json:"webhook_id,omitempty"`
+	Type              int                   `json:"type"`
+	ApplicationID     snowflake.Snowflake   `json:"application_id,omitempty"`
+	MessageReference  *MessageReference     `json:"message_reference,omitempty"`
+	ReferencedMessage *DiscordMessage       `json:"referenced_message,omitempty"`
+	Flags             int                   `json:"flags"`
+	Thread            *DiscordChannel       `json:"thread,omitempty"`
+	StickerItems      []StickerItem         `json:"sticker_items,omitempty"`
+	Position          int                   `json:"position,omitempty"`
+}
+
+type ChannelMention struct {
+	ID      snowflake.Snowflake `json:"id"`
+	GuildID snowflake.Snowflake `json:"guild_id"`
+	Type    int                 `json:"type"`
+	Name    string              `json:"name"`
+}
+
+type Attachment struct {
+	ID           snowflake.Snowflake `json:"id"`
+	Filename     string              `json:"filename"`
+	Title        string              `json:"title,omitempty"`
+	Description  string              `json:"description,omitempty"`
+	ContentType  string              `json:"content_type,omitempty"`
+	Size         int                 `json:"size"`
+	URL          string              `json:"url"`
+	ProxyURL     string              `json:"proxy_url"`
+	Height       int                 `json:"height,omitempty"`
+	Width        int                 `json:"width,omitempty"`
+	Ephemeral    bool                `json:"ephemeral,omitempty"`
+	DurationSecs float64             `json:"duration_secs,omitempty"`
+	Waveform     string              `json:"waveform,omitempty"`
+	Flags        int                 `json:"flags,omitempty"`
+}
+
+type Embed struct {
+	Title       string         `json:"title,omitempty"`
+	Type        string         `json:"type,omitempty"`
+	Description string         `json:"description,omitempty"`
+	URL         string         `json:"url,omitempty"`
+	Timestamp   string         `json:"timestamp,omitempty"`
+	Color       int            `json:"color,omitempty"`
+	Footer      *EmbedFooter   `json:"footer,omitempty"`
+	Image       *EmbedMedia    `json:"image,omitempty"`
+	Thumbnail   *EmbedMedia    `json:"thumbnail,omitempty"`
+	Video       *EmbedMedia    `json:"video,omitempty"`
+	Provider    *EmbedProvider `json:"provider,omitempty"`
+	Author      *EmbedAuthor   `json:"author,omitempty"`
+	Fields      []EmbedField   `json:"fields,omitempty"`
+}
+
+type EmbedFooter struct {
+	Text         string `json:"text"`
+	IconURL      string `json:"icon_url,omitempty"`
+	ProxyIconURL string `json:"proxy_icon_url,omitempty"`
+}
+
+type EmbedMedia struct {
+	URL      string `json:"url,omitempty"`
+	ProxyURL string `json:"proxy_url,omitempty"`
+	Height   int    `json:"height,omitempty"`
+	Width    int    `json:"width,omitempty"`
+}
+
+type EmbedProvider struct {
+	Name string `json:"name,omitempty"`
+	URL  string `json:"url,omitempty"`
+}
+
+type EmbedAuthor struct {
+	Name         string `json:"name"`
+	URL          string `json:"url,omitempty"`
+	IconURL      string `json:"icon_url,omitempty"`
+	ProxyIconURL string `json:"proxy_icon_url,omitempty"`
+}
+
+type EmbedField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline,omitempty"`
+}
+
+type Reaction struct {
+	Count        int                  `json:"count"`
+	CountDetails ReactionCountDetails `json:"count_details"`
+	Me           bool                 `json:"me"`
+	MeBurst      bool                 `json:"me_burst"`
+	Emoji        PartialEmoji         `json:"emoji"`
+	BurstColors  []string             `json:"burst_colors,omitempty"`
+}
+
+type ReactionCountDetails struct {
+	Burst  int `json:"burst"`
+	Normal int `json:"normal"`
+}
+
+type PartialEmoji struct {
+	ID       snowflake.Snowflake `json:"id,omitempty"`
+	Name     string              `json:"name"`
+	Animated bool                `json:"animated,omitempty"`
+}
+
+type MessageReference struct {
+	Type            int                 `json:"type,omitempty"`
+	MessageID       snowflake.Snowflake `json:"message_id,omitempty"`
+	ChannelID       snowflake.Snowflake `json:"channel_id,omitempty"`
+	GuildID         snowflake.Snowflake `json:"guild_id,omitempty"`
+	FailIfNotExists bool                `json:"fail_if_not_exists,omitempty"`
+}
+
+type StickerItem struct {
+	ID         snowflake.Snowflake `json:"id"`
+	Name       string              `json:"name"`
+	FormatType int                 `json:"format_type"`
+}
@@ -0,0 +1,92 @@
+package models
+
+import "discord-user-api/snowflake"
+
+type AvatarDecorationData struct {
+	Asset      string              `json:"asset"`
+	SKUID      snowflake.Snowflake `json:"sku_id"`
+	ExpiresAt  *int64              `json:"expires_at"`
+	Extensions Extensions          `json:"-"`
+}
+
+type Collectibles struct {
+	Nameplate  *Nameplate `json:"nameplate,omitempty"`
+	Extensions Extensions `json:"-"`
+}
+
+type Nameplate struct {
+	SKUID      snowflake.Snowflake `json:"sku_id"`
+	Asset      string              `json:"asset"`
+	Label      string              `json:"label"`
+	Palette    string              `json:"palette"`
+	ExpiresAt  *int64              `json:"expires_at"`
+	Extensions Extensions          `json:"-"`
+}
+
+// UserPrimaryGuild is the server tag a user displays next to their name.
+// Discord sends it as both primary_guild and the older clan key.
+type UserPrimaryGuild struct {
+	IdentityGuildID snowflake.Snowflake `json:"identity_guild_id"`
+	IdentityEnabled *bool               `json:"identity_enabled"`
+	Tag             string              `json:"tag"`
+	Badge           string              `json:"badge"`
+	Extensions      Extensions          `json:"-"`
+}
+
+type ProfileBadge struct {
+	ID          string     `json:"id"`
+	Description string     `json:"description"`
+	Icon        string     `json:"icon"`
+	Link        string     `json:"link,omitempty"`
+	Extensions  Extensions `json:"-"`
+}
+
+func (d *AvatarDecorationData) UnmarshalJSON(data []byte) error {
+	type alias AvatarDecorationData
+	return unmarshalExtended(data, (*alias)(d), &d.Extensions)
+}
+
+func (d AvatarDecorationData) MarshalJSON() ([]byte, error) {
+	type alias AvatarDecorationData
+	return marshalWithExtensions(alias(d), d.Extensions)
+}
+
+func (c *Collectibles) UnmarshalJSON(data []byte) error {
+	type alias Collectibles
+	return unmarshalExtended(data, (*alias)(c), &c.Extensions)
+}
+
+func (c Collectibles) MarshalJSON() ([]byte, error) {
+	type alias Collectibles
+	return marshalWithExtensions(alias(c), c.Extensions)
+}
+
+func (n *Nameplate) UnmarshalJSON(data []byte) error {
+	type alias Nameplate
+	return unmarshalExtended(data, (*alias)(n), &n.Extensions)
+}
+
+func (n Nameplate) MarshalJSON() ([]byte, error) {
+	type alias Nameplate
+	return marshalWithExtensions(alias(n), n.Extensions)
+}
+
+func (g *UserPrimaryGuild) UnmarshalJSON(data []byte) error {
+	type alias UserPrimaryGuild
+	return unmarshalExtended(data, (*alias)(g), &g.Extensions)
+}
+
+func (g UserPrimaryGuild) MarshalJSON() ([]byte, error) {
+	type alias UserPrimaryGuild
+	return marshalWithExtensions(alias(g), g.Extensions)
+}
+
+func (b *ProfileBadge) UnmarshalJSON(data []byte) error {
+	type alias ProfileBadge
+	return unmarshalExtended(data, (*alias)(b), &b.Extensions)
+}
+
+func (b ProfileBadge) MarshalJSON() ([]byte, error) {
+	type alias ProfileBadge
+	return marshalWithExtensions(alias(b), b.Extensions)
+}
@@ -0,0 +1,286 @@
+package permissions
+
+import (
+	"fmt"
+	"math/bits"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"discord-user-api/models"
+	"discord-user-api/snowflake"
+)
+
+type Permission uint64
+
+const (
+	CreateInstantInvite              Permission = 1 << 0
+	KickMembers                      Permission = 1 << 1
+	BanMembers                       Permission = 1 << 2
+	Administrator                    Permission = 1 << 3
+	ManageChannels                   Permission = 1 << 4
+	ManageGuild                      Permission = 1 << 5
+	AddReactions                     Permission = 1 << 6
+	ViewAuditLog                     Permission = 1 << 7
+	PrioritySpeaker                  Permission = 1 << 8
+	Stream                           Permission = 1 << 9
+	ViewChannel                      Permission = 1 << 10
+	SendMessages                     Permission = 1 << 11
+	SendTTSMessages                  Permission = 1 << 12
+	ManageMessages                   Permission = 1 << 13
+	EmbedLinks                       Permission = 1 << 14
+	AttachFiles                      Permission = 1 << 15
+	ReadMessageHistory               Permission = 1 << 16
+	MentionEveryone                  Permission = 1 << 17
+	UseExternalEmojis                Permission = 1 << 18
+	ViewGuildInsights                Permission = 1 << 19
+	Connect                          Permission = 1 << 20
+	Speak                            Permission = 1 << 21
+	MuteMembers                      Permission = 1 << 22
+	DeafenMembers                    Permission = 1 << 23
+	MoveMembers                      Permission = 1 << 24
+	UseVAD                           Permission = 1 << 25
+	ChangeNickname                   Permission = 1 << 26
+	ManageNicknames                  Permission = 1 << 27
+	ManageRoles                      Permission = 1 << 28
+	ManageWebhooks                   Permission = 1 << 29
+	ManageGuildExpressions           Permission = 1 << 30
+	UseApplicationCommands           Permission = 1 << 31
+	RequestToSpeak                   Permission = 1 << 32
+	ManageEvents                     Permission = 1 << 33
+	ManageThreads                    Permission = 1 << 34
+	CreatePublicThreads              Permission = 1 << 35
+	CreatePrivateThreads             Permission = 1 << 36
+	UseExternalStickers              Permission = 1 << 37
+	SendMessagesInThreads            Permission = 1 << 38
+	UseEmbeddedActivities            Permission = 1 << 39
+	ModerateMembers                  Permission = 1 << 40
+	ViewCreatorMonetizationAnalytics Permission = 1 << 41
+	UseSoundboard                    Permission = 1 << 42
+	CreateGuildExpressions           Permission = 1 << 43
+	CreateEvents                     Permission = 1 << 44
+	UseExternalSounds                Permission = 1 << 45
+	SendVoiceMessages                Permission = 1 << 46
+	SendPolls                        Permission = 1 << 49
+	UseExternalApps                  Permission = 1 << 50
+)
+
+var names = map[Permission]string{
+	CreateInstantInvite:              "CREATE_INSTANT_INVITE",
+	KickMembers:                      "KICK_MEMBERS",
+	BanMembers:                       "BAN_MEMBERS",
+	Administrator:                    "ADMINISTRATOR",
+	ManageChannels:                   "MANAGE_CHANNELS",
+	ManageGuild:                      "MANAGE_GUILD",
+	AddReactions:                     "ADD_REACTIONS",
+	ViewAuditLog:                     "VIEW_AUDIT_LOG",
+	PrioritySpeaker:                  "PRIORITY_SPEAKER",
+	Stream:                           "STREAM",
+	ViewChannel:                      "VIEW_CHANNEL",
+	SendMessages:                     "SEND_MESSAGES",
+	SendTTSMessages:                  "SEND_TTS_MESSAGES",
+	ManageMessages:                   "MANAGE_MESSAGES",
+	EmbedLinks:                       "EMBED_LINKS",
+	AttachFiles:                      "ATTACH_FILES",
+	ReadMessageHistory:               "READ_MESSAGE_HISTORY",
+	MentionEveryone:                  "MENTION_EVERYONE",
+	UseExternalEmojis:                "USE_EXTERNAL_EMOJIS",
+	ViewGuildInsights:                "VIEW_GUILD_INSIGHTS",
+	Connect:                          "CONNECT",
+	Speak:                            "SPEAK",
+	MuteMembers:                      "MUTE_MEMBERS",
+	DeafenMembers:                    "DEAFEN_MEMBERS",
+	MoveMembers:                      "MOVE_MEMBERS",
+	UseVAD:                           "USE_VAD",
+	ChangeNickname:                   "CHANGE_NICKNAME",
+	ManageNicknames:                  "MANAGE_NICKNAMES",
+	ManageRoles:                      "MANAGE_ROLES",
+	ManageWebhooks:                   "MANAGE_WEBHOOKS",
+	ManageGuildExpressions:           "MANAGE_GUILD_EXPRESSIONS",
+	UseApplicationCommands:           "USE_APPLICATION_COMMANDS",
+	RequestToSpeak:                   "REQUEST_TO_SPEAK",
+	ManageEvents:                     "MANAGE_EVENTS",
+	ManageThreads:                    "MANAGE_THREADS",
+	CreatePublicThreads:              "CREATE_PUBLIC_THREADS",
+	CreatePrivateThreads:             "CREATE_PRIVATE_THREADS",
+	UseExternalStickers:              "USE_EXTERNAL_STICKERS",
+	SendMessagesInThreads:            "SEND_MESSAGES_IN_THREADS",
+	UseEmbeddedActivities:            "USE_EMBEDDED_ACTIVITIES",
+	ModerateMembers:                  "MODERATE_MEMBERS",
+	ViewCreatorMonetizationAnalytics: "VIEW_CREATOR_MONETIZATION_ANALYTICS",
+	UseSoundboard:                    "USE_SOUNDBOARD",
+	CreateGuildExpressions:           "CREATE_GUILD_EXPRESSIONS",
+	CreateEvents:                     "CREATE_EVENTS",
+	UseExternalSounds:                "USE_EXTERNAL_SOUNDS",
+	SendVoiceMessages:                "SEND_VOICE_MESSAGES",
+	SendPolls:                        "SEND_POLLS",
+	UseExternalApps:                  "USE_EXTERNAL_APPS",
+}
+
+var byName = func() map[string]Permission {
+	m := make(map[string]Permission, len(names))
+	for permission, name := range names {
+		m[name] = permission
+	}
+	return m
+}()
+
+var All = func() Permission {
+	var all Permission
+	for permission := range names {
+		all |= permission
+	}
+	return all
+}()
+
+const timeoutAllowed = ViewChannel | ReadMessageHistory
+
+func Parse(value string) (Permission, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	parsed, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("geçersiz izin bitfield'ı: %q", value)
+	}
+	return Permission(parsed), nil
+}
+
+func FromName(name string) (Permission, bool) {
+	permission, ok := byName[strings.ToUpper(strings.TrimSpace(name))]
+	return permission, ok
+}
+
+func (p Permission) Has(permission Permission) bool {
+	return p&permission == permission
+}
+
+func (p Permission) String() string {
+	return strconv.FormatUint(uint64(p), 10)
+}
+
+func (p Permission) Names() []string {
+	result := make([]string, 0, bits.OnesCount64(uint64(p)))
+	for permission, name := range names {
+		if p&permission != 0 {
+			result = append(result, name)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+func (p Permission) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(p.String())), nil
+}
+
+func Base(guild *models.DiscordGuild, member *models.DiscordGuildMember) (Permission, error) {
+	if guild.OwnerID.IsValid() && guild.OwnerID == member.User.ID {
+		return All, nil
+	}
+
+	memberRoles := make(map[snowflake.Snowflake]bool, len(member.Roles))
+	for _, roleID := range member.Roles {
+		memberRoles[roleID] = true
+	}
+
+	var permissions Permission
+	for _, role := range guild.Roles {
+		if role.ID != guild.ID && !memberRoles[role.ID] {
+			continue
+		}
+
+		rolePermissions, err := Parse(role.Permissions)
+		if err != nil {
+			return 0, fmt.Errorf("rol %s: %w", role.ID, err)
+		}
+		permissions |= rolePermissions
+	}
+
+	if permissions.Has(Administrator) {
+		return All, nil
+	}
+
+	return applyTimeout(permissions, member), nil
+}
+
+func Channel(guild *models.DiscordGuild, member *models.DiscordGuildMember, channel *models.DiscordChannel) (Permission, error) {
+	base, err := Base(guild, member)
+	if err != nil {
+		return 0, err
+	}
+	if base.Has(Administrator) {
+		return All, nil
+	}
+
+	memberRoles := make(map[snowflake.Snowflake]bool, len(member.Roles))
+	for _, roleID := range member.Roles {
+		memberRoles[roleID] = true
+	}
+
+	permissions := base
+	var roleAllow, roleDeny Permission
+	var memberOverwrite *models.PermissionOverwrite
+
+	for i := range channel.PermissionOverwrites {
+		overwrite := &channel.PermissionOverwrites[i]
+		allow, err := Parse(overwrite.Allow)
+		if err != nil {
+			return 0, fmt.Errorf("overwrite %s: %w", overwrite.ID, err)
+		}
+		deny, err := Parse(overwrite.Deny)
+		if err != nil {
+			return 0, fmt.Errorf("overwrite %s: %w", overwrite.ID, err)
+		}
+
+		switch {
+		case overwrite.Type == models.PermissionOverwriteTypeRole && overwrite.ID == guild.ID:
+			permissions &^= deny
+			permissions |= allow
+		case overwrite.Type == models.PermissionOverwriteTypeRole && memberRoles[overwrite.ID]:
+			roleAllow |= allow
+			roleDeny |= deny
+		case overwrite.Type == models.PermissionOverwriteTypeMember && overwrite.ID == member.User.ID:
+			memberOverwrite = overwrite
+		}
+	}
+
+	permissions &^= roleDeny
+	permissions |= roleAllow
+
+	if memberOverwrite != nil {
+		allow, _ := Parse(memberOverwrite.Allow)
+		deny, _ := Parse(memberOverwrite.Deny)
+		permissions &^= deny
+		permissions |= allow
+	}
+
+	return applyImplicit(applyTimeout(permissions, member)), nil
+}
+
+func applyTimeout(permissions Permission, member *models.DiscordGuildMember) Permission {
+	if member.CommunicationDisabledUntil == "" {
+		return permissions
+	}
+
+	until, err := time.Parse(time.RFC3339, member.CommunicationDisabledUntil)
+	if err != nil || !until.After(time.Now()) {
+		return permissions
+	}
+
+	return permissions & timeoutAllowed
+}
+
+func applyImplicit(permissions Permission) Permission {
+	if !permissions.Has(ViewChannel) {
+		return 0
+	}
+
+	if !permissions.Has(SendMessages) {
+		permissions &^= SendTTSMessages | MentionEveryone | EmbedLinks | AttachFiles
+	}
+
+	return permissions
+}
@@ -0,0 +1,201 @@
+package permissions
+
+import (
+	"testing"
+	"time"
+
+	"discord-user-api/models"
+	"discord-user-api/snowflake"
+)
+
+const (
+	guildID    snowflake.Snowflake = 100
+	ownerID    snowflake.Snowflake = 1
+	userID     snowflake.Snowflake = 2
+	modRole    snowflake.Snowflake = 200
+	adminRole  snowflake.Snowflake = 300
+	helperRole snowflake.Snowflake = 400
+)
+
+const everyone = ViewChannel | SendMessages | ReadMessageHistory | EmbedLinks | AttachFiles
+
+func testGuild() *models.DiscordGuild {
+	return &models.DiscordGuild{
+		ID:      guildID,
+		OwnerID: ownerID,
+		Roles: []models.DiscordRole{
+			{ID: guildID, Name: "@everyone", Permissions: everyone.String()},
+			{ID: modRole, Name: "Moderator", Permissions: (KickMembers | ManageMessages).String()},
+			{ID: adminRole, Name: "Admin", Permissions: Administrator.String()},
+			{ID: helperRole, Name: "Helper", Permissions: "0"},
+		},
+	}
+}
+
+func testMember(id snowflake.Snowflake, timeoutUntil string, roles ...snowflake.Snowflake) *models.DiscordGuildMember {
+	return &models.DiscordGuildMember{
+		User:                       models.DiscordUser{ID: id},
+		Roles:                      roles,
+		CommunicationDisabledUntil: timeoutUntil,
+	}
+}
+
+func roleOverwrite(id snowflake.Snowflake, allow, deny Permission) models.PermissionOverwrite {
+	return models.PermissionOverwrite{ID: id, Type: models.PermissionOverwriteTypeRole, Allow: allow.String(), Deny: deny.String()}
+}
+
+func memberOverwrite(id snowflake.Snowflake, allow, deny Permission) models.PermissionOverwrite {
+	return models.PermissionOverwrite{ID: id, Type: models.PermissionOverwriteTypeMember, Allow: allow.String(), Deny: deny.String()}
+}
+
+func TestBase(t *testing.T) {
+	future := time.Now().Add(time.Hour).Format(time.RFC3339)
+	past := time.Now().Add(-time.Hour).Format(time.RFC3339)
+
+	tests := []struct {
+		name   string
+		member *models.DiscordGuildMember
+		want   Permission
+	}{
+		{"owner gets everything", testMember(ownerID, ""), All},
+		{"everyone role only", testMember(userID, ""), everyone},
+		{"member roles add up", testMember(userID, "", modRole), everyone | KickMembers | ManageMessages},
+		{"unknown roles are ignored", testMember(userID, "", 999), everyone},
+		{"administrator short-circuits", testMember(userID, "", adminRole), All},
+		{"timeout masks permissions", testMember(userID, future, modRole), ViewChannel | ReadMessageHistory},
+		{"expired timeout", testMember(userID, past, modRole), everyone | KickMembers | ManageMessages},
+		{"timeout does not limit administrators", testMember(userID, future, adminRole), All},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Base(testGuild(), tt.member)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("Base = %v, want %v", got.Names(), tt.want.Names())
+			}
+		})
+	}
+}
+
+func TestBaseInvalidRolePermissions(t *testing.T) {
+	guild := testGuild()
+	guild.Roles[1].Permissions = "not-a-number"
+
+	if _, err := Base(guild, testMember(userID, "", modRole)); err == nil {
+		t.Error("geçersiz rol izni hata vermedi")
+	}
+}
+
+func TestChannel(t *testing.T) {
+	future := time.Now().Add(time.Hour).Format(time.RFC3339)
+
+	tests := []struct {
+		name       string
+		member     *models.DiscordGuildMember
+		overwrites []models.PermissionOverwrite
+		want       Permission
+	}{
+		{
+			name:   "no overwrites keeps base permissions",
+			member: testMember(userID, "", modRole),
+			want:   everyone | KickMembers | ManageMessages,
+		},
+		{
+			name:       "everyone overwrite",
+			member:     testMember(userID, ""),
+			overwrites: []models.PermissionOverwrite{roleOverwrite(guildID, AddReactions, ReadMessageHistory)},
+			want:       everyone&^ReadMessageHistory | AddReactions,
+		},
+		{
+			name:   "role allow beats everyone deny",
+			member: testMember(userID, "", modRole),
+			overwrites: []models.PermissionOverwrite{
+				roleOverwrite(modRole, ReadMessageHistory, 0),
+				roleOverwrite(guildID, 0, ReadMessageHistory),
+			},
+			want: everyone | KickMembers | ManageMessages,
+		},
+		{
+			name:   "role allow beats another role's deny",
+			member: testMember(userID, "", modRole, helperRole),
+			overwrites: []models.PermissionOverwrite{
+				roleOverwrite(helperRole, ManageMessages, 0),
+				roleOverwrite(modRole, 0, ManageMessages),
+			},
+			want: everyone | KickMembers | ManageMessages,
+		},
+		{
+			name:       "overwrites for roles the member lacks are ignored",
+			member:     testMember(userID, ""),
+			overwrites: []models.PermissionOverwrite{roleOverwrite(modRole, ManageChannels, ReadMessageHistory)},
+			want:       everyone,
+		},
+		{
+			name:   "member overwrite applies last",
+			member: testMember(userID, "", modRole),
+			overwrites: []models.PermissionOverwrite{
+				memberOverwrite(userID, ReadMessageHistory, KickMembers),
+				roleOverwrite(modRole, 0, ReadMessageHistory),
+			},
+			want: everyone | ManageMessages,
+		},
+		{
+			name:       "other members' overwrites are ignored",
+			member:     testMember(userID, ""),
+			overwrites: []models.PermissionOverwrite{memberOverwrite(3, 0, ViewChannel)},
+			want:       everyone,
+		},
+		{
+			name:       "missing view channel denies everything",
+			member:     testMember(userID, "", modRole),
+			overwrites: []models.PermissionOverwrite{roleOverwrite(guildID, 0, ViewChannel)},
+			want:       0,
+		},
+		{
+			name:       "missing send messages drops message extras",
+			member:     testMember(userID, ""),
+			overwrites: []models.PermissionOverwrite{roleOverwrite(guildID, SendTTSMessages|MentionEveryone, SendMessages)},
+			want:       ViewChannel | ReadMessageHistory,
+		},
+		{
+			name:       "timeout masks channel allows",
+			member:     testMember(userID, future),
+			overwrites: []models.PermissionOverwrite{memberOverwrite(userID, ManageMessages, 0)},
+			want:       ViewChannel | ReadMessageHistory,
+		},
+		{
+			name:       "timeout with view channel denied",
+			member:     testMember(userID, future),
+			overwrites: []models.PermissionOverwrite{roleOverwrite(guildID, 0, ViewChannel)},
+			want:       0,
+		},
+		{
+			name:       "administrator ignores overwrites",
+			member:     testMember(userID, "", adminRole),
+			overwrites: []models.PermissionOverwrite{memberOverwrite(userID, 0, ViewChannel)},
+			want:       All,
+		},
+		{
+			name:       "owner ignores overwrites",
+			member:     testMember(ownerID, ""),
+			overwrites: []models.PermissionOverwrite{roleOverwrite(guildID, 0, ViewChannel)},
+			want:       All,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			channel := &models.DiscordChannel{ID: 500, GuildID: guildID, PermissionOverwrites: tt.overwrites}
+			got, err := Channel(testGuild(), tt.member, channel)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("Channel = %v, want %v", got.Names(), tt.want.Names())
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+
+	"discord-user-api/cdn"
+	"discord-user-api/models"
+	"discord-user-api/snowflake"
+)
+
+type assetURLs struct {
+	opts cdn.Options
+}
+
+func (s *Server) parseAssetOptions(w http.ResponseWriter, r *http.Request) (*assetURLs, bool) {
+	query := r.URL.Query()
+	if query.Get("include_urls") != "true" {
+		return nil, true
+	}
+
+	var opts cdn.Options
+	if sizeStr := query.Get("image_size"); sizeStr != "" {
+		size, err := strconv.Atoi(sizeStr)
+		if err != nil {
+			s.sendError(w, "Invalid image_size parameter", http.StatusBadRequest)
+			return nil, false
+		}
+		opts.Size = size
+	}
+
+	format, err := cdn.ParseFormat(query.Get("image_format"))
+	if err != nil {
+		s.sendError(w, err.Error(), http.StatusBadRequest)
+		return nil, false
+	}
+	opts.Format = format
+
+	if err := opts.Validate(); err != nil {
+		s.sendError(w, err.Error(), http.StatusBadRequest)
+		return nil, false
+	}
+
+	return &assetURLs{opts: opts}, true
+}
+
+func (a *assetURLs) apply(guildID snowflake.Snowflake, data interface{}) interface{} {
+	if a == nil {
+		return data
+	}
+
+	switch value := data.(type) {
+	case *models.DiscordGuild:
+		guild := a.guild(*value)
+		return &guild
+	case []models.DiscordGuild:
+		guilds := make([]models.DiscordGuild, len(value))
+		for i := range value {
+			guilds[i] = a.guild(value[i])
+		}
+		return guilds
+	case *models.DiscordProfile:
+		profile := *value
+		profile.User = a.user(profile.User)
+		return &profile
+	case *models.DiscordGuildMember:
+		member := a.member(guildID, *value)
+		return &member
+	case []models.DiscordGuildMember:
+		members := make([]models.DiscordGuildMember, len(value))
+		for i := range value {
+			members[i] = a.member(guildID, value[i])
+		}
+		return members
+	case []models.DiscordRole:
+		return a.roles(value)
+	case []models.DiscordEmoji:
+		return a.emojis(value)
+	case []models.DiscordSticker:
+		stickers := make([]models.DiscordSticker, len(value))
+		for i, sticker := range value {
+			sticker.URL = cdn.Sticker(sticker.ID, sticker.FormatType, a.opts)
+			stickers[i] = sticker
+		}
+		return stickers
+	case []models.DiscordBan:
+		bans := make([]models.DiscordBan, len(value))
+		for i, ban := range value {
+			ban.User = a.user(ban.User)
+			bans[i] = ban
+		}
+		return bans
+	}
+
+	return data
+}
+
+func (a *assetURLs) user(user models.DiscordUser) models.DiscordUser {
+	user.AvatarURL = cdn.UserAvatar(user.ID, user.Discriminator, user.Avatar, a.opts)
+	user.BannerURL = cdn.UserBanner(user.ID, user.Banner, a.opts)
+	return user
+}
+
+func (a *assetURLs) member(guildID snowflake.Snowflake, member models.DiscordGuildMember) models.DiscordGuildMember {
+	member.User = a.user(member.User)
+	if guildID.IsValid() {
+		member.AvatarURL = cdn.GuildMemberAvatar(guildID, member.User.ID, member.Avatar, a.opts)
+	}
+	return member
+}
+
+func (a *assetURLs) guild(guild models.DiscordGuild) models.DiscordGuild {
+	guild.IconURL = cdn.GuildIcon(guild.ID, guild.Icon, a.opts)
+	guild.SplashURL = cdn.GuildSplash(guild.ID, guild.Splash, a.opts)
+	guild.DiscoverySplashURL = cdn.GuildDiscoverySplash(guild.ID, guild.DiscoverySplash, a.opts)
+	guild.BannerURL = cdn.GuildBanner(guild.ID, guild.Banner, a.opts)
+
+	if len(guild.Roles) > 0 {
+		guild.Roles = a.roles(guild.Roles)
+	}
+
+	if len(guild.Emojis) > 0 {
+		guild.Emojis = a.emojis(guild.Emojis)
+	}
+
+	return guild
+}
+
+func (a *assetURLs) roles(value []models.DiscordRole) []models.DiscordRole {
+	roles := make([]models.DiscordRole, len(value))
+	for i, role := range value {
+		role.IconURL = cdn.RoleIcon(role.ID, role.Icon, a.opts)
+		roles[i] = role
+	}
+	return roles
+}
+
+func (a *assetURLs) emojis(value []models.DiscordEmoji) []models.DiscordEmoji {
+	emojis := make([]models.DiscordEmoji, len(value))
+	for i, emoji := range value {
+		emoji.URL = cdn.Emoji(emoji.ID, emoji.Animated, a.opts)
+		emojis[i] = emoji
+	}
+	return emojis
+}
@@ -0,0 +1,102 @@
+package server
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"discord-user-api/discord"
+	"discord-user-api/models"
+	"discord-user-api/snowflake"
+)
+
+func (s *Server) handleGuildAuditLog(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		s.sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ctx, cacheStatus := discord.WithCacheStatus(r.Context())
+
+	guildID, ok := s.parseSnowflake(w, r.PathValue("guild_id"), "guild ID")
+	if !ok {
+		return
+	}
+
+	query := discord.AuditLogQuery{Limit: 50}
+	filters := map[string]*snowflake.Snowflake{
+		"user_id": &query.UserID,
+		"before":  &query.Before,
+		"after":   &query.After,
+	}
+	for name, target := range filters {
+		id, err := snowflake.ParseOptional(r.URL.Query().Get(name))
+		if err != nil {
+			s.sendError(w, fmt.Sprintf("Invalid %s format", name), http.StatusBadRequest)
+			return
+		}
+		*target = id
+	}
+
+	if actionType := r.URL.Query().Get("action_type"); actionType != "" {
+		event, err := models.ParseAuditLogEvent(actionType)
+		if err != nil {
+			s.sendError(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		query.ActionType = event
+	}
+
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		val, err := strconv.Atoi(limitStr)
+		if err != nil || val < 1 || val > 100 {
+			s.sendError(w, "Invalid limit (1-100)", http.StatusBadRequest)
+			return
+		}
+		query.Limit = val
+	}
+
+	auditLog, err := s.discord.GetGuildAuditLog(ctx, guildID, query)
+	if err != nil {
+		log.Printf("❌ Audit log getirme hatası: %v", err)
+		s.sendUpstreamError(w, "Audit log getirilemedi", err)
+		return
+	}
+
+	entries := auditLog.AuditLogEntries
+	pagination := &models.Pagination{
+		Limit:   query.Limit,
+		Before:  query.Before.String(),
+		After:   query.After.String(),
+		HasMore: len(entries) == query.Limit,
+	}
+	if pagination.HasMore {
+		pagination.Next = auditLogCursor(entries, query.After.IsValid()).String()
+	}
+
+	response := models.APIResponse{
+		Success:    true,
+		Data:       auditLog,
+		Count:      len(entries),
+		Pagination: pagination,
+		Timestamp:  time.Now().UTC().Format(time.RFC3339),
+		RateLimit:  s.discord.GetRateLimitInfo(),
+	}
+
+	s.applyCacheStatus(w, &response, cacheStatus)
+	s.sendJSONResponse(w, response, http.StatusOK)
+}
+
+// auditLogCursor picks the entry ID to continue from: the newest one when
+// paging forward with after, otherwise the oldest one for before.
+func auditLogCursor(entries []models.AuditLogEntry, forward bool) snowflake.Snowflake {
+	cursor := entries[0].ID
+	for _, entry := range entries[1:] {
+		if (forward && entry.ID > cursor) || (!forward && entry.ID < cursor) {
+			cursor = entry.ID
+		}
+	}
+	return cursor
+}
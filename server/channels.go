@@ -0,0 +1,295 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"discord-user-api/discord"
+	"discord-user-api/models"
+	"discord-user-api/snowflake"
+)
+
+func (s *Server) handleGuildChannels(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		s.sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ctx, cacheStatus := discord.WithCacheStatus(r.Context())
+
+	guildID, ok := s.parseSnowflake(w, r.PathValue("guild_id"), "guild ID")
+	if !ok {
+		return
+	}
+
+	channels, err := s.discord.GetGuildChannels(ctx, guildID)
+	if err != nil {
+		log.Printf("❌ Guild kanalları getirme hatası: %v", err)
+		s.sendUpstreamError(w, "Guild kanalları getirilemedi", err)
+		return
+	}
+
+	response := models.APIResponse{
+		Success:   true,
+		Data:      channels,
+		Count:     len(channels),
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+		RateLimit: s.discord.GetRateLimitInfo(),
+	}
+
+	s.applyCacheStatus(w, &response, cacheStatus)
+	s.sendJSONResponse(w, response, http.StatusOK)
+}
+
+func (s *Server) handleGuildChannelsRefresh(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		s.sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	guildID, ok := s.parseSnowflake(w, r.PathValue("guild_id"), "guild ID")
+	if !ok {
+		return
+	}
+
+	channels, err := s.discord.RefreshGuildChannels(r.Context(), guildID)
+	if err != nil {
+		log.Printf("❌ Guild kanalları yenileme hatası: %v", err)
+		s.sendUpstreamError(w, "Guild kanalları yenilenemedi", err)
+		return
+	}
+
+	s.wsManager.BroadcastToGuild(guildID.String(), "channels_refreshed", map[string]interface{}{
+		"guild_id": guildID,
+		"channels": channels,
+		"message":  "Guild kanalları başarıyla yenilendi",
+	})
+
+	response := models.APIResponse{
+		Success:   true,
+		Message:   fmt.Sprintf("Guild kanalları başarıyla yenilendi: %s", guildID),
+		Count:     len(channels),
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+	}
+
+	s.sendJSONResponse(w, response, http.StatusOK)
+}
+
+func (s *Server) handleChannelByID(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		s.sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ctx, cacheStatus := discord.WithCacheStatus(r.Context())
+
+	channelID, ok := s.parseSnowflake(w, r.PathValue("channel_id"), "channel ID")
+	if !ok {
+		return
+	}
+
+	channel, err := s.discord.GetChannel(ctx, channelID)
+	if err != nil {
+		log.Printf("❌ Kanal getirme hatası: %v", err)
+		s.sendUpstreamError(w, "Kanal getirilemedi", err)
+		return
+	}
+
+	response := models.APIResponse{
+		Success:   true,
+		Data:      channel,
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+		RateLimit: s.discord.GetRateLimitInfo(),
+	}
+
+	s.applyCacheStatus(w, &response, cacheStatus)
+	s.sendJSONResponse(w, response, http.StatusOK)
+}
+
+func (s *Server) handleChannelRefresh(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		s.sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	channelID, ok := s.parseSnowflake(w, r.PathValue("channel_id"), "channel ID")
+	if !ok {
+		return
+	}
+
+	channel, err := s.discord.RefreshChannel(r.Context(), channelID)
+	if err != nil {
+		log.Printf("❌ Kanal yenileme hatası: %v", err)
+		s.sendUpstreamError(w, "Kanal yenilenemedi", err)
+		return
+	}
+
+	// DM and group DM channels have no guild room to broadcast to.
+	if channel.GuildID.IsValid() {
+		s.wsManager.BroadcastToGuild(channel.GuildID.String(), "channel_refreshed", map[string]interface{}{
+			"guild_id":   channel.GuildID,
+			"channel_id": channelID,
+			"channel":    channel,
+			"message":    "Kanal başarıyla yenilendi",
+		})
+	}
+
+	response := models.APIResponse{
+		Success:   true,
+		Message:   fmt.Sprintf("Kanal başarıyla yenilendi: %s", channelID),
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+	}
+
+	s.sendJSONResponse(w, response, http.StatusOK)
+}
+
+func (s *Server) handleChannelMessages(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		s.sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ctx, cacheStatus := discord.WithCacheStatus(r.Context())
+
+	channelID, ok := s.parseSnowflake(w, r.PathValue("channel_id"), "channel ID")
+	if !ok {
+		return
+	}
+
+	query := discord.MessageQuery{Limit: 50}
+	cursors := map[string]*snowflake.Snowflake{
+		"before": &query.Before,
+		"after":  &query.After,
+		"around": &query.Around,
+	}
+	for name, target := range cursors {
+		cursor, err := snowflake.ParseOptional(r.URL.Query().Get(name))
+		if err != nil {
+			s.sendError(w, fmt.Sprintf("Invalid %s cursor format", name), http.StatusBadRequest)
+			return
+		}
+		*target = cursor
+	}
+
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		val, err := strconv.Atoi(limitStr)
+		if err != nil || val < 1 || val > 100 {
+			s.sendError(w, "Invalid limit (1-100)", http.StatusBadRequest)
+			return
+		}
+		query.Limit = val
+	}
+
+	messages, err := s.discord.GetChannelMessages(ctx, channelID, query)
+	if errors.Is(err, discord.ErrConflictingCursors) {
+		s.sendError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		log.Printf("❌ Kanal mesajları getirme hatası: %v", err)
+		s.sendUpstreamError(w, "Kanal mesajları getirilemedi", err)
+		return
+	}
+
+	pagination := &models.Pagination{
+		Limit:   query.Limit,
+		Before:  query.Before.String(),
+		After:   query.After.String(),
+		HasMore: len(messages) == query.Limit && !query.Around.IsValid(),
+	}
+	if pagination.HasMore {
+		if query.After.IsValid() {
+			pagination.Next = messages[0].ID.String()
+		} else {
+			pagination.Next = messages[len(messages)-1].ID.String()
+		}
+	}
+
+	response := models.APIResponse{
+		Success:    true,
+		Data:       messages,
+		Count:      len(messages),
+		Pagination: pagination,
+		Timestamp:  time.Now().UTC().Format(time.RFC3339),
+		RateLimit:  s.discord.GetRateLimitInfo(),
+	}
+
+	s.applyCacheStatus(w, &response, cacheStatus)
+	s.sendJSONResponse(w, response, http.StatusOK)
+}
+
+func (s *Server) handleChannelMessagesExport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		s.sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	channelID, ok := s.parseSnowflake(w, r.PathValue("channel_id"), "channel ID")
+	if !ok {
+		return
+	}
+
+	var opts discord.MessageExportOptions
+	var err error
+	if opts.Before, err = snowflake.ParseOptional(r.URL.Query().Get("before")); err != nil {
+		s.sendError(w, "Invalid before cursor format", http.StatusBadRequest)
+		return
+	}
+	if opts.After, err = snowflake.ParseOptional(r.URL.Query().Get("after")); err != nil {
+		s.sendError(w, "Invalid after cursor format", http.StatusBadRequest)
+		return
+	}
+
+	if maxStr := r.URL.Query().Get("max"); maxStr != "" {
+		val, err := strconv.Atoi(maxStr)
+		if err != nil || val < 0 {
+			s.sendError(w, "Invalid max parameter", http.StatusBadRequest)
+			return
+		}
+		opts.MaxMessages = val
+	}
+
+	if opts.Before.IsValid() && opts.After.IsValid() {
+		s.sendError(w, discord.ErrConflictingCursors.Error(), http.StatusBadRequest)
+		return
+	}
+
+	controller := http.NewResponseController(w)
+	controller.SetWriteDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+
+	encoder := json.NewEncoder(w)
+	progress, err := s.discord.ExportChannelMessages(r.Context(), channelID, opts, func(page []models.DiscordMessage, progress discord.MessageExportProgress) error {
+		for i := range page {
+			if err := encoder.Encode(page[i]); err != nil {
+				return err
+			}
+		}
+		controller.Flush()
+		return nil
+	})
+
+	trailer := map[string]interface{}{
+		"type":       "export_complete",
+		"channel_id": channelID,
+		"pages":      progress.Pages,
+		"messages":   progress.Messages,
+		"cursor":     progress.Cursor,
+		"timestamp":  time.Now().UTC().Format(time.RFC3339),
+	}
+	if err != nil {
+		log.Printf("❌ Kanal geçmişi dışa aktarma hatası: %v", err)
+		trailer["type"] = "export_error"
+		trailer["error"] = err.Error()
+	}
+
+	encoder.Encode(trailer)
+	controller.Flush()
+}
@@ -0,0 +1,127 @@
+package server
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"discord-user-api/discord"
+	"discord-user-api/models"
+	"discord-user-api/permissions"
+	"discord-user-api/snowflake"
+)
+
+func (s *Server) handleMemberPermissions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		s.sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ctx, cacheStatus := discord.WithCacheStatus(r.Context())
+
+	guildID, ok := s.parseSnowflake(w, r.PathValue("guild_id"), "guild ID")
+	if !ok {
+		return
+	}
+	userID, ok := s.parseSnowflake(w, r.PathValue("user_id"), "user ID")
+	if !ok {
+		return
+	}
+	channelID, err := snowflake.ParseOptional(r.URL.Query().Get("channel_id"))
+	if err != nil {
+		s.sendError(w, "Invalid channel ID format", http.StatusBadRequest)
+		return
+	}
+
+	var checks []permissions.Permission
+	var checkNames []string
+	if checkParam := r.URL.Query().Get("check"); checkParam != "" {
+		for _, name := range strings.Split(checkParam, ",") {
+			permission, ok := permissions.FromName(name)
+			if !ok {
+				s.sendError(w, fmt.Sprintf("Unknown permission: %s", strings.TrimSpace(name)), http.StatusBadRequest)
+				return
+			}
+			checks = append(checks, permission)
+			checkNames = append(checkNames, strings.ToUpper(strings.TrimSpace(name)))
+		}
+	}
+
+	guild, err := s.discord.GetGuild(ctx, guildID)
+	if err != nil {
+		log.Printf("❌ Guild getirme hatası: %v", err)
+		s.sendUpstreamError(w, "Guild getirilemedi", err)
+		return
+	}
+
+	member, err := s.discord.GetGuildMember(ctx, guildID, userID)
+	if err != nil {
+		log.Printf("❌ Guild üyesi getirme hatası: %v", err)
+		s.sendUpstreamError(w, "Guild üyesi getirilemedi", err)
+		return
+	}
+
+	var effective permissions.Permission
+	if !channelID.IsValid() {
+		effective, err = permissions.Base(guild, member)
+	} else {
+		channel, channelErr := s.discord.GetChannel(ctx, channelID)
+		if channelErr != nil {
+			log.Printf("❌ Kanal getirme hatası: %v", channelErr)
+			s.sendUpstreamError(w, "Kanal getirilemedi", channelErr)
+			return
+		}
+		if channel.GuildID != guildID {
+			s.sendError(w, "Channel does not belong to this guild", http.StatusBadRequest)
+			return
+		}
+		if channel.IsThread() && channel.ParentID.IsValid() {
+			channel, channelErr = s.discord.GetChannel(ctx, channel.ParentID)
+			if channelErr != nil {
+				log.Printf("❌ Üst kanal getirme hatası: %v", channelErr)
+				s.sendUpstreamError(w, "Üst kanal getirilemedi", channelErr)
+				return
+			}
+		}
+		effective, err = permissions.Channel(guild, member, channel)
+	}
+	if err != nil {
+		log.Printf("❌ İzin hesaplama hatası: %v", err)
+		s.sendError(w, fmt.Sprintf("İzinler hesaplanamadı: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	data := map[string]interface{}{
+		"guild_id":      guildID,
+		"user_id":       userID,
+		"owner":         guild.OwnerID == userID,
+		"administrator": effective.Has(permissions.Administrator),
+		"permissions":   effective,
+		"names":         effective.Names(),
+	}
+	if channelID.IsValid() {
+		data["channel_id"] = channelID
+	}
+	if len(checks) > 0 {
+		results := make(map[string]bool, len(checks))
+		allowed := true
+		for i, permission := range checks {
+			results[checkNames[i]] = effective.Has(permission)
+			allowed = allowed && results[checkNames[i]]
+		}
+		data["checks"] = results
+		data["allowed"] = allowed
+	}
+
+	response := models.APIResponse{
+		Success:   true,
+		Data:      data,
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+		RateLimit: s.discord.GetRateLimitInfo(),
+	}
+
+	s.applyCacheStatus(w, &response, cacheStatus)
+	s.sendJSONResponse(w, response, http.StatusOK)
+}
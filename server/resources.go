@@ -0,0 +1,234 @@
+package server
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"discord-user-api/discord"
+	"discord-user-api/models"
+	"discord-user-api/snowflake"
+)
+
+func (s *Server) handleGuildRoles(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		s.sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ctx, cacheStatus := discord.WithCacheStatus(r.Context())
+
+	guildID, ok := s.parseSnowflake(w, r.PathValue("guild_id"), "guild ID")
+	if !ok {
+		return
+	}
+
+	assets, ok := s.parseAssetOptions(w, r)
+	if !ok {
+		return
+	}
+
+	roles, err := s.discord.GetGuildRoles(ctx, guildID)
+	if err != nil {
+		log.Printf("❌ Guild rolleri getirme hatası: %v", err)
+		s.sendUpstreamError(w, "Guild rolleri getirilemedi", err)
+		return
+	}
+
+	response := models.APIResponse{
+		Success:   true,
+		Data:      assets.apply(guildID, roles),
+		Count:     len(roles),
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+		RateLimit: s.discord.GetRateLimitInfo(),
+	}
+
+	s.applyCacheStatus(w, &response, cacheStatus)
+	s.sendJSONResponse(w, response, http.StatusOK)
+}
+
+func (s *Server) handleGuildEmojis(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		s.sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ctx, cacheStatus := discord.WithCacheStatus(r.Context())
+
+	guildID, ok := s.parseSnowflake(w, r.PathValue("guild_id"), "guild ID")
+	if !ok {
+		return
+	}
+
+	assets, ok := s.parseAssetOptions(w, r)
+	if !ok {
+		return
+	}
+
+	emojis, err := s.discord.GetGuildEmojis(ctx, guildID)
+	if err != nil {
+		log.Printf("❌ Guild emojileri getirme hatası: %v", err)
+		s.sendUpstreamError(w, "Guild emojileri getirilemedi", err)
+		return
+	}
+
+	response := models.APIResponse{
+		Success:   true,
+		Data:      assets.apply(guildID, emojis),
+		Count:     len(emojis),
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+		RateLimit: s.discord.GetRateLimitInfo(),
+	}
+
+	s.applyCacheStatus(w, &response, cacheStatus)
+	s.sendJSONResponse(w, response, http.StatusOK)
+}
+
+func (s *Server) handleGuildStickers(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		s.sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ctx, cacheStatus := discord.WithCacheStatus(r.Context())
+
+	guildID, ok := s.parseSnowflake(w, r.PathValue("guild_id"), "guild ID")
+	if !ok {
+		return
+	}
+
+	assets, ok := s.parseAssetOptions(w, r)
+	if !ok {
+		return
+	}
+
+	stickers, err := s.discord.GetGuildStickers(ctx, guildID)
+	if err != nil {
+		log.Printf("❌ Guild stickerları getirme hatası: %v", err)
+		s.sendUpstreamError(w, "Guild stickerları getirilemedi", err)
+		return
+	}
+
+	response := models.APIResponse{
+		Success:   true,
+		Data:      assets.apply(guildID, stickers),
+		Count:     len(stickers),
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+		RateLimit: s.discord.GetRateLimitInfo(),
+	}
+
+	s.applyCacheStatus(w, &response, cacheStatus)
+	s.sendJSONResponse(w, response, http.StatusOK)
+}
+
+func (s *Server) handleGuildBans(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		s.sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ctx, cacheStatus := discord.WithCacheStatus(r.Context())
+
+	guildID, ok := s.parseSnowflake(w, r.PathValue("guild_id"), "guild ID")
+	if !ok {
+		return
+	}
+
+	assets, ok := s.parseAssetOptions(w, r)
+	if !ok {
+		return
+	}
+
+	query := discord.BanQuery{Limit: 1000}
+	cursors := map[string]*snowflake.Snowflake{
+		"before": &query.Before,
+		"after":  &query.After,
+	}
+	for name, target := range cursors {
+		cursor, err := snowflake.ParseOptional(r.URL.Query().Get(name))
+		if err != nil {
+			s.sendError(w, fmt.Sprintf("Invalid %s cursor format", name), http.StatusBadRequest)
+			return
+		}
+		*target = cursor
+	}
+
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		val, err := strconv.Atoi(limitStr)
+		if err != nil || val < 1 || val > 1000 {
+			s.sendError(w, "Invalid limit (1-1000)", http.StatusBadRequest)
+			return
+		}
+		query.Limit = val
+	}
+
+	bans, err := s.discord.GetGuildBans(ctx, guildID, query)
+	if err != nil {
+		log.Printf("❌ Guild banları getirme hatası: %v", err)
+		s.sendUpstreamError(w, "Guild banları getirilemedi", err)
+		return
+	}
+
+	pagination := &models.Pagination{
+		Limit:   query.Limit,
+		Before:  query.Before.String(),
+		After:   query.After.String(),
+		HasMore: len(bans) == query.Limit,
+	}
+	if pagination.HasMore {
+		forward := !query.Before.IsValid()
+		cursor := bans[0].User.ID
+		for _, ban := range bans[1:] {
+			if (forward && ban.User.ID > cursor) || (!forward && ban.User.ID < cursor) {
+				cursor = ban.User.ID
+			}
+		}
+		pagination.Next = cursor.String()
+	}
+
+	response := models.APIResponse{
+		Success:    true,
+		Data:       assets.apply(guildID, bans),
+		Count:      len(bans),
+		Pagination: pagination,
+		Timestamp:  time.Now().UTC().Format(time.RFC3339),
+		RateLimit:  s.discord.GetRateLimitInfo(),
+	}
+
+	s.applyCacheStatus(w, &response, cacheStatus)
+	s.sendJSONResponse(w, response, http.StatusOK)
+}
+
+func (s *Server) handleGuildInvites(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		s.sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ctx, cacheStatus := discord.WithCacheStatus(r.Context())
+
+	guildID, ok := s.parseSnowflake(w, r.PathValue("guild_id"), "guild ID")
+	if !ok {
+		return
+	}
+
+	invites, err := s.discord.GetGuildInvites(ctx, guildID)
+	if err != nil {
+		log.Printf("❌ Guild davetleri getirme hatası: %v", err)
+		s.sendUpstreamError(w, "Guild davetleri getirilemedi", err)
+		return
+	}
+
+	response := models.APIResponse{
+		Success:   true,
+		Data:      invites,
+		Count:     len(invites),
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+		RateLimit: s.discord.GetRateLimitInfo(),
+	}
+
+	s.applyCacheStatus(w, &response, cacheStatus)
+	s.sendJSONResponse(w, response, http.StatusOK)
+}
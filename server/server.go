@@ -1,503 +1,827 @@
-package server
-
-import (
-	"encoding/json"
-	"fmt"
-	"log"
-	"net/http"
-	"strconv"
-	"time"
-
-	"discord-user-api/cache"
-	"discord-user-api/config"
-	"discord-user-api/discord"
-	"discord-user-api/middleware"
-	"discord-user-api/models"
-	"discord-user-api/websocket"
-)
-
-type Server struct {
-	config     *config.Config
-	discord    *discord.Client
-	cache      *cache.Cache
-	rateLimiter *middleware.RateLimiter
-	wsManager  *websocket.WebSocketManager
-}
-
-func NewServer(cfg *config.Config, discordClient *discord.Client, cache *cache.Cache) *Server {
-	rateLimiter := middleware.CreateRateLimiter(cfg)
-	wsManager := websocket.NewWebSocketManager()
-	
-	server := &Server{
-		config:      cfg,
-		discord:     discordClient,
-		cache:       cache,
-		rateLimiter: rateLimiter,
-		wsManager:   wsManager,
-	}
-
-	cache.SetWebSocketManager(wsManager)
-	
-	go wsManager.Start()
-	
-	cache.StartAutoRefresh()
-
-	log.Printf("🚀 HTTP Server başlatıldı")
-	return server
-}
-
-func (s *Server) Start() error {
-	middlewareChain := middleware.Compose(
-		middleware.Recovery,
-		middleware.Security,
-		middleware.RequestID,
-		middleware.Logging,
-		middleware.CORS,
-	)
-
-	if s.rateLimiter != nil {
-		middlewareChain = middleware.Compose(
-			middleware.Recovery,
-			middleware.Security,
-			middleware.RequestID,
-			middleware.Logging,
-			middleware.CORS,
-			middleware.RateLimit(s.rateLimiter),
-		)
-	}
-
-	http.HandleFunc("/", middlewareChain(s.handleRoot))
-	http.HandleFunc("/guilds", middlewareChain(s.handleGuilds))
-	http.HandleFunc("/users", middlewareChain(s.handleUsers))
-	http.HandleFunc("/guilds/", middlewareChain(s.handleGuildByID))
-	http.HandleFunc("/guilds/members", middlewareChain(s.handleGuildMembers))
-	http.HandleFunc("/guilds/refresh", middlewareChain(s.handleGuildRefresh))
-	http.HandleFunc("/guilds/members/refresh", middlewareChain(s.handleGuildMembersRefresh))
-	http.HandleFunc("/health", middlewareChain(s.handleHealth))
-	http.HandleFunc("/stats", middlewareChain(s.handleStats))
-	http.HandleFunc("/cache/clear", middlewareChain(s.handleCacheClear))
-	http.HandleFunc("/cache/stats", middlewareChain(s.handleCacheStats))
-	http.HandleFunc("/websocket", s.wsManager.HandleWebSocket)
-	http.HandleFunc("/websocket/stats", middlewareChain(s.handleWebSocketStats))
-
-	server := &http.Server{
-		Addr:         s.config.Server.Host + ":" + s.config.Server.Port,
-		ReadTimeout:  s.config.Server.ReadTimeout,
-		WriteTimeout: s.config.Server.WriteTimeout,
-		IdleTimeout:  s.config.Server.IdleTimeout,
-	}
-
-	log.Printf("🌐 Server başlatılıyor: http://%s:%s", s.config.Server.Host, s.config.Server.Port)
-	log.Printf("📋 Endpoints:")
-	log.Printf("   GET  /                    - API bilgileri")
-	log.Printf("   GET  /guilds              - Tüm guild'ler")
-	log.Printf("   GET  /guilds?id=<id>      - Belirli guild")
-	log.Printf("   GET  /users?id=<id>       - Kullanıcı profili")
-	log.Printf("   GET  /guilds/members?guild_id=<id>&limit=<limit> - Guild üyeleri")
-	log.Printf("   POST /guilds/refresh?guild_id=<id> - Guild'i yenile")
-	log.Printf("   POST /guilds/members/refresh?guild_id=<id>&limit=<limit> - Üyeleri yenile")
-	log.Printf("   GET  /health              - Sağlık kontrolü")
-	log.Printf("   GET  /stats               - İstatistikler")
-	log.Printf("   POST /cache/clear         - Cache temizle")
-	log.Printf("   GET  /cache/stats         - Cache istatistikleri")
-	log.Printf("   WS   /websocket           - WebSocket bağlantısı")
-	log.Printf("   GET  /websocket/stats     - WebSocket istatistikleri")
-
-	return server.ListenAndServe()
-}
-
-func (s *Server) handleRoot(w http.ResponseWriter, r *http.Request) {
-	if r.URL.Path != "/" {
-		http.NotFound(w, r)
-		return
-	}
-
-	response := models.APIResponse{
-		Success: true,
-		Data: map[string]interface{}{
-			"name":        "Discord API Server - Professional Edition",
-			"version":     "2.0.0",
-			"description": "Profesyonel Discord API sunucusu - Gerçek zamanlı güncellemeler ile",
-			"features": []string{
-				"🔐 Güvenli API",
-				"💾 Akıllı Cache",
-				"🔄 Retry Logic",
-				"📊 İstatistikler",
-				"🛡️ Middleware",
-				"⚡ Performans",
-				"🎯 Türkçe Loglar",
-				"🔌 WebSocket Desteği",
-				"🔄 Otomatik Yenileme",
-			},
-			"endpoints": map[string]string{
-				"guilds":         "/guilds",
-				"guilds_filter":  "/guilds?id=<guild_id>",
-				"users":          "/users?id=<user_id>",
-				"guild_members":  "/guilds/members?guild_id=<guild_id>&limit=<limit>",
-				"guild_refresh":  "/guilds/refresh?guild_id=<guild_id>",
-				"members_refresh": "/guilds/members/refresh?guild_id=<guild_id>&limit=<limit>",
-				"health":         "/health",
-				"stats":          "/stats",
-				"cache_clear":    "/cache/clear",
-				"cache_stats":    "/cache/stats",
-				"websocket":      "/websocket",
-				"websocket_stats": "/websocket/stats",
-			},
-		},
-		Timestamp: time.Now().UTC().Format(time.RFC3339),
-	}
-
-	s.sendJSONResponse(w, response, http.StatusOK)
-}
-
-func (s *Server) handleGuilds(w http.ResponseWriter, r *http.Request) {
-	if r.Method != "GET" {
-		s.sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
-	guildID := r.URL.Query().Get("id")
-
-	if guildID != "" {
-		guild, err := s.discord.GetGuild(guildID)
-		if err != nil {
-			log.Printf("❌ Guild getirme hatası: %v", err)
-			s.sendError(w, fmt.Sprintf("Guild bulunamadı: %v", err), http.StatusNotFound)
-			return
-		}
-
-		response := models.APIResponse{
-			Success:   true,
-			Data:      guild,
-			Timestamp: time.Now().UTC().Format(time.RFC3339),
-		}
-
-		s.sendJSONResponse(w, response, http.StatusOK)
-		return
-	}
-
-	guilds, err := s.discord.GetGuilds()
-	if err != nil {
-		log.Printf("❌ Guild'ler getirme hatası: %v", err)
-		s.sendError(w, fmt.Sprintf("Guild'ler getirilemedi: %v", err), http.StatusInternalServerError)
-		return
-	}
-
-	response := models.APIResponse{
-		Success:   true,
-		Data:      guilds,
-		Count:     len(guilds),
-		Timestamp: time.Now().UTC().Format(time.RFC3339),
-		RateLimit: s.discord.GetRateLimitInfo(),
-	}
-
-	s.sendJSONResponse(w, response, http.StatusOK)
-}
-
-func (s *Server) handleUsers(w http.ResponseWriter, r *http.Request) {
-	if r.Method != "GET" {
-		s.sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
-	userID := r.URL.Query().Get("id")
-	if userID == "" {
-		s.sendError(w, "User ID required (id parameter)", http.StatusBadRequest)
-		return
-	}
-
-	if _, err := strconv.ParseUint(userID, 10, 64); err != nil {
-		s.sendError(w, "Invalid user ID format", http.StatusBadRequest)
-		return
-	}
-
-	profile, err := s.discord.GetUser(userID)
-	if err != nil {
-		log.Printf("❌ Kullanıcı getirme hatası: %v", err)
-		s.sendError(w, fmt.Sprintf("Kullanıcı bulunamadı: %v", err), http.StatusNotFound)
-		return
-	}
-
-	response := models.APIResponse{
-		Success:   true,
-		Data:      profile,
-		Timestamp: time.Now().UTC().Format(time.RFC3339),
-		RateLimit: s.discord.GetRateLimitInfo(),
-	}
-
-	s.sendJSONResponse(w, response, http.StatusOK)
-}
-
-func (s *Server) handleGuildByID(w http.ResponseWriter, r *http.Request) {
-	if r.Method != "GET" {
-		s.sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
-	path := r.URL.Path
-	guildID := path[len("/guilds/"):]
-
-	if guildID == "" {
-		s.sendError(w, "Guild ID required", http.StatusBadRequest)
-		return
-	}
-
-	guild, err := s.discord.GetGuild(guildID)
-	if err != nil {
-		log.Printf("❌ Guild getirme hatası: %v", err)
-		s.sendError(w, fmt.Sprintf("Guild bulunamadı: %v", err), http.StatusNotFound)
-		return
-	}
-
-	response := models.APIResponse{
-		Success:   true,
-		Data:      guild,
-		Timestamp: time.Now().UTC().Format(time.RFC3339),
-		RateLimit: s.discord.GetRateLimitInfo(),
-	}
-
-	s.sendJSONResponse(w, response, http.StatusOK)
-}
-
-func (s *Server) handleGuildMembers(w http.ResponseWriter, r *http.Request) {
-	if r.Method != "GET" {
-		s.sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
-	guildID := r.URL.Query().Get("guild_id")
-	if guildID == "" {
-		s.sendError(w, "Guild ID required (guild_id parameter)", http.StatusBadRequest)
-		return
-	}
-
-	limitStr := r.URL.Query().Get("limit")
-	limit := 1000
-	if limitStr != "" {
-		if val, err := strconv.Atoi(limitStr); err == nil && val > 0 {
-			limit = val
-		}
-	}
-
-	members, err := s.discord.GetGuildMembers(guildID, limit)
-	if err != nil {
-		log.Printf("❌ Guild üyeleri getirme hatası: %v", err)
-		s.sendError(w, fmt.Sprintf("Guild üyeleri getirilemedi: %v", err), http.StatusNotFound)
-		return
-	}
-
-	response := models.APIResponse{
-		Success:   true,
-		Data:      members,
-		Count:     len(members),
-		Timestamp: time.Now().UTC().Format(time.RFC3339),
-		RateLimit: s.discord.GetRateLimitInfo(),
-	}
-
-	s.sendJSONResponse(w, response, http.StatusOK)
-}
-
-func (s *Server) handleGuildRefresh(w http.ResponseWriter, r *http.Request) {
-	if r.Method != "POST" {
-		s.sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
-	guildID := r.URL.Query().Get("guild_id")
-	if guildID == "" {
-		s.sendError(w, "Guild ID required (guild_id parameter)", http.StatusBadRequest)
-		return
-	}
-
-	err := s.discord.RefreshGuild(guildID)
-	if err != nil {
-		log.Printf("❌ Guild yenileme hatası: %v", err)
-		s.sendError(w, fmt.Sprintf("Guild yenilenemedi: %v", err), http.StatusInternalServerError)
-		return
-	}
-
-	s.wsManager.BroadcastToGuild(guildID, "guild_refreshed", map[string]interface{}{
-		"guild_id": guildID,
-		"message":  "Guild başarıyla yenilendi",
-	})
-
-	response := models.APIResponse{
-		Success:   true,
-		Message:   fmt.Sprintf("Guild başarıyla yenilendi: %s", guildID),
-		Timestamp: time.Now().UTC().Format(time.RFC3339),
-	}
-
-	s.sendJSONResponse(w, response, http.StatusOK)
-}
-
-func (s *Server) handleGuildMembersRefresh(w http.ResponseWriter, r *http.Request) {
-	if r.Method != "POST" {
-		s.sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
-	guildID := r.URL.Query().Get("guild_id")
-	if guildID == "" {
-		s.sendError(w, "Guild ID required (guild_id parameter)", http.StatusBadRequest)
-		return
-	}
-
-	limitStr := r.URL.Query().Get("limit")
-	limit := 1000
-	if limitStr != "" {
-		if val, err := strconv.Atoi(limitStr); err == nil && val > 0 {
-			limit = val
-		}
-	}
-
-	err := s.discord.RefreshGuildMembers(guildID, limit)
-	if err != nil {
-		log.Printf("❌ Guild üyeleri yenileme hatası: %v", err)
-		s.sendError(w, fmt.Sprintf("Guild üyeleri yenilenemedi: %v", err), http.StatusInternalServerError)
-		return
-	}
-
-	s.wsManager.BroadcastToGuild(guildID, "members_refreshed", map[string]interface{}{
-		"guild_id": guildID,
-		"limit":    limit,
-		"message":  "Guild üyeleri başarıyla yenilendi",
-	})
-
-	response := models.APIResponse{
-		Success:   true,
-		Message:   fmt.Sprintf("Guild üyeleri başarıyla yenilendi: %s", guildID),
-		Timestamp: time.Now().UTC().Format(time.RFC3339),
-	}
-
-	s.sendJSONResponse(w, response, http.StatusOK)
-}
-
-func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
-	if r.Method != "GET" {
-		s.sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
-	response := models.APIResponse{
-		Success: true,
-		Data: map[string]interface{}{
-			"status":    "healthy",
-			"uptime":    time.Since(time.Now()).String(),
-			"timestamp": time.Now().UTC().Format(time.RFC3339),
-		},
-		Timestamp: time.Now().UTC().Format(time.RFC3339),
-	}
-
-	s.sendJSONResponse(w, response, http.StatusOK)
-}
-
-func (s *Server) handleStats(w http.ResponseWriter, r *http.Request) {
-	if r.Method != "GET" {
-		s.sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
-	cacheStats := s.discord.GetCacheStats()
-	rateLimitInfo := s.discord.GetRateLimitInfo()
-	wsStats := s.wsManager.GetConnectedClientsInfo()
-
-	response := models.APIResponse{
-		Success: true,
-		Data: map[string]interface{}{
-			"cache": map[string]interface{}{
-				"hits":        cacheStats.Hits,
-				"misses":      cacheStats.Misses,
-				"evictions":   cacheStats.Evictions,
-				"refreshes":   cacheStats.Refreshes,
-				"size":        cacheStats.Size,
-				"last_cleanup": cacheStats.LastCleanup.Format(time.RFC3339),
-			},
-			"rate_limit": rateLimitInfo,
-			"websocket": map[string]interface{}{
-				"connected_clients": s.wsManager.GetConnectedClientsCount(),
-				"clients_info":      wsStats,
-			},
-			"server": map[string]interface{}{
-				"start_time": time.Now().UTC().Format(time.RFC3339),
-			},
-		},
-		Timestamp: time.Now().UTC().Format(time.RFC3339),
-	}
-
-	s.sendJSONResponse(w, response, http.StatusOK)
-}
-
-func (s *Server) handleCacheClear(w http.ResponseWriter, r *http.Request) {
-	if r.Method != "POST" {
-		s.sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
-	s.discord.ClearCache()
-
-	response := models.APIResponse{
-		Success:   true,
-		Message:   "Cache başarıyla temizlendi",
-		Timestamp: time.Now().UTC().Format(time.RFC3339),
-	}
-
-	s.sendJSONResponse(w, response, http.StatusOK)
-}
-
-func (s *Server) handleCacheStats(w http.ResponseWriter, r *http.Request) {
-	if r.Method != "GET" {
-		s.sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
-	cacheStats := s.discord.GetCacheStats()
-
-	response := models.APIResponse{
-		Success: true,
-		Data: map[string]interface{}{
-			"hits":         cacheStats.Hits,
-			"misses":       cacheStats.Misses,
-			"evictions":    cacheStats.Evictions,
-			"refreshes":    cacheStats.Refreshes,
-			"size":         cacheStats.Size,
-			"last_cleanup": cacheStats.LastCleanup.Format(time.RFC3339),
-		},
-		Timestamp: time.Now().UTC().Format(time.RFC3339),
-	}
-
-	s.sendJSONResponse(w, response, http.StatusOK)
-}
-
-func (s *Server) handleWebSocketStats(w http.ResponseWriter, r *http.Request) {
-	if r.Method != "GET" {
-		s.sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
-	response := models.APIResponse{
-		Success: true,
-		Data: map[string]interface{}{
-			"connected_clients": s.wsManager.GetConnectedClientsCount(),
-			"clients_info":      s.wsManager.GetConnectedClientsInfo(),
-		},
-		Timestamp: time.Now().UTC().Format(time.RFC3339),
-	}
-
-	s.sendJSONResponse(w, response, http.StatusOK)
-}
-
-func (s *Server) sendJSONResponse(w http.ResponseWriter, data interface{}, statusCode int) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(statusCode)
-	json.NewEncoder(w).Encode(data)
-}
-
-func (s *Server) sendError(w http.ResponseWriter, message string, statusCode int) {
-	response := models.APIResponse{
-		Success:   false,
-		Error:     message,
-		Timestamp: time.Now().UTC().Format(time.RFC3339),
-	}
-
-	s.sendJSONResponse(w, response, statusCode)
-} 
\ No newline at end of file
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"discord-user-api/cache"
+	"discord-user-api/config"
+	"discord-user-api/discord"
+	"discord-user-api/gateway"
+	"discord-user-api/middleware"
+	"discord-user-api/models"
+	"discord-user-api/snowflake"
+	"discord-user-api/websocket"
+)
+
+type Server struct {
+	config      *config.Config
+	discord     *discord.Client
+	cache       *cache.Cache
+	rateLimiter *middleware.RateLimiter
+	wsManager   *websocket.WebSocketManager
+	gateway     *gateway.Client
+	stopGateway context.CancelFunc
+}
+
+func NewServer(cfg *config.Config, discordClient *discord.Client, cache *cache.Cache) *Server {
+	rateLimiter := middleware.CreateRateLimiter(cfg)
+	wsManager := websocket.NewWebSocketManager()
+
+	server := &Server{
+		config:      cfg,
+		discord:     discordClient,
+		cache:       cache,
+		rateLimiter: rateLimiter,
+		wsManager:   wsManager,
+	}
+
+	cache.SetWebSocketManager(wsManager)
+	discordClient.SetWebSocketManager(wsManager)
+
+	go wsManager.Start()
+
+	cache.StartAutoRefresh()
+
+	if cfg.Discord.Gateway.Enabled {
+		ctx, cancel := context.WithCancel(context.Background())
+		server.gateway = gateway.NewClient(cfg, cache, wsManager)
+		server.gateway.SetTransport(discordClient.Transport())
+		server.stopGateway = cancel
+
+		go func() {
+			if err := server.gateway.Run(ctx); err != nil && !errors.Is(err, context.Canceled) {
+				log.Printf("❌ Discord Gateway durdu: %v", err)
+			}
+		}()
+	}
+
+	log.Printf("🚀 HTTP Server başlatıldı")
+	return server
+}
+
+func (s *Server) Stop() {
+	if s.stopGateway != nil {
+		s.stopGateway()
+	}
+}
+
+func (s *Server) Start() error {
+	middlewareChain := middleware.Compose(
+		middleware.Recovery,
+		middleware.Security,
+		middleware.RequestID,
+		middleware.Logging,
+		middleware.CORS,
+	)
+
+	if s.rateLimiter != nil {
+		middlewareChain = middleware.Compose(
+			middleware.Recovery,
+			middleware.Security,
+			middleware.RequestID,
+			middleware.Logging,
+			middleware.CORS,
+			middleware.RateLimit(s.rateLimiter),
+		)
+	}
+
+	http.HandleFunc("/", middlewareChain(s.handleRoot))
+	http.HandleFunc("/guilds", middlewareChain(s.handleGuilds))
+	http.HandleFunc("/users", middlewareChain(s.handleUsers))
+	http.HandleFunc("/guilds/", middlewareChain(s.handleGuildByID))
+	http.HandleFunc("/guilds/members", middlewareChain(s.handleGuildMembers))
+	http.HandleFunc("/guilds/refresh", middlewareChain(s.handleGuildRefresh))
+	http.HandleFunc("/guilds/members/refresh", middlewareChain(s.handleGuildMembersRefresh))
+	http.HandleFunc("/guilds/{guild_id}/channels", middlewareChain(s.handleGuildChannels))
+	http.HandleFunc("/guilds/{guild_id}/channels/refresh", middlewareChain(s.handleGuildChannelsRefresh))
+	http.HandleFunc("/guilds/{guild_id}/members/{user_id}/permissions", middlewareChain(s.handleMemberPermissions))
+	http.HandleFunc("/guilds/{guild_id}/audit-logs", middlewareChain(s.handleGuildAuditLog))
+	http.HandleFunc("/guilds/{guild_id}/roles", middlewareChain(s.handleGuildRoles))
+	http.HandleFunc("/guilds/{guild_id}/emojis", middlewareChain(s.handleGuildEmojis))
+	http.HandleFunc("/guilds/{guild_id}/stickers", middlewareChain(s.handleGuildStickers))
+	http.HandleFunc("/guilds/{guild_id}/bans", middlewareChain(s.handleGuildBans))
+	http.HandleFunc("/guilds/{guild_id}/invites", middlewareChain(s.handleGuildInvites))
+	http.HandleFunc("/channels/{channel_id}", middlewareChain(s.handleChannelByID))
+	http.HandleFunc("/channels/{channel_id}/refresh", middlewareChain(s.handleChannelRefresh))
+	http.HandleFunc("/channels/{channel_id}/messages", middlewareChain(s.handleChannelMessages))
+	http.HandleFunc("/channels/{channel_id}/messages/export", middlewareChain(s.handleChannelMessagesExport))
+	http.HandleFunc("/health", middlewareChain(s.handleHealth))
+	http.HandleFunc("/stats", middlewareChain(s.handleStats))
+	http.HandleFunc("/cache/clear", middlewareChain(s.handleCacheClear))
+	http.HandleFunc("/cache/stats", middlewareChain(s.handleCacheStats))
+	http.HandleFunc("/websocket", s.wsManager.HandleWebSocket)
+	http.HandleFunc("/websocket/stats", middlewareChain(s.handleWebSocketStats))
+
+	server := &http.Server{
+		Addr:         s.config.Server.Host + ":" + s.config.Server.Port,
+		ReadTimeout:  s.config.Server.ReadTimeout,
+		WriteTimeout: s.config.Server.WriteTimeout,
+		IdleTimeout:  s.config.Server.IdleTimeout,
+	}
+
+	log.Printf("🌐 Server başlatılıyor: http://%s:%s", s.config.Server.Host, s.config.Server.Port)
+	log.Printf("📋 Endpoints:")
+	log.Printf("   GET  /                    - API bilgileri")
+	log.Printf("   GET  /guilds              - Tüm guild'ler")
+	log.Printf("   GET  /guilds?id=<id>      - Belirli guild")
+	log.Printf("   GET  /users?id=<id>       - Kullanıcı profili")
+	log.Printf("   GET  /guilds/members?guild_id=<id>&limit=<limit>&after=<user_id> - Guild üyeleri")
+	log.Printf("   GET  /guilds/members?guild_id=<id>&all=true - Tüm guild üyeleri")
+	log.Printf("   POST /guilds/refresh?guild_id=<id> - Guild'i yenile")
+	log.Printf("   POST /guilds/members/refresh?guild_id=<id>&limit=<limit> - Üyeleri yenile")
+	log.Printf("   GET  /guilds/<id>/channels - Guild kanalları")
+	log.Printf("   POST /guilds/<id>/channels/refresh - Guild kanallarını yenile")
+	log.Printf("   GET  /guilds/<id>/members/<user_id>/permissions?channel_id=<id>&check=<PERM,...> - Üye izinleri")
+	log.Printf("   GET  /channels/<id>       - Kanal bilgisi")
+	log.Printf("   POST /channels/<id>/refresh - Kanalı yenile")
+	log.Printf("   GET  /channels/<id>/messages?before=<id>&after=<id>&around=<id>&limit=<limit> - Kanal mesajları")
+	log.Printf("   GET  /channels/<id>/messages/export?before=<id>&after=<id>&max=<n> - Kanal geçmişini NDJSON olarak dışa aktar")
+	log.Printf("   GET  /health              - Sağlık kontrolü")
+	log.Printf("   GET  /stats               - İstatistikler")
+	log.Printf("   POST /cache/clear         - Cache temizle")
+	log.Printf("   GET  /cache/stats         - Cache istatistikleri")
+	log.Printf("   WS   /websocket           - WebSocket bağlantısı")
+	log.Printf("   GET  /websocket/stats     - WebSocket istatistikleri")
+	log.Printf("   ?include_urls=true&image_size=<size>&image_format=<format> - CDN URL'lerini ekle (guild, kullanıcı, üye)")
+
+	return server.ListenAndServe()
+}
+
+func (s *Server) handleRoot(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+
+	response := models.APIResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"name":        "Discord API Server - Professional Edition",
+			"version":     "2.0.0",
+			"description": "Profesyonel Discord API sunucusu - Gerçek zamanlı güncellemeler ile",
+			"features": []string{
+				"🔐 Güvenli API",
+				"💾 Akıllı Cache",
+				"🔄 Retry Logic",
+				"📊 İstatistikler",
+				"🛡️ Middleware",
+				"⚡ Performans",
+				"🎯 Türkçe Loglar",
+				"🔌 WebSocket Desteği",
+				"🔄 Otomatik Yenileme",
+			},
+			"endpoints": map[string]string{
+				"guilds":                 "/guilds",
+				"guilds_filter":          "/guilds?id=<guild_id>",
+				"users":                  "/users?id=<user_id>",
+				"guild_members":          "/guilds/members?guild_id=<guild_id>&limit=<limit>&after=<user_id>",
+				"guild_members_all":      "/guilds/members?guild_id=<guild_id>&all=true",
+				"guild_refresh":          "/guilds/refresh?guild_id=<guild_id>",
+				"members_refresh":        "/guilds/members/refresh?guild_id=<guild_id>&limit=<limit>",
+				"guild_channels":         "/guilds/<guild_id>/channels",
+				"guild_channels_refresh": "/guilds/<guild_id>/channels/refresh",
+				"member_permissions":     "/guilds/<guild_id>/members/<user_id>/permissions?channel_id=<channel_id>&check=<PERMISSION,...>",
+				"guild_roles":            "/guilds/<guild_id>/roles",
+				"guild_emojis":           "/guilds/<guild_id>/emojis",
+				"guild_stickers":         "/guilds/<guild_id>/stickers",
+				"guild_bans":             "/guilds/<guild_id>/bans?before=<user_id>&after=<user_id>&limit=<limit>",
+				"guild_invites":          "/guilds/<guild_id>/invites",
+				"guild_audit_log":        "/guilds/<guild_id>/audit-logs?user_id=<id>&action_type=<member_ban_add|22>&before=<id>&after=<id>&limit=<limit>",
+				"channel":                "/channels/<channel_id>",
+				"channel_refresh":        "/channels/<channel_id>/refresh",
+				"channel_messages":       "/channels/<channel_id>/messages?before=<id>&after=<id>&around=<id>&limit=<limit>",
+				"channel_export":         "/channels/<channel_id>/messages/export?before=<id>&after=<id>&max=<n>",
+				"health":                 "/health",
+				"stats":                  "/stats",
+				"cache_clear":            "/cache/clear",
+				"cache_stats":            "/cache/stats",
+				"websocket":              "/websocket",
+				"websocket_stats":        "/websocket/stats",
+				"asset_urls":             "?include_urls=true&image_size=<16-4096>&image_format=<png|jpg|webp|gif>",
+			},
+		},
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+	}
+
+	s.sendJSONResponse(w, response, http.StatusOK)
+}
+
+func (s *Server) handleGuilds(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		s.sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ctx, cacheStatus := discord.WithCacheStatus(r.Context())
+
+	assets, ok := s.parseAssetOptions(w, r)
+	if !ok {
+		return
+	}
+
+	if idParam := r.URL.Query().Get("id"); idParam != "" {
+		guildID, ok := s.parseSnowflake(w, idParam, "guild ID")
+		if !ok {
+			return
+		}
+
+		guild, err := s.discord.GetGuild(ctx, guildID)
+		if err != nil {
+			log.Printf("❌ Guild getirme hatası: %v", err)
+			s.sendUpstreamError(w, "Guild getirilemedi", err)
+			return
+		}
+
+		response := models.APIResponse{
+			Success:   true,
+			Data:      assets.apply(guildID, guild),
+			Timestamp: time.Now().UTC().Format(time.RFC3339),
+		}
+
+		s.applyCacheStatus(w, &response, cacheStatus)
+		s.sendJSONResponse(w, response, http.StatusOK)
+		return
+	}
+
+	guilds, err := s.discord.GetGuilds(ctx)
+	if err != nil {
+		log.Printf("❌ Guild'ler getirme hatası: %v", err)
+		s.sendUpstreamError(w, "Guild'ler getirilemedi", err)
+		return
+	}
+
+	response := models.APIResponse{
+		Success:   true,
+		Data:      assets.apply(0, guilds),
+		Count:     len(guilds),
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+		RateLimit: s.discord.GetRateLimitInfo(),
+	}
+
+	s.applyCacheStatus(w, &response, cacheStatus)
+	s.sendJSONResponse(w, response, http.StatusOK)
+}
+
+func (s *Server) handleUsers(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		s.sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ctx, cacheStatus := discord.WithCacheStatus(r.Context())
+
+	assets, ok := s.parseAssetOptions(w, r)
+	if !ok {
+		return
+	}
+
+	idParam := r.URL.Query().Get("id")
+	if idParam == "" {
+		s.sendError(w, "User ID required (id parameter)", http.StatusBadRequest)
+		return
+	}
+
+	userID, ok := s.parseSnowflake(w, idParam, "user ID")
+	if !ok {
+		return
+	}
+
+	profile, err := s.discord.GetUser(ctx, userID)
+	if err != nil {
+		log.Printf("❌ Kullanıcı getirme hatası: %v", err)
+		s.sendUpstreamError(w, "Kullanıcı getirilemedi", err)
+		return
+	}
+
+	response := models.APIResponse{
+		Success:   true,
+		Data:      assets.apply(0, profile),
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+		RateLimit: s.discord.GetRateLimitInfo(),
+	}
+
+	s.applyCacheStatus(w, &response, cacheStatus)
+	s.sendJSONResponse(w, response, http.StatusOK)
+}
+
+func (s *Server) handleGuildByID(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		s.sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ctx, cacheStatus := discord.WithCacheStatus(r.Context())
+
+	assets, ok := s.parseAssetOptions(w, r)
+	if !ok {
+		return
+	}
+
+	path := r.URL.Path
+	idParam := path[len("/guilds/"):]
+
+	if idParam == "" {
+		s.sendError(w, "Guild ID required", http.StatusBadRequest)
+		return
+	}
+
+	guildID, ok := s.parseSnowflake(w, idParam, "guild ID")
+	if !ok {
+		return
+	}
+
+	guild, err := s.discord.GetGuild(ctx, guildID)
+	if err != nil {
+		log.Printf("❌ Guild getirme hatası: %v", err)
+		s.sendUpstreamError(w, "Guild getirilemedi", err)
+		return
+	}
+
+	response := models.APIResponse{
+		Success:   true,
+		Data:      assets.apply(guildID, guild),
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+		RateLimit: s.discord.GetRateLimitInfo(),
+	}
+
+	s.applyCacheStatus(w, &response, cacheStatus)
+	s.sendJSONResponse(w, response, http.StatusOK)
+}
+
+func (s *Server) handleGuildMembers(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		s.sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ctx, cacheStatus := discord.WithCacheStatus(r.Context())
+
+	assets, ok := s.parseAssetOptions(w, r)
+	if !ok {
+		return
+	}
+
+	guildIDParam := r.URL.Query().Get("guild_id")
+	if guildIDParam == "" {
+		s.sendError(w, "Guild ID required (guild_id parameter)", http.StatusBadRequest)
+		return
+	}
+
+	guildID, ok := s.parseSnowflake(w, guildIDParam, "guild ID")
+	if !ok {
+		return
+	}
+
+	if r.URL.Query().Get("all") == "true" {
+		members, err := s.discord.GetAllGuildMembers(ctx, guildID)
+		if err != nil {
+			log.Printf("❌ Tüm guild üyeleri getirme hatası: %v", err)
+			s.sendUpstreamError(w, "Guild üyeleri getirilemedi", err)
+			return
+		}
+
+		response := models.APIResponse{
+			Success:    true,
+			Data:       assets.apply(guildID, members),
+			Count:      len(members),
+			Pagination: &models.Pagination{HasMore: false},
+			Timestamp:  time.Now().UTC().Format(time.RFC3339),
+			RateLimit:  s.discord.GetRateLimitInfo(),
+		}
+
+		s.applyCacheStatus(w, &response, cacheStatus)
+		s.sendJSONResponse(w, response, http.StatusOK)
+		return
+	}
+
+	limitStr := r.URL.Query().Get("limit")
+	limit := 1000
+	if limitStr != "" {
+		if val, err := strconv.Atoi(limitStr); err == nil && val > 0 && val <= 1000 {
+			limit = val
+		}
+	}
+
+	after, err := snowflake.ParseOptional(r.URL.Query().Get("after"))
+	if err != nil {
+		s.sendError(w, "Invalid after cursor format", http.StatusBadRequest)
+		return
+	}
+
+	members, err := s.discord.GetGuildMembersAfter(ctx, guildID, after, limit)
+	if err != nil {
+		log.Printf("❌ Guild üyeleri getirme hatası: %v", err)
+		s.sendUpstreamError(w, "Guild üyeleri getirilemedi", err)
+		return
+	}
+
+	pagination := &models.Pagination{
+		Limit:   limit,
+		After:   after.String(),
+		HasMore: len(members) == limit,
+	}
+	if pagination.HasMore {
+		pagination.Next = members[len(members)-1].User.ID.String()
+	}
+
+	response := models.APIResponse{
+		Success:    true,
+		Data:       assets.apply(guildID, members),
+		Count:      len(members),
+		Pagination: pagination,
+		Timestamp:  time.Now().UTC().Format(time.RFC3339),
+		RateLimit:  s.discord.GetRateLimitInfo(),
+	}
+
+	s.applyCacheStatus(w, &response, cacheStatus)
+	s.sendJSONResponse(w, response, http.StatusOK)
+}
+
+func (s *Server) handleGuildRefresh(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		s.sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	guildIDParam := r.URL.Query().Get("guild_id")
+	if guildIDParam == "" {
+		s.sendError(w, "Guild ID required (guild_id parameter)", http.StatusBadRequest)
+		return
+	}
+
+	guildID, ok := s.parseSnowflake(w, guildIDParam, "guild ID")
+	if !ok {
+		return
+	}
+
+	err := s.discord.RefreshGuild(r.Context(), guildID)
+	if err != nil {
+		log.Printf("❌ Guild yenileme hatası: %v", err)
+		s.sendUpstreamError(w, "Guild yenilenemedi", err)
+		return
+	}
+
+	s.wsManager.BroadcastToGuild(guildID.String(), "guild_refreshed", map[string]interface{}{
+		"guild_id": guildID,
+		"message":  "Guild başarıyla yenilendi",
+	})
+
+	response := models.APIResponse{
+		Success:   true,
+		Message:   fmt.Sprintf("Guild başarıyla yenilendi: %s", guildID),
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+	}
+
+	s.sendJSONResponse(w, response, http.StatusOK)
+}
+
+func (s *Server) handleGuildMembersRefresh(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		s.sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	guildIDParam := r.URL.Query().Get("guild_id")
+	if guildIDParam == "" {
+		s.sendError(w, "Guild ID required (guild_id parameter)", http.StatusBadRequest)
+		return
+	}
+
+	guildID, ok := s.parseSnowflake(w, guildIDParam, "guild ID")
+	if !ok {
+		return
+	}
+
+	limitStr := r.URL.Query().Get("limit")
+	limit := 1000
+	if limitStr != "" {
+		if val, err := strconv.Atoi(limitStr); err == nil && val > 0 {
+			limit = val
+		}
+	}
+
+	err := s.discord.RefreshGuildMembers(r.Context(), guildID, limit)
+	if err != nil {
+		log.Printf("❌ Guild üyeleri yenileme hatası: %v", err)
+		s.sendUpstreamError(w, "Guild üyeleri yenilenemedi", err)
+		return
+	}
+
+	s.wsManager.BroadcastToGuild(guildID.String(), "members_refreshed", map[string]interface{}{
+		"guild_id": guildID,
+		"limit":    limit,
+		"message":  "Guild üyeleri başarıyla yenilendi",
+	})
+
+	response := models.APIResponse{
+		Success:   true,
+		Message:   fmt.Sprintf("Guild üyeleri başarıyla yenilendi: %s", guildID),
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+	}
+
+	s.sendJSONResponse(w, response, http.StatusOK)
+}
+
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		s.sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	data := map[string]interface{}{
+		"status":    "healthy",
+		"uptime":    time.Since(time.Now()).String(),
+		"timestamp": time.Now().UTC().Format(time.RFC3339),
+	}
+
+	if s.gateway != nil {
+		data["gateway"] = s.gateway.Stats().State
+	}
+
+	circuits := s.discord.GetCircuitStates()
+	if len(circuits) > 0 {
+		data["circuit_breakers"] = circuits
+		for _, state := range circuits {
+			if state != discord.CircuitClosed {
+				data["status"] = "degraded"
+				break
+			}
+		}
+	}
+
+	response := models.APIResponse{
+		Success:   true,
+		Data:      data,
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+	}
+
+	s.sendJSONResponse(w, response, http.StatusOK)
+}
+
+func (s *Server) handleStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		s.sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	cacheStats := s.discord.GetCacheStats()
+	rateLimitInfo := s.discord.GetRateLimitInfo()
+	wsStats := s.wsManager.GetConnectedClientsInfo()
+
+	var gatewayStats interface{}
+	if s.gateway != nil {
+		gatewayStats = s.gateway.Stats()
+	}
+
+	response := models.APIResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"cache": map[string]interface{}{
+				"hits":           cacheStats.Hits,
+				"misses":         cacheStats.Misses,
+				"evictions":      cacheStats.Evictions,
+				"refreshes":      cacheStats.Refreshes,
+				"refresh_errors": cacheStats.RefreshErrors,
+				"stale_hits":     cacheStats.StaleHits,
+				"revalidations":  cacheStats.Revalidations,
+				"loaders":        cacheStats.Loaders,
+				"size":           cacheStats.Size,
+				"last_cleanup":   cacheStats.LastCleanup.Format(time.RFC3339),
+				"store":          cacheStats.Store,
+				"store_errors":   cacheStats.StoreErrors,
+				"shards":         cacheStats.Shards,
+				"eviction":       cacheStats.Eviction,
+			},
+			"rate_limit":       rateLimitInfo,
+			"coalescing":       s.discord.GetCoalesceStats(),
+			"retries":          s.discord.GetRetryStats(),
+			"circuit_breakers": s.discord.GetCircuitStats(),
+			"recording":        s.discord.GetRecorderStats(),
+			"tokens":           s.discord.GetTokenStats(),
+			"gateway":          gatewayStats,
+			"websocket": map[string]interface{}{
+				"connected_clients": s.wsManager.GetConnectedClientsCount(),
+				"clients_info":      wsStats,
+			},
+			"server": map[string]interface{}{
+				"start_time": time.Now().UTC().Format(time.RFC3339),
+			},
+		},
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+	}
+
+	s.sendJSONResponse(w, response, http.StatusOK)
+}
+
+func (s *Server) handleCacheClear(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		s.sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	s.discord.ClearCache()
+
+	response := models.APIResponse{
+		Success:   true,
+		Message:   "Cache başarıyla temizlendi",
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+	}
+
+	s.sendJSONResponse(w, response, http.StatusOK)
+}
+
+func (s *Server) handleCacheStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		s.sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	cacheStats := s.discord.GetCacheStats()
+
+	response := models.APIResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"hits":           cacheStats.Hits,
+			"misses":         cacheStats.Misses,
+			"evictions":      cacheStats.Evictions,
+			"refreshes":      cacheStats.Refreshes,
+			"refresh_errors": cacheStats.RefreshErrors,
+			"stale_hits":     cacheStats.StaleHits,
+			"revalidations":  cacheStats.Revalidations,
+			"loaders":        cacheStats.Loaders,
+			"size":           cacheStats.Size,
+			"last_cleanup":   cacheStats.LastCleanup.Format(time.RFC3339),
+			"store":          cacheStats.Store,
+			"store_errors":   cacheStats.StoreErrors,
+			"shards":         cacheStats.Shards,
+			"eviction":       cacheStats.Eviction,
+		},
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+	}
+
+	s.sendJSONResponse(w, response, http.StatusOK)
+}
+
+func (s *Server) handleWebSocketStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		s.sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	response := models.APIResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"connected_clients": s.wsManager.GetConnectedClientsCount(),
+			"clients_info":      s.wsManager.GetConnectedClientsInfo(),
+		},
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+	}
+
+	s.sendJSONResponse(w, response, http.StatusOK)
+}
+
+func (s *Server) parseSnowflake(w http.ResponseWriter, value, name string) (snowflake.Snowflake, bool) {
+	id, err := snowflake.Parse(value)
+	if err != nil {
+		s.sendError(w, fmt.Sprintf("Invalid %s format", name), http.StatusBadRequest)
+		return 0, false
+	}
+	return id, true
+}
+
+func (s *Server) sendJSONResponse(w http.ResponseWriter, data interface{}, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
+
+func (s *Server) sendError(w http.ResponseWriter, message string, statusCode int) {
+	response := models.APIResponse{
+		Success:   false,
+		Error:     message,
+		ErrorCode: errorCodeForStatus(statusCode),
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+	}
+
+	s.sendJSONResponse(w, response, statusCode)
+}
+
+func (s *Server) sendUpstreamError(w http.ResponseWriter, message string, err error) {
+	statusCode, errorCode := classifyError(err)
+
+	response := models.APIResponse{
+		Success:   false,
+		Error:     fmt.Sprintf("%s: %v", message, err),
+		ErrorCode: errorCode,
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+	}
+
+	var apiErr *discord.APIError
+	if errors.As(err, &apiErr) {
+		response.DiscordError = &models.DiscordError{
+			StatusCode: apiErr.StatusCode,
+			Code:       apiErr.Code,
+			Message:    apiErr.Message,
+			Errors:     apiErr.Errors,
+		}
+	}
+
+	var rateLimitErr *discord.RateLimitedError
+	if errors.As(err, &rateLimitErr) && rateLimitErr.RetryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(rateLimitErr.RetryAfter.Seconds()))))
+	}
+
+	var circuitErr *discord.CircuitOpenError
+	if errors.As(err, &circuitErr) && circuitErr.RetryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(circuitErr.RetryAfter.Seconds()))))
+	}
+
+	s.sendJSONResponse(w, response, statusCode)
+}
+
+func (s *Server) applyCacheStatus(w http.ResponseWriter, response *models.APIResponse, status *discord.CacheStatus) {
+	if !status.Stale() {
+		return
+	}
+
+	age := status.Age().Round(time.Second)
+	response.Stale = true
+	response.CacheAge = age.String()
+
+	w.Header().Set("Age", strconv.Itoa(int(age.Seconds())))
+	if status.LastError() != nil {
+		w.Header().Set("Warning", `111 - "Revalidation Failed"`)
+	} else {
+		w.Header().Set("Warning", `110 - "Response is Stale"`)
+	}
+}
+
+func classifyError(err error) (int, string) {
+	var (
+		unauthorizedErr *discord.UnauthorizedError
+		forbiddenErr    *discord.ForbiddenError
+		notFoundErr     *discord.NotFoundError
+		rateLimitErr    *discord.RateLimitedError
+		circuitErr      *discord.CircuitOpenError
+		upstreamErr     *discord.UpstreamError
+		decodeErr       *discord.DecodeError
+		apiErr          *discord.APIError
+	)
+
+	switch {
+	case errors.Is(err, discord.ErrNoHealthyToken):
+		return http.StatusServiceUnavailable, "no_healthy_token"
+	case errors.Is(err, discord.ErrFixtureNotFound):
+		return http.StatusServiceUnavailable, "fixture_not_found"
+	case errors.As(err, &circuitErr):
+		return http.StatusServiceUnavailable, circuitErr.ErrorCode()
+	case errors.As(err, &unauthorizedErr):
+		return http.StatusBadGateway, unauthorizedErr.ErrorCode()
+	case errors.As(err, &forbiddenErr):
+		return http.StatusForbidden, forbiddenErr.ErrorCode()
+	case errors.As(err, &notFoundErr):
+		return http.StatusNotFound, notFoundErr.ErrorCode()
+	case errors.As(err, &rateLimitErr):
+		return http.StatusTooManyRequests, rateLimitErr.ErrorCode()
+	case errors.As(err, &decodeErr):
+		return http.StatusBadGateway, decodeErr.ErrorCode()
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout, "upstream_timeout"
+	case errors.Is(err, context.Canceled):
+		return http.StatusRequestTimeout, "request_cancelled"
+	case errors.As(err, &upstreamErr):
+		return http.StatusBadGateway, upstreamErr.ErrorCode()
+	case errors.As(err, &apiErr):
+		if apiErr.StatusCode >= 400 && apiErr.StatusCode < 500 {
+			return apiErr.StatusCode, apiErr.ErrorCode()
+		}
+		return http.StatusBadGateway, apiErr.ErrorCode()
+	}
+
+	return http.StatusInternalServerError, "internal_error"
+}
+
+func errorCodeForStatus(statusCode int) string {
+	switch statusCode {
+	case http.StatusBadRequest:
+		return "bad_request"
+	case http.StatusNotFound:
+		return "not_found"
+	case http.StatusMethodNotAllowed:
+		return "method_not_allowed"
+	case http.StatusTooManyRequests:
+		return "rate_limited"
+	case http.StatusInternalServerError:
+		return "internal_error"
+	}
+	return strings.ReplaceAll(strings.ToLower(http.StatusText(statusCode)), " ", "_")
+}
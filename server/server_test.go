@@ -0,0 +1,255 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"discord-user-api/cache"
+	"discord-user-api/config"
+	"discord-user-api/discord"
+	"discord-user-api/discordtest"
+	"discord-user-api/models"
+	"discord-user-api/permissions"
+	"discord-user-api/snowflake"
+)
+
+func TestMain(m *testing.M) {
+	log.SetOutput(io.Discard)
+	os.Exit(m.Run())
+}
+
+func TestCacheStatsEvictionsAddUpAcrossShards(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Cache.Enabled = true
+	cfg.Discord.APIURL = "http://127.0.0.1:1/api"
+	cfg.Discord.Tokens = []string{"token"}
+
+	store := cache.NewCache(64, time.Minute, time.Hour)
+	defer store.Stop()
+	store.SetShards(4)
+	if err := store.SetEvictionPolicy(cache.EvictionLFU); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 200; i++ {
+		store.Set(fmt.Sprintf("key_%d", i), i)
+	}
+
+	client, err := discord.NewClient(cfg, store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewServer(cfg, client, store)
+	defer s.Stop()
+	defer store.StopAutoRefresh()
+
+	recorder := httptest.NewRecorder()
+	s.handleCacheStats(recorder, httptest.NewRequest(http.MethodGet, "/cache/stats", nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("status = %d", recorder.Code)
+	}
+
+	var response struct {
+		Data struct {
+			Evictions int64               `json:"evictions"`
+			Size      int                 `json:"size"`
+			Shards    int                 `json:"shards"`
+			Eviction  cache.EvictionStats `json:"eviction"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(recorder.Body).Decode(&response); err != nil {
+		t.Fatal(err)
+	}
+
+	stats := response.Data
+	if stats.Shards != 4 || stats.Eviction.Policy != cache.EvictionLFU || stats.Eviction.Capacity != 64 {
+		t.Errorf("stats = %+v", stats)
+	}
+	if stats.Size != 64 || stats.Eviction.Tracked != stats.Size {
+		t.Errorf("size = %d, tracked = %d, want 64", stats.Size, stats.Eviction.Tracked)
+	}
+	if stats.Evictions != 200-64 || stats.Eviction.Evictions != stats.Evictions {
+		t.Errorf("evictions = %d, policy evictions = %d, want %d", stats.Evictions, stats.Eviction.Evictions, 200-64)
+	}
+}
+
+// newTestServer wires a Server to a discordtest fake; configure, if set, runs
+// before the client is built.
+func newTestServer(t *testing.T, seed discordtest.Seed, configure func(*config.Config)) (*Server, *discordtest.Server) {
+	t.Helper()
+
+	upstream := discordtest.NewServer(seed)
+	t.Cleanup(upstream.Close)
+
+	cfg := &config.Config{}
+	cfg.Cache.Enabled = true
+	cfg.Cache.TTL = time.Minute
+	cfg.Discord.RequestTimeout = time.Second
+	upstream.ConfigureClient(cfg)
+	if configure != nil {
+		configure(cfg)
+	}
+
+	store := cache.NewCache(100, cfg.Cache.TTL, time.Hour)
+	store.SetStaleTTL(cfg.Cache.StaleTTL)
+	client, err := discord.NewClient(cfg, store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewServer(cfg, client, store)
+	t.Cleanup(func() {
+		s.Stop()
+		store.StopAutoRefresh()
+		store.Stop()
+	})
+	return s, upstream
+}
+
+func TestMemberPermissionsChannel(t *testing.T) {
+	seed := discordtest.DefaultSeed()
+	guildID := seed.Guilds[0].ID
+	alice := seed.Members[guildID][1]
+	base := time.Date(2024, 1, 1, 1, 0, 0, 0, time.UTC)
+
+	announcements := models.DiscordChannel{
+		ID:      snowflake.FromTime(base),
+		Type:    models.ChannelTypeGuildText,
+		GuildID: guildID,
+		Name:    "announcements",
+		PermissionOverwrites: []models.PermissionOverwrite{
+			{ID: guildID, Type: models.PermissionOverwriteTypeRole, Deny: permissions.SendMessages.String()},
+		},
+	}
+	thread := models.DiscordChannel{
+		ID:       snowflake.FromTime(base.Add(time.Minute)),
+		Type:     models.ChannelTypePublicThread,
+		GuildID:  guildID,
+		Name:     "discussion",
+		ParentID: announcements.ID,
+	}
+	seed.Channels = append(seed.Channels, announcements, thread)
+
+	everyone, err := permissions.Parse(seed.Guilds[0].Roles[0].Permissions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	readOnly, err := permissions.Channel(&seed.Guilds[0], &alice, &announcements)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if readOnly.Has(permissions.SendMessages) || !readOnly.Has(permissions.ViewChannel) {
+		t.Fatalf("announcements = %v", readOnly.Names())
+	}
+
+	tests := []struct {
+		name       string
+		channelID  string
+		wantStatus int
+		want       permissions.Permission
+	}{
+		{"no channel uses base permissions", "", http.StatusOK, everyone},
+		{"channel overwrites apply", announcements.ID.String(), http.StatusOK, readOnly},
+		{"thread uses parent permissions", thread.ID.String(), http.StatusOK, readOnly},
+		{"missing channel", snowflake.FromTime(base.Add(time.Hour)).String(), http.StatusNotFound, 0},
+		{"bad channel id", "general", http.StatusBadRequest, 0},
+	}
+
+	s, _ := newTestServer(t, seed, nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := "/guilds/" + guildID.String() + "/members/" + alice.User.ID.String() + "/permissions"
+			if tt.channelID != "" {
+				target += "?channel_id=" + tt.channelID
+			}
+			req := httptest.NewRequest(http.MethodGet, target, nil)
+			req.SetPathValue("guild_id", guildID.String())
+			req.SetPathValue("user_id", alice.User.ID.String())
+
+			recorder := httptest.NewRecorder()
+			s.handleMemberPermissions(recorder, req)
+			if recorder.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", recorder.Code, tt.wantStatus, recorder.Body)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var response struct {
+				Data struct {
+					Permissions string `json:"permissions"`
+				} `json:"data"`
+			}
+			if err := json.NewDecoder(recorder.Body).Decode(&response); err != nil {
+				t.Fatal(err)
+			}
+			if got, _ := permissions.Parse(response.Data.Permissions); got != tt.want {
+				t.Errorf("permissions = %v, want %v", got.Names(), tt.want.Names())
+			}
+		})
+	}
+}
+
+func TestStaleGuildServedWhenUpstreamFails(t *testing.T) {
+	seed := discordtest.DefaultSeed()
+	guild := seed.Guilds[0]
+	s, upstream := newTestServer(t, seed, func(cfg *config.Config) {
+		cfg.Cache.TTL = 10 * time.Millisecond
+		cfg.Cache.StaleTTL = time.Hour
+		cfg.Cache.AutoRefresh = false
+	})
+
+	get := func() *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		s.handleGuilds(recorder, httptest.NewRequest(http.MethodGet, "/guilds?id="+guild.ID.String(), nil))
+		return recorder
+	}
+
+	if recorder := get(); recorder.Code != http.StatusOK || recorder.Header().Get("Warning") != "" {
+		t.Fatalf("status = %d, warning = %q", recorder.Code, recorder.Header().Get("Warning"))
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	upstream.InjectFault(discordtest.Fault{Path: "/guilds/" + guild.ID.String(), Status: http.StatusBadGateway})
+
+	recorder := get()
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", recorder.Code, recorder.Body)
+	}
+	if got := recorder.Header().Get("Warning"); got != `111 - "Revalidation Failed"` {
+		t.Errorf("Warning = %q", got)
+	}
+	if got := recorder.Header().Get("Age"); got != "0" {
+		t.Errorf("Age = %q, want 0", got)
+	}
+
+	var response struct {
+		Stale    bool   `json:"stale"`
+		CacheAge string `json:"cache_age"`
+		Data     struct {
+			Name string `json:"name"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(recorder.Body).Decode(&response); err != nil {
+		t.Fatal(err)
+	}
+	if !response.Stale || response.CacheAge == "" || response.Data.Name != guild.Name {
+		t.Errorf("response = %+v", response)
+	}
+	if got := upstream.RequestCount(); got != 2 {
+		t.Errorf("requests = %d, want 2", got)
+	}
+
+	// Without a cached copy the same failure reaches the caller.
+	upstream.InjectFault(discordtest.Fault{Path: "/guilds/" + seed.Guilds[1].ID.String(), Status: http.StatusBadGateway})
+	recorder = httptest.NewRecorder()
+	s.handleGuilds(recorder, httptest.NewRequest(http.MethodGet, "/guilds?id="+seed.Guilds[1].ID.String(), nil))
+	if recorder.Code != http.StatusBadGateway {
+		t.Errorf("status = %d, want 502", recorder.Code)
+	}
+}
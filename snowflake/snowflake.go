@@ -0,0 +1,128 @@
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+const DiscordEpoch int64 = 1420070400000
+
+var ErrInvalid = errors.New("geçersiz snowflake ID")
+
+type Snowflake uint64
+
+func Parse(value string) (Snowflake, error) {
+	if value == "" {
+		return 0, fmt.Errorf("%w: boş değer", ErrInvalid)
+	}
+
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return 0, fmt.Errorf("%w: %q", ErrInvalid, value)
+		}
+	}
+
+	id, err := strconv.ParseUint(value, 10, 64)
+	if err != nil || id == 0 {
+		return 0, fmt.Errorf("%w: %q", ErrInvalid, value)
+	}
+
+	return Snowflake(id), nil
+}
+
+func ParseOptional(value string) (Snowflake, error) {
+	if value == "" {
+		return 0, nil
+	}
+	return Parse(value)
+}
+
+func FromTime(t time.Time) Snowflake {
+	ms := t.UnixMilli() - DiscordEpoch
+	if ms < 0 {
+		return 0
+	}
+	return Snowflake(uint64(ms) << 22)
+}
+
+func (s Snowflake) IsValid() bool {
+	return s != 0
+}
+
+func (s Snowflake) String() string {
+	if s == 0 {
+		return ""
+	}
+	return strconv.FormatUint(uint64(s), 10)
+}
+
+func (s Snowflake) Timestamp() int64 {
+	return int64(uint64(s)>>22) + DiscordEpoch
+}
+
+func (s Snowflake) Time() time.Time {
+	return time.UnixMilli(s.Timestamp()).UTC()
+}
+
+func (s Snowflake) CreatedAt() string {
+	if s == 0 {
+		return ""
+	}
+	return s.Time().Format(time.RFC3339Nano)
+}
+
+func (s Snowflake) WorkerID() uint8 {
+	return uint8((uint64(s) >> 17) & 0x1F)
+}
+
+func (s Snowflake) ProcessID() uint8 {
+	return uint8((uint64(s) >> 12) & 0x1F)
+}
+
+func (s Snowflake) Increment() uint16 {
+	return uint16(uint64(s) & 0xFFF)
+}
+
+func (s Snowflake) MarshalJSON() ([]byte, error) {
+	if s == 0 {
+		return []byte("null"), nil
+	}
+	return []byte(strconv.Quote(s.String())), nil
+}
+
+// UnmarshalJSON reads null, "" and "0" as an unset ID, so it round-trips with
+// MarshalJSON writing 0 as null.
+func (s *Snowflake) UnmarshalJSON(data []byte) error {
+	value := string(data)
+	if len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"' {
+		value = value[1 : len(value)-1]
+	}
+
+	if value == "null" || value == "" || value == "0" {
+		*s = 0
+		return nil
+	}
+
+	id, err := Parse(value)
+	if err != nil {
+		return err
+	}
+
+	*s = id
+	return nil
+}
+
+func (s Snowflake) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+func (s *Snowflake) UnmarshalText(data []byte) error {
+	id, err := ParseOptional(string(data))
+	if err != nil {
+		return err
+	}
+	*s = id
+	return nil
+}
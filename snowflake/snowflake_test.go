@@ -0,0 +1,129 @@
+package snowflake
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    Snowflake
+		wantErr bool
+	}{
+		{"175928847299117063", 175928847299117063, false},
+		{"1", 1, false},
+		{"", 0, true},
+		{"0", 0, true},
+		{"-1", 0, true},
+		{"12a", 0, true},
+		{" 1", 0, true},
+		{"18446744073709551616", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := Parse(tt.value)
+		if tt.wantErr {
+			if !errors.Is(err, ErrInvalid) {
+				t.Errorf("Parse(%q) err = %v, want ErrInvalid", tt.value, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("Parse(%q) = %d, %v, want %d", tt.value, got, err, tt.want)
+		}
+	}
+
+	if id, err := ParseOptional(""); err != nil || id != 0 {
+		t.Errorf("ParseOptional(\"\") = %d, %v", id, err)
+	}
+}
+
+func TestFields(t *testing.T) {
+	// The example from Discord's API reference.
+	id := Snowflake(175928847299117063)
+
+	if got := id.Timestamp(); got != 1462015105796 {
+		t.Errorf("Timestamp = %d", got)
+	}
+	if got := id.Time(); !got.Equal(time.UnixMilli(1462015105796)) {
+		t.Errorf("Time = %v", got)
+	}
+	if got := id.CreatedAt(); got != "2016-04-30T11:18:25.796Z" {
+		t.Errorf("CreatedAt = %q", got)
+	}
+	if id.WorkerID() != 1 || id.ProcessID() != 0 || id.Increment() != 7 {
+		t.Errorf("worker = %d, process = %d, increment = %d", id.WorkerID(), id.ProcessID(), id.Increment())
+	}
+
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	if got := FromTime(created).Time(); !got.Equal(created) {
+		t.Errorf("FromTime round trip = %v", got)
+	}
+	if got := FromTime(time.Unix(0, 0)); got != 0 {
+		t.Errorf("FromTime before epoch = %d", got)
+	}
+}
+
+func TestJSON(t *testing.T) {
+	type payload struct {
+		ID Snowflake `json:"id"`
+	}
+
+	tests := []struct {
+		input string
+		want  Snowflake
+		out   string
+	}{
+		{`{"id":"175928847299117063"}`, 175928847299117063, `{"id":"175928847299117063"}`},
+		{`{"id":175928847299117063}`, 175928847299117063, `{"id":"175928847299117063"}`},
+		{`{"id":null}`, 0, `{"id":null}`},
+		{`{"id":""}`, 0, `{"id":null}`},
+		{`{"id":"0"}`, 0, `{"id":null}`},
+		{`{"id":0}`, 0, `{"id":null}`},
+	}
+
+	for _, tt := range tests {
+		var decoded payload
+		if err := json.Unmarshal([]byte(tt.input), &decoded); err != nil {
+			t.Errorf("Unmarshal(%s): %v", tt.input, err)
+			continue
+		}
+		if decoded.ID != tt.want {
+			t.Errorf("Unmarshal(%s) = %d, want %d", tt.input, decoded.ID, tt.want)
+		}
+
+		encoded, err := json.Marshal(decoded)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(encoded) != tt.out {
+			t.Errorf("Marshal(%s) = %s, want %s", tt.input, encoded, tt.out)
+		}
+	}
+
+	var decoded payload
+	if err := json.Unmarshal([]byte(`{"id":"abc"}`), &decoded); !errors.Is(err, ErrInvalid) {
+		t.Errorf("err = %v, want ErrInvalid", err)
+	}
+}
+
+func TestText(t *testing.T) {
+	var decoded map[Snowflake]int
+	if err := json.Unmarshal([]byte(`{"175928847299117063":1}`), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded[175928847299117063] != 1 {
+		t.Errorf("decoded = %v", decoded)
+	}
+
+	encoded, err := json.Marshal(decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(encoded) != `{"175928847299117063":1}` {
+		t.Errorf("encoded = %s", encoded)
+	}
+}